@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -35,19 +36,39 @@ const (
 	yearFlag      = "year"
 	startDateFlag = "start-date"
 	endDateFlag   = "end-date"
+	pruneFlag     = "prune"
+	dryRunFlag    = "dry-run"
+	outputFlag    = "output"
 )
 
 func init() {
+	addDateFlags(rootCmd)
+	rootCmd.Flags().String(
+		pruneFlag,
+		"",
+		"Archive rows of transactions the provider no longer lists: dry-run to only list them, or archive",
+	)
+	rootCmd.Flags().Bool(dryRunFlag, false, "Print the planned sheet changes without writing them")
+	rootCmd.Flags().StringP(
+		outputFlag,
+		"o",
+		string(outputFormatTable),
+		"Format of the run report or dry-run plan: table, json or csv",
+	)
+}
+
+// addDateFlags adds the flags selecting the date range, which defaults to the current month.
+func addDateFlags(cmd *cobra.Command) {
 	now := time.Now()
 
-	rootCmd.Flags().IntP(monthFlag, "m", int(now.Month()), "Month (1-12)")
-	rootCmd.Flags().IntP(yearFlag, "y", now.Year(), "Year (YYYY)")
+	cmd.Flags().IntP(monthFlag, "m", int(now.Month()), "Month (1-12)")
+	cmd.Flags().IntP(yearFlag, "y", now.Year(), "Year (YYYY)")
 
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
 	endOfMonth := startOfMonth.AddDate(0, 1, 0).Add(-time.Nanosecond)
 
-	rootCmd.Flags().TimeP(startDateFlag, "s", startOfMonth, timeFormats, "Start date")
-	rootCmd.Flags().TimeP(endDateFlag, "e", endOfMonth, timeFormats, "End date")
+	cmd.Flags().TimeP(startDateFlag, "s", startOfMonth, timeFormats, "Start date")
+	cmd.Flags().TimeP(endDateFlag, "e", endOfMonth, timeFormats, "End date")
 }
 
 func Execute() error {
@@ -62,7 +83,7 @@ func Execute() error {
 var rootCmd = &cobra.Command{
 	Use:   "openfinance-to-sheets",
 	Short: "Sync Open Finance data to sheets through Pluggy",
-	Long:  "Sync Open Finance data to spreadsheet-style databases through Pluggy. Notion is the currently supported sheet provider.",
+	Long:  "Sync Open Finance data to spreadsheet-style databases through Pluggy. Each ingest profile writes to Notion, Google Sheets, local CSV/XLSX files, or SQLite/Postgres.",
 	RunE:  run,
 }
 
@@ -76,35 +97,88 @@ func run(cmd *cobra.Command, _ []string) error {
 }
 
 func executeIngest(cmd *cobra.Command, ingestUseCase ingest.IngestExecutor) error {
-	monthVal, _ := cmd.Flags().GetInt(monthFlag)
-	yearVal, _ := cmd.Flags().GetInt(yearFlag)
-	startDateVal, _ := cmd.Flags().GetTime(startDateFlag)
-	endDateVal, _ := cmd.Flags().GetTime(endDateFlag)
-
-	if cmd.Flags().Changed(monthFlag) || cmd.Flags().Changed(yearFlag) {
-		month := time.Month(monthVal)
-		year := yearVal
+	pruneVal, _ := cmd.Flags().GetString(pruneFlag)
+	dryRunVal, _ := cmd.Flags().GetBool(dryRunFlag)
 
-		startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
-		endOfMonth := startOfMonth.AddDate(0, 1, 0).Add(-time.Nanosecond)
-
-		startDateVal = startOfMonth
-		endDateVal = endOfMonth
+	format, err := outputFormatFlagValue(cmd)
+	if err != nil {
+		return err
 	}
+	startDateVal, endDateVal := dateRangeFlagValues(cmd)
 
 	ctx := context.Background()
 
-	err := ingestUseCase.Execute(ctx, ingest.IngestInput{
+	output, err := ingestUseCase.Execute(ctx, ingest.IngestInput{
 		StartDate: startDateVal,
 		EndDate:   endDateVal,
+		Prune:     ingest.PruneMode(pruneVal),
+		DryRun:    dryRunVal,
 	})
 	if err != nil {
-		return fmt.Errorf("execute ingest: %w", err)
+		err = fmt.Errorf("execute ingest: %w", err)
+		// A failed run still reports the work done before it stopped.
+		if len(output.Reports) > 0 {
+			if reportErr := writeReport(cmd.OutOrStdout(), format, output.Reports); reportErr != nil {
+				return errors.Join(err, fmt.Errorf("print run report: %w", reportErr))
+			}
+		}
+
+		return err
+	}
+
+	if dryRunVal {
+		if err := writePlan(cmd.OutOrStdout(), format, output.Plan); err != nil {
+			return fmt.Errorf("print dry-run plan: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := writeReport(cmd.OutOrStdout(), format, output.Reports); err != nil {
+		return fmt.Errorf("print run report: %w", err)
+	}
+	if format != outputFormatTable {
+		return nil
 	}
 
-	if _, err := fmt.Println("Ingest completed successfully"); err != nil {
+	if _, err := fmt.Fprintln(cmd.OutOrStdout(), "Ingest completed successfully"); err != nil {
 		return fmt.Errorf("print success message: %w", err)
 	}
 
 	return nil
 }
+
+func outputFormatFlagValue(cmd *cobra.Command) (outputFormat, error) {
+	outputVal, _ := cmd.Flags().GetString(outputFlag)
+
+	format := outputFormat(outputVal)
+	if !format.isValid() {
+		return "", fmt.Errorf(
+			"unsupported output format %q (supported: %s, %s, %s)",
+			outputVal,
+			outputFormatTable,
+			outputFormatJSON,
+			outputFormatCSV,
+		)
+	}
+
+	return format, nil
+}
+
+// dateRangeFlagValues returns the range of the date flags, or the whole month when the month or
+// year flag is set.
+func dateRangeFlagValues(cmd *cobra.Command) (time.Time, time.Time) {
+	monthVal, _ := cmd.Flags().GetInt(monthFlag)
+	yearVal, _ := cmd.Flags().GetInt(yearFlag)
+	startDateVal, _ := cmd.Flags().GetTime(startDateFlag)
+	endDateVal, _ := cmd.Flags().GetTime(endDateFlag)
+
+	if cmd.Flags().Changed(monthFlag) || cmd.Flags().Changed(yearFlag) {
+		startOfMonth := time.Date(yearVal, time.Month(monthVal), 1, 0, 0, 0, 0, time.Local)
+		endOfMonth := startOfMonth.AddDate(0, 1, 0).Add(-time.Nanosecond)
+
+		return startOfMonth, endOfMonth
+	}
+
+	return startDateVal, endDateVal
+}
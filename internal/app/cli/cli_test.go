@@ -1,13 +1,16 @@
 package cli
 
 import (
+	"bytes"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/ingest"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/mockingest"
 )
@@ -18,6 +21,9 @@ func testCommand(startDate, endDate time.Time) *cobra.Command {
 	command.Flags().Int(yearFlag, startDate.Year(), "")
 	command.Flags().Time(startDateFlag, startDate, timeFormats, "")
 	command.Flags().Time(endDateFlag, endDate, timeFormats, "")
+	command.Flags().String(pruneFlag, "", "")
+	command.Flags().Bool(dryRunFlag, false, "")
+	command.Flags().String(outputFlag, string(outputFormatTable), "")
 
 	return command
 }
@@ -38,7 +44,7 @@ func TestExecuteIngestPropagatesIngestError(t *testing.T) {
 		Execute(mock.Anything, mock.MatchedBy(func(input ingest.IngestInput) bool {
 			return input.StartDate.Equal(startDate) && input.EndDate.Equal(endDate)
 		})).
-		Return(wantErr).
+		Return(ingest.IngestOutput{}, wantErr).
 		Once()
 
 	err := executeIngest(testCommand(startDate, endDate), ingestUseCase)
@@ -65,10 +71,334 @@ func TestExecuteIngestUsesMonthAndYearFlags(t *testing.T) {
 		Execute(mock.Anything, mock.MatchedBy(func(input ingest.IngestInput) bool {
 			return input.StartDate.Equal(wantStart) && input.EndDate.Equal(wantEnd)
 		})).
-		Return(nil).
+		Return(ingest.IngestOutput{}, nil).
 		Once()
 
 	if err := executeIngest(command, ingestUseCase); err != nil {
 		t.Fatalf("executeIngest() error = %v", err)
 	}
 }
+
+func TestExecuteIngestPassesPruneMode(t *testing.T) {
+	startDate := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, time.August, 31, 0, 0, 0, 0, time.UTC)
+	command := testCommand(startDate, endDate)
+	if err := command.Flags().Set(pruneFlag, "dry-run"); err != nil {
+		t.Fatalf("set prune flag: %v", err)
+	}
+
+	ingestUseCase := mockingest.NewMockIngest(t)
+	ingestUseCase.EXPECT().
+		Execute(mock.Anything, mock.MatchedBy(func(input ingest.IngestInput) bool {
+			return input.Prune == ingest.PruneModeDryRun
+		})).
+		Return(ingest.IngestOutput{}, nil).
+		Once()
+
+	if err := executeIngest(command, ingestUseCase); err != nil {
+		t.Fatalf("executeIngest() error = %v", err)
+	}
+}
+
+func TestExecuteIngestPrintsDryRunPlan(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	plan := []ingest.TablePlan{
+		{
+			IngestProfileID: "profile",
+			Title:           "Aug 2026",
+			Columns:         []string{"External ID"},
+			Insert: []entity.Transaction{
+				{ExternalID: "new", Name: "Store, Inc", Category: "Food", Amount: 10.5, Date: date},
+			},
+		},
+		{IngestProfileID: "profile", Title: "Sep 2026", Create: true, Columns: []string{"Name"}},
+	}
+	tests := map[string]string{
+		"table": "PROFILE TABLE ACTION COLUMN EXTERNAL ID NAME CATEGORY BUDGET GROUP AMOUNT DATE\n" +
+			"profile Aug 2026 ensure_column External ID\n" +
+			"profile Aug 2026 insert new Store, Inc Food 10.50 2026-08-10T12:00:00Z\n" +
+			"profile Sep 2026 create_table\n" +
+			"profile Sep 2026 add_column Name\n",
+		"csv": "PROFILE,TABLE,ACTION,COLUMN,EXTERNAL ID,NAME,CATEGORY,BUDGET GROUP,AMOUNT,DATE\n" +
+			"profile,Aug 2026,ensure_column,External ID,,,,,,\n" +
+			"profile,Aug 2026,insert,,new,\"Store, Inc\",Food,,10.50,2026-08-10T12:00:00Z\n" +
+			"profile,Sep 2026,create_table,,,,,,,\n" +
+			"profile,Sep 2026,add_column,Name,,,,,,\n",
+		"json": `[
+  {
+    "profile": "profile",
+    "table": "Aug 2026",
+    "action": "ensure_column",
+    "column": "External ID"
+  },
+  {
+    "profile": "profile",
+    "table": "Aug 2026",
+    "action": "insert",
+    "external_id": "new",
+    "name": "Store, Inc",
+    "category": "Food",
+    "amount": 10.5,
+    "date": "2026-08-10T12:00:00Z"
+  },
+  {
+    "profile": "profile",
+    "table": "Sep 2026",
+    "action": "create_table"
+  },
+  {
+    "profile": "profile",
+    "table": "Sep 2026",
+    "action": "add_column",
+    "column": "Name"
+  }
+]
+`,
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			command := testCommand(date, date)
+			var output bytes.Buffer
+			command.SetOut(&output)
+			if err := command.Flags().Set(dryRunFlag, "true"); err != nil {
+				t.Fatalf("set dry-run flag: %v", err)
+			}
+			if err := command.Flags().Set(outputFlag, format); err != nil {
+				t.Fatalf("set output flag: %v", err)
+			}
+
+			ingestUseCase := mockingest.NewMockIngest(t)
+			ingestUseCase.EXPECT().
+				Execute(mock.Anything, mock.MatchedBy(func(input ingest.IngestInput) bool {
+					return input.DryRun
+				})).
+				Return(ingest.IngestOutput{Plan: plan}, nil).
+				Once()
+
+			if err := executeIngest(command, ingestUseCase); err != nil {
+				t.Fatalf("executeIngest() error = %v", err)
+			}
+			got := output.String()
+			if format == string(outputFormatTable) {
+				got = collapseSpaces(got)
+			}
+			if got != want {
+				t.Fatalf("output = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestExecuteIngestPrintsRunReport(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)
+	reports := []ingest.ProfileReport{{
+		IngestProfileID: "profile",
+		Status:          ingest.ProfileStatusSucceeded,
+		Months: []ingest.MonthReport{{
+			Month:   time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+			Title:   "Aug 2026",
+			Fetched: 5,
+			Filtered: map[ingest.FilterReason]int{
+				ingest.FilterReasonCredit:      1,
+				ingest.FilterReasonBillPayment: 1,
+			},
+			Renamed: 1,
+			Categorized: map[ingest.CategorySource]int{
+				ingest.CategorySourceGPT:      2,
+				ingest.CategorySourceFallback: 1,
+			},
+			Duplicates: 1,
+			Inserted:   1,
+			Failed:     1,
+		}},
+	}}
+
+	tests := map[string]string{
+		"table": "PROFILE MONTH TABLE STATUS FETCHED FILTERED RENAMED CATEGORIZED DUPLICATES INSERTED UPDATED " +
+			"ARCHIVED FAILED ERROR\n" +
+			"profile 2026-08 Aug 2026 succeeded 5 bill_payment=1 credit=1 1 fallback=1 gpt=2 1 1 0 0 1\n" +
+			"Ingest completed successfully\n",
+		"csv": "PROFILE,MONTH,TABLE,STATUS,FETCHED,FILTERED,RENAMED,CATEGORIZED,DUPLICATES,INSERTED,UPDATED," +
+			"ARCHIVED,FAILED,ERROR\n" +
+			"profile,2026-08,Aug 2026,succeeded,5,bill_payment=1 credit=1,1,fallback=1 gpt=2,1,1,0,0,1,\n",
+		"json": `[
+  {
+    "profile": "profile",
+    "month": "2026-08",
+    "table": "Aug 2026",
+    "status": "succeeded",
+    "fetched": 5,
+    "filtered": {
+      "bill_payment": 1,
+      "credit": 1
+    },
+    "renamed": 1,
+    "categorized": {
+      "fallback": 1,
+      "gpt": 2
+    },
+    "duplicates": 1,
+    "inserted": 1,
+    "updated": 0,
+    "archived": 0,
+    "failed": 1
+  }
+]
+`,
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			command := testCommand(date, date)
+			var output bytes.Buffer
+			command.SetOut(&output)
+			if err := command.Flags().Set(outputFlag, format); err != nil {
+				t.Fatalf("set output flag: %v", err)
+			}
+
+			ingestUseCase := mockingest.NewMockIngest(t)
+			ingestUseCase.EXPECT().
+				Execute(mock.Anything, mock.Anything).
+				Return(ingest.IngestOutput{Reports: reports}, nil).
+				Once()
+
+			if err := executeIngest(command, ingestUseCase); err != nil {
+				t.Fatalf("executeIngest() error = %v", err)
+			}
+			got := output.String()
+			if format == string(outputFormatTable) {
+				got = collapseSpaces(got)
+			}
+			if got != want {
+				t.Fatalf("output = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestExecuteIngestPrintsRunReportOfFailedRun(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)
+	command := testCommand(date, date)
+	var output bytes.Buffer
+	command.SetOut(&output)
+
+	sourceErr := errors.New("item login expired")
+	insertErr := errors.New("insert failed")
+	ingestUseCase := mockingest.NewMockIngest(t)
+	ingestUseCase.EXPECT().
+		Execute(mock.Anything, mock.Anything).
+		Return(ingest.IngestOutput{Reports: []ingest.ProfileReport{
+			{
+				IngestProfileID: "expired",
+				Status:          ingest.ProfileStatusFailed,
+				Err:             sourceErr,
+				Months: []ingest.MonthReport{{
+					Month: time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+					Err:   sourceErr,
+				}},
+			},
+			{
+				IngestProfileID: "healthy",
+				Status:          ingest.ProfileStatusPartial,
+				Err:             insertErr,
+				Months: []ingest.MonthReport{
+					{
+						Month:   time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+						Title:   "Aug 2026",
+						Fetched: 2,
+						Failed:  2,
+						Err:     errors.Join(insertErr, errors.New("row rejected")),
+					},
+					{
+						Month:    time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+						Title:    "Sep 2026",
+						Fetched:  1,
+						Inserted: 1,
+					},
+				},
+			},
+		}}, errors.Join(sourceErr, insertErr)).
+		Once()
+
+	if err := executeIngest(command, ingestUseCase); !errors.Is(err, sourceErr) || !errors.Is(err, insertErr) {
+		t.Fatalf("executeIngest() error = %v, want both profile errors", err)
+	}
+
+	got := collapseSpaces(output.String())
+	for _, want := range []string{
+		"expired 2026-08 failed 0 0 0 0 0 0 0 0 0 item login expired",
+		"healthy 2026-08 Aug 2026 failed 2 0 0 0 0 0 0 0 2 insert failed; row rejected",
+		"healthy 2026-09 Sep 2026 succeeded 1 0 0 0 0 1 0 0 0",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output = %q, want line %q", got, want)
+		}
+	}
+}
+
+func TestExecuteIngestRejectsUnknownOutputFormat(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)
+	command := testCommand(date, date)
+	if err := command.Flags().Set(outputFlag, "yaml"); err != nil {
+		t.Fatalf("set output flag: %v", err)
+	}
+
+	if err := executeIngest(command, mockingest.NewMockIngest(t)); err == nil {
+		t.Fatal("executeIngest() error = nil, want unsupported output format")
+	}
+}
+
+func TestExecuteLearnPrintsCorrections(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)
+	command := testCommand(date, date)
+	var output bytes.Buffer
+	command.SetOut(&output)
+	if err := command.Flags().Set(dryRunFlag, "true"); err != nil {
+		t.Fatalf("set dry-run flag: %v", err)
+	}
+
+	learner := mockingest.NewMockLearner(t)
+	learner.EXPECT().
+		Learn(mock.Anything, mock.MatchedBy(func(input ingest.LearnInput) bool {
+			return input.StartDate.Equal(date) && input.EndDate.Equal(date) && input.DryRun
+		})).
+		Return(ingest.LearnOutput{Corrections: []ingest.Correction{{
+			IngestProfileID:     "profile",
+			Name:                "Store",
+			Category:            "Food",
+			BudgetGroup:         "Lifestyle",
+			AssignedCategory:    "Others",
+			AssignedBudgetGroup: "Other",
+		}}}, nil).
+		Once()
+
+	if err := executeLearn(command, learner); err != nil {
+		t.Fatalf("executeLearn() error = %v", err)
+	}
+
+	want := "PROFILE NAME CATEGORY BUDGET GROUP ASSIGNED CATEGORY ASSIGNED BUDGET GROUP\n" +
+		"profile Store Food Lifestyle Others Other\n"
+	if got := collapseSpaces(output.String()); got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+}
+
+func TestExecuteLearnPropagatesLearnError(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)
+	wantErr := errors.New("list tables failed")
+	learner := mockingest.NewMockLearner(t)
+	learner.EXPECT().Learn(mock.Anything, mock.Anything).Return(ingest.LearnOutput{}, wantErr).Once()
+
+	if err := executeLearn(testCommand(date, date), learner); !errors.Is(err, wantErr) {
+		t.Fatalf("executeLearn() error = %v", err)
+	}
+}
+
+// collapseSpaces drops the column padding of table output, which depends on the longest cell.
+func collapseSpaces(text string) string {
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		lines[index] = strings.Join(strings.Fields(line), " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/app"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/ingest"
+)
+
+func init() {
+	addDateFlags(learnCmd)
+	learnCmd.Flags().Bool(dryRunFlag, false, "Print the corrections found without saving them")
+	learnCmd.Flags().StringP(
+		outputFlag,
+		"o",
+		string(outputFormatTable),
+		"Format of the corrections: table, json or csv",
+	)
+
+	rootCmd.AddCommand(learnCmd)
+}
+
+var learnCmd = &cobra.Command{
+	Use:   "learn",
+	Short: "Learn category corrections made by hand in the sheet",
+	Long:  "Compare the rows of the monthly tables with the categorization and save the categories and budget groups changed by hand in the categorization cache, so later runs reuse them instead of asking GPT. CATEGORIZATION_CACHE_PATH is required unless --dry-run is set.",
+	RunE:  runLearn,
+}
+
+func runLearn(cmd *cobra.Command, _ []string) error {
+	ingestUseCase, err := app.NewIngestUseCase()
+	if err != nil {
+		return fmt.Errorf("initialize application: %w", err)
+	}
+
+	return executeLearn(cmd, ingestUseCase)
+}
+
+func executeLearn(cmd *cobra.Command, learner ingest.CorrectionLearner) error {
+	dryRunVal, _ := cmd.Flags().GetBool(dryRunFlag)
+
+	format, err := outputFormatFlagValue(cmd)
+	if err != nil {
+		return err
+	}
+	startDateVal, endDateVal := dateRangeFlagValues(cmd)
+
+	output, err := learner.Learn(context.Background(), ingest.LearnInput{
+		StartDate: startDateVal,
+		EndDate:   endDateVal,
+		DryRun:    dryRunVal,
+	})
+	if err != nil {
+		err = fmt.Errorf("learn corrections: %w", err)
+		// Profiles that succeeded still saved their corrections.
+		if len(output.Corrections) > 0 {
+			if printErr := writeCorrections(cmd.OutOrStdout(), format, output.Corrections); printErr != nil {
+				return errors.Join(err, fmt.Errorf("print corrections: %w", printErr))
+			}
+		}
+
+		return err
+	}
+
+	if err := writeCorrections(cmd.OutOrStdout(), format, output.Corrections); err != nil {
+		return fmt.Errorf("print corrections: %w", err)
+	}
+
+	return nil
+}
+
+// learnedCorrection is one line of the corrections listing.
+type learnedCorrection struct {
+	Profile             string `json:"profile"`
+	Name                string `json:"name"`
+	Category            string `json:"category"`
+	BudgetGroup         string `json:"budget_group,omitempty"`
+	AssignedCategory    string `json:"assigned_category"`
+	AssignedBudgetGroup string `json:"assigned_budget_group,omitempty"`
+}
+
+var learnedCorrectionHeader = []string{
+	"PROFILE",
+	"NAME",
+	"CATEGORY",
+	"BUDGET GROUP",
+	"ASSIGNED CATEGORY",
+	"ASSIGNED BUDGET GROUP",
+}
+
+func (correction learnedCorrection) fields() []string {
+	return []string{
+		correction.Profile,
+		correction.Name,
+		correction.Category,
+		correction.BudgetGroup,
+		correction.AssignedCategory,
+		correction.AssignedBudgetGroup,
+	}
+}
+
+func learnedCorrections(corrections []ingest.Correction) []learnedCorrection {
+	lines := make([]learnedCorrection, 0, len(corrections))
+	for _, correction := range corrections {
+		lines = append(lines, learnedCorrection{
+			Profile:             correction.IngestProfileID,
+			Name:                correction.Name,
+			Category:            string(correction.Category),
+			BudgetGroup:         string(correction.BudgetGroup),
+			AssignedCategory:    string(correction.AssignedCategory),
+			AssignedBudgetGroup: string(correction.AssignedBudgetGroup),
+		})
+	}
+
+	return lines
+}
+
+func writeCorrections(writer io.Writer, format outputFormat, corrections []ingest.Correction) error {
+	return writeRecords(
+		writer,
+		format,
+		learnedCorrectionHeader,
+		learnedCorrections(corrections),
+		"No corrections found",
+	)
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	outputFormatTable outputFormat = "table"
+	outputFormatJSON  outputFormat = "json"
+	outputFormatCSV   outputFormat = "csv"
+)
+
+func (format outputFormat) isValid() bool {
+	switch format {
+	case outputFormatTable, outputFormatJSON, outputFormatCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// record is one line of a printed listing.
+type record interface {
+	fields() []string
+}
+
+// writeRecords prints the records as indented JSON, CSV with a header, or an aligned table that
+// reads emptyMessage when there are no records.
+func writeRecords[T record](
+	writer io.Writer,
+	format outputFormat,
+	header []string,
+	records []T,
+	emptyMessage string,
+) error {
+	switch format {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(records)
+	case outputFormatCSV:
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(header); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := csvWriter.Write(record.fields()); err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+
+		return csvWriter.Error()
+	default:
+		return writeTable(writer, header, records, emptyMessage)
+	}
+}
+
+func writeTable[T record](writer io.Writer, header []string, records []T, emptyMessage string) error {
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(writer, emptyMessage)
+
+		return err
+	}
+
+	tableWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tableWriter, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if _, err := fmt.Fprintln(tableWriter, strings.Join(record.fields(), "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tableWriter.Flush()
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/ingest"
+)
+
+const (
+	planActionCreateTable  = "create_table"
+	planActionAddColumn    = "add_column"
+	planActionEnsureColumn = "ensure_column"
+	planActionInsert       = "insert"
+	planActionUpdate       = "update"
+	planActionArchive      = "archive"
+)
+
+// plannedChange is one line of a dry-run plan. Table and column changes leave the transaction
+// fields empty.
+type plannedChange struct {
+	Profile     string  `json:"profile"`
+	Table       string  `json:"table"`
+	Action      string  `json:"action"`
+	Column      string  `json:"column,omitempty"`
+	ExternalID  string  `json:"external_id,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	BudgetGroup string  `json:"budget_group,omitempty"`
+	Amount      float64 `json:"amount,omitempty"`
+	Date        string  `json:"date,omitempty"`
+}
+
+var plannedChangeHeader = []string{
+	"PROFILE",
+	"TABLE",
+	"ACTION",
+	"COLUMN",
+	"EXTERNAL ID",
+	"NAME",
+	"CATEGORY",
+	"BUDGET GROUP",
+	"AMOUNT",
+	"DATE",
+}
+
+func (change plannedChange) fields() []string {
+	amount := ""
+	if change.Date != "" {
+		amount = strconv.FormatFloat(change.Amount, 'f', 2, 64)
+	}
+
+	return []string{
+		change.Profile,
+		change.Table,
+		change.Action,
+		change.Column,
+		change.ExternalID,
+		change.Name,
+		change.Category,
+		change.BudgetGroup,
+		amount,
+		change.Date,
+	}
+}
+
+func plannedChanges(plan []ingest.TablePlan) []plannedChange {
+	changes := make([]plannedChange, 0, len(plan))
+	for _, table := range plan {
+		columnAction := planActionEnsureColumn
+		if table.Create {
+			columnAction = planActionAddColumn
+			changes = append(changes, plannedChange{
+				Profile: table.IngestProfileID,
+				Table:   table.Title,
+				Action:  planActionCreateTable,
+			})
+		}
+		for _, column := range table.Columns {
+			changes = append(changes, plannedChange{
+				Profile: table.IngestProfileID,
+				Table:   table.Title,
+				Action:  columnAction,
+				Column:  column,
+			})
+		}
+
+		for _, transaction := range table.Insert {
+			changes = append(changes, transactionChange(table, planActionInsert, transaction))
+		}
+		for _, transaction := range table.Update {
+			changes = append(changes, transactionChange(table, planActionUpdate, transaction))
+		}
+		for _, transaction := range table.Archive {
+			changes = append(changes, transactionChange(table, planActionArchive, transaction))
+		}
+	}
+
+	return changes
+}
+
+func transactionChange(table ingest.TablePlan, action string, transaction entity.Transaction) plannedChange {
+	return plannedChange{
+		Profile:     table.IngestProfileID,
+		Table:       table.Title,
+		Action:      action,
+		ExternalID:  transaction.ExternalID,
+		Name:        transaction.Name,
+		Category:    string(transaction.Category),
+		BudgetGroup: string(transaction.BudgetGroup),
+		Amount:      transaction.Amount,
+		Date:        transaction.Date.Format(time.RFC3339),
+	}
+}
+
+func writePlan(writer io.Writer, format outputFormat, plan []ingest.TablePlan) error {
+	return writeRecords(writer, format, plannedChangeHeader, plannedChanges(plan), "No changes planned")
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/ingest"
+)
+
+const reportMonthLayout = "2006-01"
+
+// monthReport is one line of the run report, counting the transactions of one profile and month.
+type monthReport struct {
+	Profile     string         `json:"profile"`
+	Month       string         `json:"month"`
+	Table       string         `json:"table"`
+	Status      string         `json:"status"`
+	Fetched     int            `json:"fetched"`
+	Filtered    map[string]int `json:"filtered"`
+	Renamed     int            `json:"renamed"`
+	Categorized map[string]int `json:"categorized"`
+	Duplicates  int            `json:"duplicates"`
+	Inserted    int            `json:"inserted"`
+	Updated     int            `json:"updated"`
+	Archived    int            `json:"archived"`
+	Failed      int            `json:"failed"`
+	Error       string         `json:"error,omitempty"`
+}
+
+var monthReportHeader = []string{
+	"PROFILE",
+	"MONTH",
+	"TABLE",
+	"STATUS",
+	"FETCHED",
+	"FILTERED",
+	"RENAMED",
+	"CATEGORIZED",
+	"DUPLICATES",
+	"INSERTED",
+	"UPDATED",
+	"ARCHIVED",
+	"FAILED",
+	"ERROR",
+}
+
+func (report monthReport) fields() []string {
+	return []string{
+		report.Profile,
+		report.Month,
+		report.Table,
+		report.Status,
+		strconv.Itoa(report.Fetched),
+		formatCounts(report.Filtered),
+		strconv.Itoa(report.Renamed),
+		formatCounts(report.Categorized),
+		strconv.Itoa(report.Duplicates),
+		strconv.Itoa(report.Inserted),
+		strconv.Itoa(report.Updated),
+		strconv.Itoa(report.Archived),
+		strconv.Itoa(report.Failed),
+		report.Error,
+	}
+}
+
+// formatCounts lists the counts by key as "key=count" pairs in key order, or "0" when empty.
+func formatCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "0"
+	}
+
+	pairs := make([]string, 0, len(counts))
+	for _, key := range slices.Sorted(maps.Keys(counts)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", key, counts[key]))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+func monthReports(reports []ingest.ProfileReport) []monthReport {
+	var lines []monthReport
+	for _, profile := range reports {
+		for _, month := range profile.Months {
+			line := monthReport{
+				Profile:     profile.IngestProfileID,
+				Month:       month.Month.Format(reportMonthLayout),
+				Table:       month.Title,
+				Status:      string(ingest.ProfileStatusSucceeded),
+				Fetched:     month.Fetched,
+				Filtered:    stringKeys(month.Filtered),
+				Renamed:     month.Renamed,
+				Categorized: stringKeys(month.Categorized),
+				Duplicates:  month.Duplicates,
+				Inserted:    month.Inserted,
+				Updated:     month.Updated,
+				Archived:    month.Archived,
+				Failed:      month.Failed,
+			}
+			if month.Err != nil {
+				line.Status = string(ingest.ProfileStatusFailed)
+				// Joined errors span several lines, which would break the table and CSV rows.
+				line.Error = strings.ReplaceAll(month.Err.Error(), "\n", "; ")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func stringKeys[K ~string](counts map[K]int) map[string]int {
+	converted := make(map[string]int, len(counts))
+	for key, count := range counts {
+		if count > 0 {
+			converted[string(key)] = count
+		}
+	}
+
+	return converted
+}
+
+func writeReport(writer io.Writer, format outputFormat, reports []ingest.ProfileReport) error {
+	return writeRecords(writer, format, monthReportHeader, monthReports(reports), "No ingest profiles configured")
+}
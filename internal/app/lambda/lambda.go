@@ -43,13 +43,34 @@ type Response struct {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
+	// Report covers the work done by the run, including the profiles and months that succeeded.
+	Report []MonthReportResponse `json:"report,omitempty"`
 }
 
 type SuccessResponse struct {
-	Message   string `json:"message"`
-	StartDate string `json:"start_date"`
-	EndDate   string `json:"end_date"`
-	Duration  string `json:"duration"`
+	Message   string                `json:"message"`
+	StartDate string                `json:"start_date"`
+	EndDate   string                `json:"end_date"`
+	Duration  string                `json:"duration"`
+	Report    []MonthReportResponse `json:"report"`
+}
+
+// MonthReportResponse counts what happened to the transactions of one profile and month.
+type MonthReportResponse struct {
+	Profile     string         `json:"profile"`
+	Month       string         `json:"month"`
+	Table       string         `json:"table"`
+	Status      string         `json:"status"`
+	Fetched     int            `json:"fetched"`
+	Filtered    map[string]int `json:"filtered"`
+	Renamed     int            `json:"renamed"`
+	Categorized map[string]int `json:"categorized"`
+	Duplicates  int            `json:"duplicates"`
+	Inserted    int            `json:"inserted"`
+	Updated     int            `json:"updated"`
+	Archived    int            `json:"archived"`
+	Failed      int            `json:"failed"`
+	Error       string         `json:"error,omitempty"`
 }
 
 func (h *LambdaHandler) Handle(ctx context.Context) (Response, error) {
@@ -62,11 +83,12 @@ func (h *LambdaHandler) Handle(ctx context.Context) (Response, error) {
 		EndDate:   endDate,
 	}
 
-	err := h.ingestUseCase.Execute(ctx, input)
+	output, err := h.ingestUseCase.Execute(ctx, input)
 	if err != nil {
 		return newResponse(http.StatusInternalServerError, ErrorResponse{
 			Error:   "ingest_failed",
 			Message: fmt.Sprintf("Failed to execute ingest: %v", err),
+			Report:  newMonthReportResponses(output.Reports),
 		}), nil
 	}
 
@@ -77,9 +99,51 @@ func (h *LambdaHandler) Handle(ctx context.Context) (Response, error) {
 		StartDate: startDate.Format(time.RFC3339),
 		EndDate:   endDate.Format(time.RFC3339),
 		Duration:  duration.String(),
+		Report:    newMonthReportResponses(output.Reports),
 	}), nil
 }
 
+func newMonthReportResponses(reports []ingest.ProfileReport) []MonthReportResponse {
+	responses := make([]MonthReportResponse, 0, len(reports))
+	for _, profile := range reports {
+		for _, month := range profile.Months {
+			response := MonthReportResponse{
+				Profile:     profile.IngestProfileID,
+				Month:       month.Month.Format("2006-01"),
+				Table:       month.Title,
+				Status:      string(ingest.ProfileStatusSucceeded),
+				Fetched:     month.Fetched,
+				Filtered:    stringKeys(month.Filtered),
+				Renamed:     month.Renamed,
+				Categorized: stringKeys(month.Categorized),
+				Duplicates:  month.Duplicates,
+				Inserted:    month.Inserted,
+				Updated:     month.Updated,
+				Archived:    month.Archived,
+				Failed:      month.Failed,
+			}
+			if month.Err != nil {
+				response.Status = string(ingest.ProfileStatusFailed)
+				response.Error = month.Err.Error()
+			}
+			responses = append(responses, response)
+		}
+	}
+
+	return responses
+}
+
+func stringKeys[K ~string](counts map[K]int) map[string]int {
+	converted := make(map[string]int, len(counts))
+	for key, count := range counts {
+		if count > 0 {
+			converted[string(key)] = count
+		}
+	}
+
+	return converted
+}
+
 func newResponse(statusCode int, value any) Response {
 	body, err := json.Marshal(value)
 	if err != nil {
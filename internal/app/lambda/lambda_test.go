@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -18,7 +20,18 @@ func TestLambdaHandlerSuccess(t *testing.T) {
 		Execute(mock.Anything, mock.MatchedBy(func(input ingest.IngestInput) bool {
 			return input.EndDate.Sub(input.StartDate).Hours() == 7*24
 		})).
-		Return(nil).
+		Return(ingest.IngestOutput{Reports: []ingest.ProfileReport{{
+			IngestProfileID: "profile",
+			Status:          ingest.ProfileStatusSucceeded,
+			Months: []ingest.MonthReport{{
+				Month:       time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+				Title:       "Aug 2026",
+				Fetched:     3,
+				Filtered:    map[ingest.FilterReason]int{ingest.FilterReasonCredit: 1},
+				Categorized: map[ingest.CategorySource]int{ingest.CategorySourceMapping: 2},
+				Inserted:    2,
+			}},
+		}}}, nil).
 		Once()
 
 	response, err := newLambdaHandler(ingestUseCase).Handle(t.Context())
@@ -38,11 +51,36 @@ func TestLambdaHandlerSuccess(t *testing.T) {
 	if body.Message != "Ingest completed successfully" || body.StartDate == "" || body.EndDate == "" {
 		t.Fatalf("body = %#v", body)
 	}
+	wantReport := []MonthReportResponse{{
+		Profile:     "profile",
+		Month:       "2026-08",
+		Table:       "Aug 2026",
+		Status:      "succeeded",
+		Fetched:     3,
+		Filtered:    map[string]int{"credit": 1},
+		Categorized: map[string]int{"mapping": 2},
+		Inserted:    2,
+	}}
+	if !reflect.DeepEqual(body.Report, wantReport) {
+		t.Fatalf("report = %#v, want %#v", body.Report, wantReport)
+	}
 }
 
 func TestLambdaHandlerFailure(t *testing.T) {
 	ingestUseCase := mockingest.NewMockIngest(t)
-	ingestUseCase.EXPECT().Execute(mock.Anything, mock.Anything).Return(errors.New("failed")).Once()
+	ingestErr := errors.New("item login expired")
+	ingestUseCase.EXPECT().
+		Execute(mock.Anything, mock.Anything).
+		Return(ingest.IngestOutput{Reports: []ingest.ProfileReport{{
+			IngestProfileID: "profile",
+			Status:          ingest.ProfileStatusFailed,
+			Err:             ingestErr,
+			Months: []ingest.MonthReport{{
+				Month: time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+				Err:   ingestErr,
+			}},
+		}}}, ingestErr).
+		Once()
 
 	response, err := newLambdaHandler(ingestUseCase).Handle(t.Context())
 	if err != nil {
@@ -56,7 +94,8 @@ func TestLambdaHandlerFailure(t *testing.T) {
 	if err := json.Unmarshal([]byte(response.Body), &body); err != nil {
 		t.Fatalf("decode body: %v", err)
 	}
-	if body.Error != "ingest_failed" {
+	if body.Error != "ingest_failed" || len(body.Report) != 1 || body.Report[0].Status != "failed" ||
+		body.Report[0].Error != ingestErr.Error() {
 		t.Fatalf("body = %#v", body)
 	}
 }
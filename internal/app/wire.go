@@ -13,11 +13,18 @@ import (
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/companyapi"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/companyapi/brasilapi"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/anthropicapi"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/geminiapi"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/openai"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore/jsonfile"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/openfinance"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/openfinance/pluggyapi"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/filesheet"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/googlesheets"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/notionapi"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/sqlsheet"
 )
 
 func ingestSettings(env *config.Env) entity.IngestSettings {
@@ -28,6 +35,55 @@ func maxConcurrentOperations(env *config.Env) int {
 	return env.MaxConcurrentOperations
 }
 
+func sheetRouter(
+	env *config.Env,
+	notionClient *notionapi.Client,
+	googleSheetsClient *googlesheets.Client,
+	fileClient *filesheet.Client,
+	sqlClient *sqlsheet.Client,
+) *sheet.Router {
+	providers := make(map[string]sheet.Provider, len(env.IngestProfiles))
+	for _, ingestProfile := range env.IngestProfiles {
+		switch ingestProfile.SheetProviderOrDefault() {
+		case entity.SheetProviderNotion:
+			providers[ingestProfile.ID] = notionClient
+		case entity.SheetProviderGoogleSheets:
+			providers[ingestProfile.ID] = googleSheetsClient
+		case entity.SheetProviderFile:
+			providers[ingestProfile.ID] = fileClient
+		case entity.SheetProviderSQL:
+			providers[ingestProfile.ID] = sqlClient
+		}
+	}
+
+	return sheet.NewRouter(providers)
+}
+
+func gptRouter(
+	env *config.Env,
+	openAIClient *openai.OpenAIClient,
+	anthropicClient *anthropicapi.Client,
+	geminiClient *geminiapi.Client,
+) *gpt.Router {
+	providers := make(map[string]gpt.Provider, len(env.IngestProfiles))
+	for _, ingestProfile := range env.IngestProfiles {
+		if !ingestProfile.UsesLLM() {
+			continue
+		}
+
+		switch ingestProfile.LLMConnectionOrDefault().Provider {
+		case entity.LLMProviderOpenAI, entity.LLMProviderOllama:
+			providers[ingestProfile.ID] = openAIClient
+		case entity.LLMProviderAnthropic:
+			providers[ingestProfile.ID] = anthropicClient
+		case entity.LLMProviderGemini:
+			providers[ingestProfile.ID] = geminiClient
+		}
+	}
+
+	return gpt.NewRouter(providers)
+}
+
 func NewIngestUseCase() (*ingest.Ingest, error) {
 	wire.Build(
 		validator.NewValidator,
@@ -38,11 +94,21 @@ func NewIngestUseCase() (*ingest.Ingest, error) {
 		wire.Bind(new(companyapi.APIProvider), new(*brasilapi.Client)),
 		brasilapi.NewClient,
 
-		wire.Bind(new(gpt.Provider), new(*openai.OpenAIClient)),
+		wire.Bind(new(gpt.Provider), new(*gpt.Router)),
+		gptRouter,
 		openai.NewOpenAIClient,
+		anthropicapi.NewClient,
+		geminiapi.NewClient,
+
+		wire.Bind(new(kvstore.Provider), new(*jsonfile.Client)),
+		jsonfile.NewClient,
 
-		wire.Bind(new(sheet.Provider), new(*notionapi.Client)),
+		wire.Bind(new(sheet.Provider), new(*sheet.Router)),
+		sheetRouter,
 		notionapi.NewClient,
+		googlesheets.NewClient,
+		filesheet.NewClient,
+		sqlsheet.NewClient,
 
 		wire.Bind(new(openfinance.APIProvider), new(*pluggyapi.Client)),
 		pluggyapi.NewClient,
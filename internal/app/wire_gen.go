@@ -12,9 +12,17 @@ import (
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/ingest"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/pkg/validator"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/companyapi/brasilapi"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/anthropicapi"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/geminiapi"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/openai"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore/jsonfile"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/openfinance/pluggyapi"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/filesheet"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/googlesheets"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/notionapi"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/sqlsheet"
 )
 
 // Injectors from wire.go:
@@ -29,12 +37,26 @@ func NewIngestUseCase() (*ingest.Ingest, error) {
 	entityIngestSettings := ingestSettings(env)
 	client := brasilapi.NewClient()
 	openAIClient := openai.NewOpenAIClient(env)
+	anthropicapiClient := anthropicapi.NewClient(env)
+	geminiapiClient := geminiapi.NewClient(env)
+	router := gptRouter(env, openAIClient, anthropicapiClient, geminiapiClient)
+	jsonfileClient := jsonfile.NewClient(env)
 	notionapiClient := notionapi.NewClient(env)
+	googlesheetsClient, err := googlesheets.NewClient(env)
+	if err != nil {
+		return nil, err
+	}
+	filesheetClient := filesheet.NewClient(env)
+	sqlsheetClient, err := sqlsheet.NewClient(env)
+	if err != nil {
+		return nil, err
+	}
+	router2 := sheetRouter(env, notionapiClient, googlesheetsClient, filesheetClient, sqlsheetClient)
 	pluggyapiClient, err := pluggyapi.NewClient(env)
 	if err != nil {
 		return nil, err
 	}
-	ingestIngest := ingest.NewIngest(validatorValidator, int2, entityIngestSettings, client, openAIClient, notionapiClient, pluggyapiClient)
+	ingestIngest := ingest.NewIngest(validatorValidator, int2, entityIngestSettings, client, router, jsonfileClient, router2, pluggyapiClient)
 	return ingestIngest, nil
 }
 
@@ -47,3 +69,52 @@ func ingestSettings(env *config.Env) entity.IngestSettings {
 func maxConcurrentOperations(env *config.Env) int {
 	return env.MaxConcurrentOperations
 }
+
+func sheetRouter(
+	env *config.Env,
+	notionClient *notionapi.Client,
+	googleSheetsClient *googlesheets.Client,
+	fileClient *filesheet.Client,
+	sqlClient *sqlsheet.Client,
+) *sheet.Router {
+	providers := make(map[string]sheet.Provider, len(env.IngestProfiles))
+	for _, ingestProfile := range env.IngestProfiles {
+		switch ingestProfile.SheetProviderOrDefault() {
+		case entity.SheetProviderNotion:
+			providers[ingestProfile.ID] = notionClient
+		case entity.SheetProviderGoogleSheets:
+			providers[ingestProfile.ID] = googleSheetsClient
+		case entity.SheetProviderFile:
+			providers[ingestProfile.ID] = fileClient
+		case entity.SheetProviderSQL:
+			providers[ingestProfile.ID] = sqlClient
+		}
+	}
+
+	return sheet.NewRouter(providers)
+}
+
+func gptRouter(
+	env *config.Env,
+	openAIClient *openai.OpenAIClient,
+	anthropicClient *anthropicapi.Client,
+	geminiClient *geminiapi.Client,
+) *gpt.Router {
+	providers := make(map[string]gpt.Provider, len(env.IngestProfiles))
+	for _, ingestProfile := range env.IngestProfiles {
+		if !ingestProfile.UsesLLM() {
+			continue
+		}
+
+		switch ingestProfile.LLMConnectionOrDefault().Provider {
+		case entity.LLMProviderOpenAI, entity.LLMProviderOllama:
+			providers[ingestProfile.ID] = openAIClient
+		case entity.LLMProviderAnthropic:
+			providers[ingestProfile.ID] = anthropicClient
+		case entity.LLMProviderGemini:
+			providers[ingestProfile.ID] = geminiClient
+		}
+	}
+
+	return gpt.NewRouter(providers)
+}
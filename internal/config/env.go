@@ -13,8 +13,9 @@ import (
 
 // EnvFileData is the data for the .env file.
 type EnvFileData struct {
-	OpenAIToken             string `json:"open_ai_token"             mapstructure:"OPEN_AI_TOKEN"             validate:"required"`
+	OpenAIToken             string `json:"open_ai_token"             mapstructure:"OPEN_AI_TOKEN"`
 	MaxConcurrentOperations int    `json:"max_concurrent_operations" mapstructure:"MAX_CONCURRENT_OPERATIONS" validate:"required,gte=1"`
+	CategorizationCachePath string `json:"categorization_cache_path" mapstructure:"CATEGORIZATION_CACHE_PATH"`
 }
 
 // IngestProfilesFileData is the data for the ingest_profiles.json file.
@@ -131,5 +132,22 @@ func (e *Env) validateIngestProfilesFile() error {
 		return fmt.Errorf("failed to validate ingest profiles file: %w", err)
 	}
 
+	return e.validateOpenAIToken()
+}
+
+// validateOpenAIToken requires OPEN_AI_TOKEN when a profile categorizing with OpenAI sets no API key of
+// its own.
+func (e *Env) validateOpenAIToken() error {
+	if e.OpenAIToken != "" {
+		return nil
+	}
+
+	for _, ingestProfile := range e.IngestProfiles {
+		connection := ingestProfile.LLMConnectionOrDefault()
+		if ingestProfile.UsesLLM() && connection.Provider == entity.LLMProviderOpenAI && connection.APIKey == "" {
+			return fmt.Errorf("ingest profile %q: OPEN_AI_TOKEN or an LLM api_key is required", ingestProfile.ID)
+		}
+	}
+
 	return nil
 }
@@ -21,6 +21,14 @@ func validIngestProfilesFileData() IngestProfilesFileData {
 	}}}
 }
 
+func validGoogleSheetsConnection() *entity.GoogleSheetsConnection {
+	return &entity.GoogleSheetsConnection{
+		SpreadsheetID: "spreadsheet",
+		ClientEmail:   "bot@project.iam.gserviceaccount.com",
+		PrivateKey:    "private-key",
+	}
+}
+
 func TestIngestProfilesFileDataValidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -107,6 +115,198 @@ func TestIngestProfilesFileDataValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid google sheets profile",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderGoogleSheets
+				data.IngestProfiles[0].NotionToken = ""
+				data.IngestProfiles[0].NotionPageID = ""
+				data.IngestProfiles[0].GoogleSheets = validGoogleSheetsConnection()
+			},
+		},
+		{
+			name: "missing notion token with explicit provider",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderNotion
+				data.IngestProfiles[0].NotionToken = ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported sheet provider",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = "airtable"
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing google sheets connection",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderGoogleSheets
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid google sheets client email",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderGoogleSheets
+				data.IngestProfiles[0].GoogleSheets = validGoogleSheetsConnection()
+				data.IngestProfiles[0].GoogleSheets.ClientEmail = "not-an-email"
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing google sheets spreadsheet id",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderGoogleSheets
+				data.IngestProfiles[0].GoogleSheets = validGoogleSheetsConnection()
+				data.IngestProfiles[0].GoogleSheets.SpreadsheetID = ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid file profile",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderFile
+				data.IngestProfiles[0].NotionToken = ""
+				data.IngestProfiles[0].NotionPageID = ""
+				data.IngestProfiles[0].File = &entity.FileConnection{Directory: "tables", Format: entity.FileFormatXLSX}
+			},
+		},
+		{
+			name: "missing file directory",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderFile
+				data.IngestProfiles[0].File = &entity.FileConnection{}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported file format",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderFile
+				data.IngestProfiles[0].File = &entity.FileConnection{Directory: "tables", Format: "ods"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid sql profile",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderSQL
+				data.IngestProfiles[0].NotionToken = ""
+				data.IngestProfiles[0].NotionPageID = ""
+				data.IngestProfiles[0].SQL = &entity.SQLConnection{Driver: entity.SQLDriverSQLite, DSN: "sheets.db"}
+			},
+		},
+		{
+			name: "missing sql dsn",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderSQL
+				data.IngestProfiles[0].SQL = &entity.SQLConnection{Driver: entity.SQLDriverPostgres}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported sql driver",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SheetProvider = entity.SheetProviderSQL
+				data.IngestProfiles[0].SQL = &entity.SQLConnection{Driver: "mysql", DSN: "sheets"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid anthropic LLM",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{
+					Provider:    entity.LLMProviderAnthropic,
+					Model:       "model",
+					APIKey:      "anthropic-key",
+					Temperature: new(0.0),
+				}
+			},
+		},
+		{
+			name: "valid ollama LLM",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{
+					Provider:        entity.LLMProviderOllama,
+					Model:           "model",
+					ReasoningEffort: entity.ReasoningEffortLow,
+					BaseURL:         "http://localhost:11434/v1",
+				}
+			},
+		},
+		{
+			name: "unsupported LLM provider",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{Provider: "mistral", Model: "model"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing gemini API key",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{Provider: entity.LLMProviderGemini, Model: "model"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing ollama model",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{Provider: entity.LLMProviderOllama}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported reasoning effort",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{ReasoningEffort: "extreme"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "temperature out of range",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{Temperature: new(2.5)}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid LLM base URL",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].LLM = &entity.LLMConnection{BaseURL: "localhost"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid heuristic categorizer",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].Categorizer = entity.CategorizerHeuristic
+				data.IngestProfiles[0].SourceCategoryMappings = map[string]entity.Category{"Groceries": "Food"}
+				data.IngestProfiles[0].CategoryKeywords = map[entity.Category][]string{"Food": {"market"}}
+			},
+		},
+		{
+			name: "unsupported categorizer",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].Categorizer = "bayes"
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty category keywords",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].CategoryKeywords = map[entity.Category][]string{"Food": {}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "blank source category",
+			mutate: func(data *IngestProfilesFileData) {
+				data.IngestProfiles[0].SourceCategoryMappings = map[string]entity.Category{"": "Food"}
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing pluggy client id",
 			mutate: func(data *IngestProfilesFileData) {
@@ -254,3 +454,41 @@ func TestLoadIngestSettingsWrapsDomainValidationError(t *testing.T) {
 		t.Fatalf("loadIngestSettings() error = %v, want invalid domain settings", err)
 	}
 }
+
+func TestValidateOpenAIToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		openAIToken string
+		categorizer entity.Categorizer
+		llm         *entity.LLMConnection
+		wantErr     bool
+	}{
+		{name: "environment token", openAIToken: "token"},
+		{name: "profile API key", llm: &entity.LLMConnection{APIKey: "key"}},
+		{
+			name: "profile without OpenAI",
+			llm:  &entity.LLMConnection{Provider: entity.LLMProviderOllama, Model: "model"},
+		},
+		{
+			name:        "heuristic profile",
+			categorizer: entity.CategorizerHeuristic,
+		},
+		{name: "missing token", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := validIngestProfilesFileData()
+			data.IngestProfiles[0].Categorizer = test.categorizer
+			data.IngestProfiles[0].LLM = test.llm
+			e := Env{
+				EnvFileData:            EnvFileData{OpenAIToken: test.openAIToken},
+				IngestProfilesFileData: data,
+			}
+
+			if err := e.validateOpenAIToken(); (err != nil) != test.wantErr {
+				t.Fatalf("validateOpenAIToken() error = %v, wantErr %t", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -4,4 +4,31 @@ type Category string
 
 const (
 	DefaultFallbackCategory Category = "Others"
+	// DefaultIncomeFallbackCategory is the fallback of credits in profiles that include them.
+	DefaultIncomeFallbackCategory Category = "Other income"
 )
+
+// DefaultCategorizationChunkSize is how many transaction names each categorization request holds
+// unless the profile sets its own size.
+const DefaultCategorizationChunkSize = 100
+
+// Categorizer selects what classifies the transactions the profile's category rules leave
+// incomplete.
+type Categorizer string
+
+const (
+	// CategorizerLLM asks the profile's LLM.
+	CategorizerLLM Categorizer = "llm"
+	// CategorizerHeuristic classifies offline, without sending transaction data anywhere.
+	CategorizerHeuristic Categorizer = "heuristic"
+	DefaultCategorizer   Categorizer = CategorizerLLM
+)
+
+func (categorizer Categorizer) IsValid() bool {
+	switch categorizer {
+	case CategorizerLLM, CategorizerHeuristic:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,187 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// NameMatch controls how a category rule compares its name with transaction names.
+type NameMatch string
+
+const (
+	NameMatchExact           NameMatch = "exact"
+	NameMatchCaseInsensitive NameMatch = "case_insensitive"
+	NameMatchPrefix          NameMatch = "prefix"
+	NameMatchContains        NameMatch = "contains"
+	NameMatchRegex           NameMatch = "regex"
+	DefaultNameMatch         NameMatch = NameMatchExact
+)
+
+func (match NameMatch) IsValid() bool {
+	switch match {
+	case NameMatchExact, NameMatchCaseInsensitive, NameMatchPrefix, NameMatchContains, NameMatchRegex:
+		return true
+	default:
+		return false
+	}
+}
+
+// CategoryRule assigns a category, a budget group, or both to the transactions that meet all of
+// its conditions. Conditions left empty match every transaction, but a rule needs at least one.
+// MinAmount and MaxAmount bound the absolute amount inclusively, and SourceCategories match the
+// category the open finance provider assigned to the transaction.
+type CategoryRule struct {
+	Name             string          `json:"name,omitempty"`
+	NameMatch        NameMatch       `json:"name_match,omitempty"        validate:"omitempty,oneof=exact case_insensitive prefix contains regex"`
+	MinAmount        *float64        `json:"min_amount,omitempty"        validate:"omitempty,gte=0"`
+	MaxAmount        *float64        `json:"max_amount,omitempty"        validate:"omitempty,gte=0"`
+	PaymentMethods   []PaymentMethod `json:"payment_methods,omitempty"   validate:"omitempty,dive,required"`
+	CardLastDigits   []string        `json:"card_last_digits,omitempty"  validate:"omitempty,dive,required"`
+	SourceCategories []string        `json:"source_categories,omitempty" validate:"omitempty,dive,required"`
+	Category         Category        `json:"category,omitempty"`
+	BudgetGroup      BudgetGroup     `json:"budget_group,omitempty"`
+
+	nameRegexp *regexp.Regexp
+}
+
+// Matches reports whether the transaction meets every condition of the rule. Regex rules only
+// match once normalized into the ingest profile settings, which compiles their expression. The
+// name of an installment matches with or without its installment marker.
+func (r CategoryRule) Matches(transaction Transaction) bool {
+	return (r.matchesName(transaction.Name) || r.matchesName(transaction.CategorizationName())) &&
+		matchesAmount(transaction.Amount, r.MinAmount, r.MaxAmount) &&
+		matchesAny(r.PaymentMethods, transaction.PaymentMethod) &&
+		(len(r.CardLastDigits) == 0 ||
+			transaction.CardLastDigits != nil && slices.Contains(r.CardLastDigits, *transaction.CardLastDigits)) &&
+		matchesAny(r.SourceCategories, transaction.SourceCategory)
+}
+
+func (r CategoryRule) matchesName(name string) bool {
+	return r.Name == "" || matchName(name, r.Name, r.NameMatch, r.nameRegexp)
+}
+
+// matchName compares a transaction name with the name of a rule the way the name match says.
+func matchName(name, ruleName string, match NameMatch, nameRegexp *regexp.Regexp) bool {
+	switch match {
+	case NameMatchCaseInsensitive:
+		return strings.EqualFold(name, ruleName)
+	case NameMatchPrefix:
+		return strings.HasPrefix(name, ruleName)
+	case NameMatchContains:
+		return strings.Contains(name, ruleName)
+	case NameMatchRegex:
+		return nameRegexp != nil && nameRegexp.MatchString(name)
+	default:
+		return name == ruleName
+	}
+}
+
+// matchesAny reports whether the value is one of the values of a rule condition, which an empty
+// condition matches whatever the value.
+func matchesAny[T comparable](values []T, value T) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+// matchesAmount reports whether the amount is within the inclusive bounds of a rule, either of
+// which may be unset.
+func matchesAmount(amount float64, minAmount, maxAmount *float64) bool {
+	return (minAmount == nil || amount >= *minAmount) && (maxAmount == nil || amount <= *maxAmount)
+}
+
+// normalizeNameMatch defaults the name match of a rule and compiles the name of regex rules.
+func normalizeNameMatch(name string, match NameMatch) (NameMatch, *regexp.Regexp, error) {
+	if match == "" {
+		match = DefaultNameMatch
+	}
+	if !match.IsValid() {
+		return "", nil, fmt.Errorf(
+			"unsupported name match %q (supported: %s, %s, %s, %s, %s)",
+			match,
+			NameMatchExact,
+			NameMatchCaseInsensitive,
+			NameMatchPrefix,
+			NameMatchContains,
+			NameMatchRegex,
+		)
+	}
+	if match != NameMatchRegex {
+		return match, nil, nil
+	}
+
+	nameRegexp, err := regexp.Compile(name)
+	if err != nil {
+		return "", nil, fmt.Errorf("compile name regex: %w", err)
+	}
+
+	return match, nameRegexp, nil
+}
+
+func (r CategoryRule) hasConditions() bool {
+	return r.Name != "" || r.MinAmount != nil || r.MaxAmount != nil || len(r.PaymentMethods) > 0 ||
+		len(r.CardLastDigits) > 0 || len(r.SourceCategories) > 0
+}
+
+func normalizeCategoryRules(
+	rules []CategoryRule,
+	colorsByCategory map[Category]Color,
+	colorsByBudgetGroup map[BudgetGroup]Color,
+) ([]CategoryRule, error) {
+	normalized := make([]CategoryRule, 0, len(rules))
+	for index, rule := range rules {
+		normalizedRule, err := normalizeCategoryRule(rule, colorsByCategory, colorsByBudgetGroup)
+		if err != nil {
+			return nil, fmt.Errorf("category rule %d: %w", index+1, err)
+		}
+
+		normalized = append(normalized, normalizedRule)
+	}
+
+	return normalized, nil
+}
+
+func normalizeCategoryRule(
+	rule CategoryRule,
+	colorsByCategory map[Category]Color,
+	colorsByBudgetGroup map[BudgetGroup]Color,
+) (CategoryRule, error) {
+	if !rule.hasConditions() {
+		return CategoryRule{}, errors.New("at least one condition is required")
+	}
+	if rule.Category == "" && rule.BudgetGroup == "" {
+		return CategoryRule{}, errors.New("a category or budget group is required")
+	}
+	if rule.Category != "" {
+		if _, ok := colorsByCategory[rule.Category]; !ok {
+			return CategoryRule{}, fmt.Errorf("category %q is not configured", rule.Category)
+		}
+	}
+	if rule.BudgetGroup != "" {
+		if _, ok := colorsByBudgetGroup[rule.BudgetGroup]; !ok {
+			return CategoryRule{}, fmt.Errorf("budget group %q is not configured", rule.BudgetGroup)
+		}
+	}
+
+	nameMatch, nameRegexp, err := normalizeNameMatch(rule.Name, rule.NameMatch)
+	if err != nil {
+		return CategoryRule{}, err
+	}
+	rule.NameMatch, rule.nameRegexp = nameMatch, nameRegexp
+
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return CategoryRule{}, fmt.Errorf("min amount %v is greater than max amount %v", *rule.MinAmount, *rule.MaxAmount)
+	}
+	for _, paymentMethod := range rule.PaymentMethods {
+		if !slices.Contains(PaymentMethods, paymentMethod) {
+			return CategoryRule{}, fmt.Errorf("payment method %q is not supported", paymentMethod)
+		}
+	}
+
+	rule.PaymentMethods = slices.Clone(rule.PaymentMethods)
+	rule.CardLastDigits = slices.Clone(rule.CardLastDigits)
+	rule.SourceCategories = slices.Clone(rule.SourceCategories)
+
+	return rule, nil
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Reasons of the default filter rules.
+const (
+	FilterReasonInvestment         = "investment"
+	FilterReasonBillPayment        = "bill_payment"
+	FilterReasonSamePersonTransfer = "same_person_transfer"
+)
+
+// FilterRule leaves out of the sheet the transactions that meet all of its conditions, reporting
+// them under its Reason. Conditions left empty match every transaction, but a rule needs at least
+// one. MinAmount and MaxAmount bound the absolute amount inclusively, SourceCategories match the
+// category the open finance provider assigned to the transaction, and AccountIDs match the account
+// it was listed from.
+type FilterRule struct {
+	Reason           string                 `json:"reason"                      validate:"required"`
+	Name             string                 `json:"name,omitempty"`
+	NameMatch        NameMatch              `json:"name_match,omitempty"        validate:"omitempty,oneof=exact case_insensitive prefix contains regex"`
+	SourceCategories []string               `json:"source_categories,omitempty" validate:"omitempty,dive,required"`
+	Directions       []TransactionDirection `json:"directions,omitempty"        validate:"omitempty,dive,oneof=CREDIT DEBIT"`
+	PaymentMethods   []PaymentMethod        `json:"payment_methods,omitempty"   validate:"omitempty,dive,required"`
+	MinAmount        *float64               `json:"min_amount,omitempty"        validate:"omitempty,gte=0"`
+	MaxAmount        *float64               `json:"max_amount,omitempty"        validate:"omitempty,gte=0"`
+	AccountIDs       []string               `json:"account_ids,omitempty"       validate:"omitempty,dive,required"`
+
+	nameRegexp *regexp.Regexp
+}
+
+// DefaultFilterRules are the filter rules of profiles that list none. They leave out investments,
+// credit card bill payments and, when the profile ignores them, transfers between accounts of the
+// same person, as Brazilian banks word them.
+func DefaultFilterRules(ignoreSamePersonTransfers bool) []FilterRule {
+	rules := []FilterRule{
+		{Reason: FilterReasonInvestment, SourceCategories: []string{"Investments"}},
+		{Reason: FilterReasonInvestment, Name: "Aplicação", NameMatch: NameMatchContains},
+		{Reason: FilterReasonBillPayment, Name: "Pagamento de fatura"},
+	}
+	if ignoreSamePersonTransfers {
+		rules = append(rules, FilterRule{
+			Reason:           FilterReasonSamePersonTransfer,
+			SourceCategories: []string{"Same person transfer"},
+		})
+	}
+
+	return rules
+}
+
+// Matches reports whether the rule drops the transaction from the sheet, which takes meeting all
+// of its conditions. A regex name drops nothing until the rule is normalized into the ingest
+// profile settings.
+func (r FilterRule) Matches(transaction Transaction) bool {
+	return (r.Name == "" || matchName(transaction.Name, r.Name, r.NameMatch, r.nameRegexp)) &&
+		matchesAny(r.SourceCategories, transaction.SourceCategory) &&
+		matchesAny(r.Directions, transaction.Direction) &&
+		matchesAny(r.PaymentMethods, transaction.PaymentMethod) &&
+		matchesAmount(transaction.Amount, r.MinAmount, r.MaxAmount) &&
+		matchesAny(r.AccountIDs, transaction.AccountID)
+}
+
+func (r FilterRule) hasConditions() bool {
+	return r.Name != "" || len(r.SourceCategories) > 0 || len(r.Directions) > 0 || len(r.PaymentMethods) > 0 ||
+		r.MinAmount != nil || r.MaxAmount != nil || len(r.AccountIDs) > 0
+}
+
+// normalizeFilterRules returns the profile's filter rules, or the default ones when it lists none.
+// An empty list keeps every transaction.
+func normalizeFilterRules(ingestProfile IngestProfile, ignoreSamePersonTransfers bool) ([]FilterRule, error) {
+	rules := ingestProfile.FilterRules
+	if rules == nil {
+		rules = DefaultFilterRules(ignoreSamePersonTransfers)
+	} else if ingestProfile.IgnoreSamePersonTransfers != nil {
+		return nil, errors.New("ignore same-person transfers only applies to the default filter rules")
+	}
+
+	normalized := make([]FilterRule, 0, len(rules))
+	for index, rule := range rules {
+		normalizedRule, err := normalizeFilterRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("filter rule %d: %w", index+1, err)
+		}
+
+		normalized = append(normalized, normalizedRule)
+	}
+
+	return normalized, nil
+}
+
+func normalizeFilterRule(rule FilterRule) (FilterRule, error) {
+	if rule.Reason == "" {
+		return FilterRule{}, errors.New("a reason is required")
+	}
+	if !rule.hasConditions() {
+		return FilterRule{}, errors.New("at least one condition is required")
+	}
+
+	nameMatch, nameRegexp, err := normalizeNameMatch(rule.Name, rule.NameMatch)
+	if err != nil {
+		return FilterRule{}, err
+	}
+	rule.NameMatch, rule.nameRegexp = nameMatch, nameRegexp
+
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return FilterRule{}, fmt.Errorf("min amount %v is greater than max amount %v", *rule.MinAmount, *rule.MaxAmount)
+	}
+	for _, direction := range rule.Directions {
+		if !slices.Contains(TransactionDirections, direction) {
+			return FilterRule{}, fmt.Errorf("direction %q is not supported", direction)
+		}
+	}
+	for _, paymentMethod := range rule.PaymentMethods {
+		if !slices.Contains(PaymentMethods, paymentMethod) {
+			return FilterRule{}, fmt.Errorf("payment method %q is not supported", paymentMethod)
+		}
+	}
+
+	rule.SourceCategories = slices.Clone(rule.SourceCategories)
+	rule.Directions = slices.Clone(rule.Directions)
+	rule.PaymentMethods = slices.Clone(rule.PaymentMethods)
+	rule.AccountIDs = slices.Clone(rule.AccountIDs)
+
+	return rule, nil
+}
@@ -3,16 +3,138 @@ package entity
 type IngestProfile struct {
 	ID                        string                   `json:"id"                                     validate:"required"`
 	Language                  Language                 `json:"language,omitempty"                     validate:"omitempty,oneof=en pt-BR"`
-	NotionToken               string                   `json:"notion_token"                           validate:"required"`
-	NotionPageID              string                   `json:"notion_page_id"                         validate:"required"`
+	SheetProvider             SheetProvider            `json:"sheet_provider,omitempty"               validate:"omitempty,oneof=notion google_sheets file sql"`
+	NotionToken               string                   `json:"notion_token,omitempty"                 validate:"required_if=SheetProvider notion,required_without=SheetProvider"`
+	NotionPageID              string                   `json:"notion_page_id,omitempty"               validate:"required_if=SheetProvider notion,required_without=SheetProvider"`
+	GoogleSheets              *GoogleSheetsConnection  `json:"google_sheets,omitempty"                validate:"required_if=SheetProvider google_sheets"`
+	File                      *FileConnection          `json:"file,omitempty"                         validate:"required_if=SheetProvider file"`
+	SQL                       *SQLConnection           `json:"sql,omitempty"                          validate:"required_if=SheetProvider sql"`
+	Categorizer               Categorizer              `json:"categorizer,omitempty"                  validate:"omitempty,oneof=llm heuristic"`
+	LLM                       *LLMConnection           `json:"llm,omitempty"`
 	PluggyClientID            string                   `json:"pluggy_client_id"                       validate:"required"`
 	PluggyClientSecret        string                   `json:"pluggy_client_secret"                   validate:"required"`
 	PluggyAccountIDs          []string                 `json:"pluggy_account_ids"                     validate:"required,min=1,dive,required"`
 	IgnoreSamePersonTransfers *bool                    `json:"ignore_same_person_transfers,omitempty"`
+	FilterRules               []FilterRule             `json:"filter_rules,omitempty"                 validate:"omitempty,dive"`
+	RefundMatching            *RefundMatching          `json:"refund_matching,omitempty"`
+	SyncMode                  SyncMode                 `json:"sync_mode,omitempty"                    validate:"omitempty,oneof=append reconcile"`
+	PreserveColumns           []TransactionColumn      `json:"preserve_columns,omitempty"             validate:"omitempty,dive,required"`
+	ReviewColumn              bool                     `json:"review_column,omitempty"`
+	InstallmentColumns        bool                     `json:"installment_columns,omitempty"`
+	IncludeCredits            bool                     `json:"include_credits,omitempty"`
 	Categories                map[Category]Color       `json:"categories"                             validate:"required,min=1,dive,keys,required,endkeys,required"`
 	CategoryMappings          map[string]Category      `json:"category_mappings"                      validate:"required,dive,keys,required,endkeys,required"`
+	SourceCategoryMappings    map[string]Category      `json:"source_category_mappings,omitempty"     validate:"omitempty,dive,keys,required,endkeys,required"`
+	CategoryKeywords          map[Category][]string    `json:"category_keywords,omitempty"            validate:"omitempty,dive,keys,required,endkeys,min=1,dive,required"`
+	CategoryRules             []CategoryRule           `json:"category_rules,omitempty"               validate:"omitempty,dive"`
+	SplitRules                []SplitRule              `json:"split_rules,omitempty"                  validate:"omitempty,dive"`
+	CategorizationChunkSize   int                      `json:"categorization_chunk_size,omitempty"    validate:"omitempty,gte=1"`
 	Fallback                  Category                 `json:"fallback,omitempty"`
+	IncomeCategories          map[Category]Color       `json:"income_categories,omitempty"            validate:"omitempty,dive,keys,required,endkeys,required"`
+	IncomeFallback            Category                 `json:"income_fallback,omitempty"`
 	BudgetGroups              map[BudgetGroup]Color    `json:"budget_groups,omitempty"                validate:"omitempty,min=1,dive,keys,required,endkeys,required"`
 	BudgetGroupMappings       map[Category]BudgetGroup `json:"budget_group_mappings,omitempty"        validate:"omitempty,dive,keys,required,endkeys,required"`
 	BudgetGroupFallback       BudgetGroup              `json:"budget_group_fallback,omitempty"`
 }
+
+type GoogleSheetsConnection struct {
+	SpreadsheetID string `json:"spreadsheet_id" validate:"required"`
+	ClientEmail   string `json:"client_email"   validate:"required,email"`
+	PrivateKey    string `json:"private_key"    validate:"required"`
+}
+
+type FileConnection struct {
+	Directory string     `json:"directory"        validate:"required"`
+	Format    FileFormat `json:"format,omitempty" validate:"omitempty,oneof=csv xlsx"`
+}
+
+func (c FileConnection) FormatOrDefault() FileFormat {
+	if c.Format == "" {
+		return DefaultFileFormat
+	}
+
+	return c.Format
+}
+
+type SQLConnection struct {
+	Driver SQLDriver `json:"driver" validate:"required,oneof=sqlite postgres"`
+	DSN    string    `json:"dsn"    validate:"required"`
+}
+
+type LLMConnection struct {
+	Provider        LLMProvider     `json:"provider,omitempty"         validate:"omitempty,oneof=openai anthropic gemini ollama"`
+	Model           string          `json:"model,omitempty"            validate:"required_if=Provider anthropic,required_if=Provider gemini,required_if=Provider ollama"`
+	ReasoningEffort ReasoningEffort `json:"reasoning_effort,omitempty" validate:"omitempty,oneof=minimal low medium high"`
+	Temperature     *float64        `json:"temperature,omitempty"      validate:"omitempty,gte=0,lte=2"`
+	BaseURL         string          `json:"base_url,omitempty"         validate:"omitempty,url"`
+	APIKey          string          `json:"api_key,omitempty"          validate:"required_if=Provider anthropic,required_if=Provider gemini"`
+}
+
+func (p IngestProfile) SheetProviderOrDefault() SheetProvider {
+	if p.SheetProvider == "" {
+		return DefaultSheetProvider
+	}
+
+	return p.SheetProvider
+}
+
+func (p IngestProfile) hasCompleteSheetSettings() bool {
+	switch p.SheetProviderOrDefault() {
+	case SheetProviderNotion:
+		return p.NotionToken != "" && p.NotionPageID != ""
+	case SheetProviderGoogleSheets:
+		return p.GoogleSheets != nil && p.GoogleSheets.SpreadsheetID != "" &&
+			p.GoogleSheets.ClientEmail != "" && p.GoogleSheets.PrivateKey != ""
+	case SheetProviderFile:
+		return p.File != nil && p.File.Directory != ""
+	case SheetProviderSQL:
+		return p.SQL != nil && p.SQL.Driver != "" && p.SQL.DSN != ""
+	default:
+		return false
+	}
+}
+
+func (p IngestProfile) CategorizerOrDefault() Categorizer {
+	if p.Categorizer == "" {
+		return DefaultCategorizer
+	}
+
+	return p.Categorizer
+}
+
+// UsesLLM reports whether the profile sends transactions to an LLM for categorization.
+func (p IngestProfile) UsesLLM() bool {
+	return p.CategorizerOrDefault() == CategorizerLLM
+}
+
+// LLMConnectionOrDefault returns the profile's LLM connection, which uses the default provider when
+// the profile or its connection omits it.
+func (p IngestProfile) LLMConnectionOrDefault() LLMConnection {
+	connection := LLMConnection{}
+	if p.LLM != nil {
+		connection = *p.LLM
+	}
+	if connection.Provider == "" {
+		connection.Provider = DefaultLLMProvider
+	}
+
+	return connection
+}
+
+func (p IngestProfile) hasCompleteLLMSettings() bool {
+	if !p.UsesLLM() {
+		return true
+	}
+
+	connection := p.LLMConnectionOrDefault()
+	switch connection.Provider {
+	case LLMProviderOpenAI:
+		return true
+	case LLMProviderAnthropic, LLMProviderGemini:
+		return connection.Model != "" && connection.APIKey != ""
+	case LLMProviderOllama:
+		return connection.Model != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// installmentMarker matches the installment marker card statements add to the name of a purchase
+// paid in installments, such as "PARC 03/10", "PARC03/10" or "Parcela 3 de 10".
+var installmentMarker = regexp.MustCompile(`(?i)\bparc(?:ela)?\.?\s*(\d{1,3})\s*(?:/|de)\s*(\d{1,3})\b`)
+
+// parseInstallment reads the installment marker of a name. It returns the name without the marker,
+// and reports false when the name has no valid marker.
+func parseInstallment(name string) (string, int, int, bool) {
+	match := installmentMarker.FindStringSubmatchIndex(name)
+	if match == nil {
+		return name, 0, 0, false
+	}
+
+	number, numberErr := strconv.Atoi(name[match[2]:match[3]])
+	count, countErr := strconv.Atoi(name[match[4]:match[5]])
+	if numberErr != nil || countErr != nil || count < 2 || number < 1 || number > count {
+		return name, 0, 0, false
+	}
+
+	base := strings.Join(strings.Fields(name[:match[0]]+" "+name[match[1]:]), " ")
+
+	return base, number, count, true
+}
+
+// applyInstallment fills the installment fields of a card transaction from the card metadata, or
+// from the installment marker of its name when the metadata lacks them. Without the total amount
+// in the metadata, the purchase amount assumes equal installments.
+func applyInstallment(transaction *Transaction, input TransactionInput) {
+	number, count := 0, 0
+	if input.InstallmentNumber != nil && input.TotalInstallments != nil {
+		number, count = *input.InstallmentNumber, *input.TotalInstallments
+	} else if _, parsedNumber, parsedCount, ok := parseInstallment(transaction.Name); ok {
+		number, count = parsedNumber, parsedCount
+	}
+	if count < 2 || number < 1 || number > count {
+		return
+	}
+
+	transaction.InstallmentNumber = number
+	transaction.TotalInstallments = count
+	if input.TotalAmount != nil && *input.TotalAmount != 0 {
+		transaction.PurchaseAmount = math.Abs(*input.TotalAmount)
+	} else {
+		transaction.PurchaseAmount = math.Round(transaction.Amount*float64(count)*100) / 100
+	}
+}
+
+// CategorizationName is the name the transaction is categorized by: its name without the
+// installment marker, so every installment of a purchase shares the classifications of its name.
+func (t Transaction) CategorizationName() string {
+	base, _, _, ok := parseInstallment(t.Name)
+	if !ok || base == "" {
+		return t.Name
+	}
+
+	return base
+}
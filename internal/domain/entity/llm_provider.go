@@ -0,0 +1,35 @@
+package entity
+
+type LLMProvider string
+
+const (
+	LLMProviderOpenAI    LLMProvider = "openai"
+	LLMProviderAnthropic LLMProvider = "anthropic"
+	LLMProviderGemini    LLMProvider = "gemini"
+	LLMProviderOllama    LLMProvider = "ollama"
+	DefaultLLMProvider   LLMProvider = LLMProviderOpenAI
+)
+
+func (provider LLMProvider) IsValid() bool {
+	switch provider {
+	case LLMProviderOpenAI, LLMProviderAnthropic, LLMProviderGemini, LLMProviderOllama:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsReasoningEffort reports whether the provider accepts a reasoning effort, which is only
+// sent through the OpenAI-compatible chat completions API.
+func (provider LLMProvider) SupportsReasoningEffort() bool {
+	return provider == LLMProviderOpenAI || provider == LLMProviderOllama
+}
+
+type ReasoningEffort string
+
+const (
+	ReasoningEffortMinimal ReasoningEffort = "minimal"
+	ReasoningEffortLow     ReasoningEffort = "low"
+	ReasoningEffortMedium  ReasoningEffort = "medium"
+	ReasoningEffortHigh    ReasoningEffort = "high"
+)
@@ -0,0 +1,66 @@
+package entity
+
+import "fmt"
+
+// RefundMode controls what an ingest does with a credit card refund once it is paired with the
+// purchase it returns.
+type RefundMode string
+
+const (
+	// RefundModeOff leaves refunds unmatched, so they are filtered out like any other credit.
+	RefundModeOff RefundMode = ""
+	// RefundModeNet subtracts the refund from its purchase and leaves the refund out of the sheet.
+	RefundModeNet RefundMode = "net"
+	// RefundModeMark writes the refund as a row of its own, linking it and its purchase.
+	RefundModeMark RefundMode = "mark"
+	// RefundModeNegative writes the refund like RefundModeMark, with a negative amount.
+	RefundModeNegative RefundMode = "negative"
+)
+
+// DefaultRefundWindowDays is how many days after a purchase its refund may arrive when the profile
+// does not say.
+const DefaultRefundWindowDays = 90
+
+func (mode RefundMode) IsValid() bool {
+	switch mode {
+	case RefundModeNet, RefundModeMark, RefundModeNegative:
+		return true
+	default:
+		return false
+	}
+}
+
+// RefundMatching pairs credit card refunds with the purchases they return. A refund matches a
+// purchase on the same card with a similar name, dated at most WindowDays before it, whose amount
+// covers the refund, so partial refunds match too.
+type RefundMatching struct {
+	Mode       RefundMode `json:"mode"                  validate:"required,oneof=net mark negative"`
+	WindowDays int        `json:"window_days,omitempty" validate:"omitempty,gte=1"`
+}
+
+func normalizeRefundMatching(ingestProfile IngestProfile) (RefundMode, int, error) {
+	if ingestProfile.RefundMatching == nil {
+		return RefundModeOff, 0, nil
+	}
+
+	matching := *ingestProfile.RefundMatching
+	if !matching.Mode.IsValid() {
+		return "", 0, fmt.Errorf(
+			"unsupported refund mode %q (supported: %s, %s, %s)",
+			matching.Mode,
+			RefundModeNet,
+			RefundModeMark,
+			RefundModeNegative,
+		)
+	}
+
+	windowDays := matching.WindowDays
+	if windowDays == 0 {
+		windowDays = DefaultRefundWindowDays
+	}
+	if windowDays < 0 {
+		return "", 0, fmt.Errorf("refund window days must be positive, got %d", windowDays)
+	}
+
+	return matching.Mode, windowDays, nil
+}
@@ -5,16 +5,34 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strings"
 )
 
 type IngestProfileSettings struct {
 	ID                        string
 	Language                  Language
 	IgnoreSamePersonTransfers bool
+	FilterRules               []FilterRule
+	RefundMode                RefundMode
+	RefundWindowDays          int
+	SyncMode                  SyncMode
+	PreservedColumns          []TransactionColumn
+	ReviewColumn              bool
+	InstallmentColumns        bool
 	Categories                []Category
 	ColorsByCategory          map[Category]Color
 	Mappings                  map[string]Category
+	Categorizer               Categorizer
+	SourceCategoryMappings    map[string]Category
+	CategoryKeywords          map[Category][]string
+	CategoryRules             []CategoryRule
+	SplitRules                []SplitRule
+	CategorizationChunkSize   int
 	Fallback                  Category
+	IncludeCredits            bool
+	IncomeCategories          []Category
+	IncomeFallback            Category
+	Direction                 TransactionDirection
 	BudgetGroups              []BudgetGroup
 	ColorsByBudgetGroup       map[BudgetGroup]Color
 	BudgetGroupMappings       map[Category]BudgetGroup
@@ -63,6 +81,21 @@ func newIngestProfileSettings(ingestProfile IngestProfile) (IngestProfileSetting
 		ignoreSamePersonTransfers = *ingestProfile.IgnoreSamePersonTransfers
 	}
 
+	filterRules, err := normalizeFilterRules(ingestProfile, ignoreSamePersonTransfers)
+	if err != nil {
+		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
+	}
+
+	refundMode, refundWindowDays, err := normalizeRefundMatching(ingestProfile)
+	if err != nil {
+		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
+	}
+
+	syncMode, preservedColumns, err := normalizeSyncMode(ingestProfile)
+	if err != nil {
+		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
+	}
+
 	if len(ingestProfile.Categories) == 0 {
 		return IngestProfileSettings{}, fmt.Errorf(
 			"ingest profile %q: at least one category is required",
@@ -86,29 +119,84 @@ func newIngestProfileSettings(ingestProfile IngestProfile) (IngestProfileSetting
 		colorsByCategory[fallback] = Gray
 	}
 
-	if err := ValidateCategories(colorsByCategory, ingestProfile.CategoryMappings); err != nil {
+	incomeCategories, incomeFallback, err := normalizeIncomeCategories(ingestProfile, colorsByCategory)
+	if err != nil {
+		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
+	}
+
+	if err := ValidateCategories(
+		colorsByCategory,
+		ingestProfile.CategoryMappings,
+		ingestProfile.SourceCategoryMappings,
+	); err != nil {
+		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
+	}
+	if err := validateCategoryKeywords(colorsByCategory, ingestProfile.CategoryKeywords); err != nil {
 		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
 	}
 
 	categories := make([]Category, 0, len(colorsByCategory))
 	for category := range colorsByCategory {
-		categories = append(categories, category)
+		if !slices.Contains(incomeCategories, category) {
+			categories = append(categories, category)
+		}
 	}
 	slices.Sort(categories)
 
+	categorizationChunkSize := ingestProfile.CategorizationChunkSize
+	if categorizationChunkSize == 0 {
+		categorizationChunkSize = DefaultCategorizationChunkSize
+	}
+	if categorizationChunkSize < 0 {
+		return IngestProfileSettings{}, fmt.Errorf(
+			"ingest profile %q: categorization chunk size must be positive",
+			ingestProfile.ID,
+		)
+	}
+
 	budgetGroupSettings, err := normalizeBudgetGroups(ingestProfile, colorsByCategory)
 	if err != nil {
 		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
 	}
 
+	categoryRules, err := normalizeCategoryRules(
+		ingestProfile.CategoryRules,
+		colorsByCategory,
+		budgetGroupSettings.colorsByGroup,
+	)
+	if err != nil {
+		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
+	}
+
+	splitRules, err := normalizeSplitRules(ingestProfile.SplitRules, colorsByCategory, budgetGroupSettings)
+	if err != nil {
+		return IngestProfileSettings{}, fmt.Errorf("ingest profile %q: %w", ingestProfile.ID, err)
+	}
+
 	return IngestProfileSettings{
 		ID:                        ingestProfile.ID,
 		Language:                  language,
 		IgnoreSamePersonTransfers: ignoreSamePersonTransfers,
+		FilterRules:               filterRules,
+		RefundMode:                refundMode,
+		RefundWindowDays:          refundWindowDays,
+		SyncMode:                  syncMode,
+		PreservedColumns:          preservedColumns,
+		ReviewColumn:              ingestProfile.ReviewColumn,
+		InstallmentColumns:        ingestProfile.InstallmentColumns,
 		Categories:                categories,
 		ColorsByCategory:          colorsByCategory,
 		Mappings:                  maps.Clone(ingestProfile.CategoryMappings),
+		Categorizer:               ingestProfile.CategorizerOrDefault(),
+		SourceCategoryMappings:    maps.Clone(ingestProfile.SourceCategoryMappings),
+		CategoryKeywords:          maps.Clone(ingestProfile.CategoryKeywords),
+		CategoryRules:             categoryRules,
+		SplitRules:                splitRules,
+		CategorizationChunkSize:   categorizationChunkSize,
 		Fallback:                  fallback,
+		IncludeCredits:            ingestProfile.IncludeCredits,
+		IncomeCategories:          incomeCategories,
+		IncomeFallback:            incomeFallback,
 		BudgetGroups:              budgetGroupSettings.groups,
 		ColorsByBudgetGroup:       budgetGroupSettings.colorsByGroup,
 		BudgetGroupMappings:       budgetGroupSettings.mappings,
@@ -116,6 +204,130 @@ func newIngestProfileSettings(ingestProfile IngestProfile) (IngestProfileSetting
 	}, nil
 }
 
+// ForDirection returns the settings that categorize the transactions of the direction, with
+// Direction set. In profiles that include credits, credits take the income categories and fallback
+// and debits the others, and each direction only sees the mappings, keywords and rules of its own
+// categories. ColorsByCategory keeps the colors of both.
+func (s IngestProfileSettings) ForDirection(direction TransactionDirection) IngestProfileSettings {
+	if !s.IncludeCredits {
+		return s
+	}
+
+	scoped := s
+	scoped.Direction = TransactionDirectionDebit
+	if direction == TransactionDirectionCredit {
+		scoped.Direction = TransactionDirectionCredit
+		scoped.Categories = s.IncomeCategories
+		scoped.Fallback = s.IncomeFallback
+	}
+
+	scopes := func(category Category) bool {
+		return slices.Contains(scoped.Categories, category)
+	}
+	scoped.Mappings = scopedCategoryValues(s.Mappings, scopes)
+	scoped.SourceCategoryMappings = scopedCategoryValues(s.SourceCategoryMappings, scopes)
+	scoped.CategoryKeywords = maps.Clone(s.CategoryKeywords)
+	maps.DeleteFunc(scoped.CategoryKeywords, func(category Category, _ []string) bool {
+		return !scopes(category)
+	})
+	scoped.BudgetGroupMappings = maps.Clone(s.BudgetGroupMappings)
+	maps.DeleteFunc(scoped.BudgetGroupMappings, func(category Category, _ BudgetGroup) bool {
+		return !scopes(category)
+	})
+	scoped.CategoryRules = slices.DeleteFunc(slices.Clone(s.CategoryRules), func(rule CategoryRule) bool {
+		return rule.Category != "" && !scopes(rule.Category)
+	})
+	scoped.SplitRules = slices.DeleteFunc(slices.Clone(s.SplitRules), func(rule SplitRule) bool {
+		return slices.ContainsFunc(rule.Parts, func(part SplitPart) bool {
+			return !scopes(part.Category)
+		})
+	})
+
+	return scoped
+}
+
+func scopedCategoryValues(categoryByName map[string]Category, scopes func(Category) bool) map[string]Category {
+	scoped := maps.Clone(categoryByName)
+	maps.DeleteFunc(scoped, func(_ string, category Category) bool {
+		return !scopes(category)
+	})
+
+	return scoped
+}
+
+// normalizeIncomeCategories adds the income categories and their fallback to the colors of the
+// profile's categories and returns them sorted, for profiles that include credits. Income
+// categories cannot also be expense categories, so the direction of a category is never ambiguous.
+func normalizeIncomeCategories(
+	ingestProfile IngestProfile,
+	colorsByCategory map[Category]Color,
+) ([]Category, Category, error) {
+	if !ingestProfile.IncludeCredits {
+		if ingestProfile.IncomeCategories != nil || ingestProfile.IncomeFallback != "" {
+			return nil, "", errors.New("income categories and fallback require including credits")
+		}
+
+		return nil, "", nil
+	}
+
+	fallback := ingestProfile.IncomeFallback
+	if fallback == "" {
+		fallback = DefaultIncomeFallbackCategory
+	}
+
+	colorsByIncomeCategory := maps.Clone(ingestProfile.IncomeCategories)
+	if colorsByIncomeCategory == nil {
+		colorsByIncomeCategory = make(map[Category]Color, 1)
+	}
+	if _, exists := colorsByIncomeCategory[fallback]; !exists {
+		colorsByIncomeCategory[fallback] = Gray
+	}
+
+	categories := make([]Category, 0, len(colorsByIncomeCategory))
+	for category, color := range colorsByIncomeCategory {
+		if _, exists := colorsByCategory[category]; exists {
+			return nil, "", fmt.Errorf("category %q is both an expense and an income category", category)
+		}
+
+		colorsByCategory[category] = color
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+
+	return categories, fallback, nil
+}
+
+func normalizeSyncMode(ingestProfile IngestProfile) (SyncMode, []TransactionColumn, error) {
+	syncMode := ingestProfile.SyncMode
+	if syncMode == "" {
+		syncMode = DefaultSyncMode
+	}
+	if !syncMode.IsValid() {
+		return "", nil, fmt.Errorf(
+			"unsupported sync mode %q (supported: %s, %s)",
+			syncMode,
+			SyncModeAppend,
+			SyncModeReconcile,
+		)
+	}
+
+	if len(ingestProfile.PreserveColumns) > 0 && syncMode != SyncModeReconcile {
+		return "", nil, fmt.Errorf("preserve columns require the %s sync mode", SyncModeReconcile)
+	}
+
+	preservedColumns := make([]TransactionColumn, 0, len(ingestProfile.PreserveColumns))
+	for _, column := range ingestProfile.PreserveColumns {
+		if !column.IsValid() {
+			return "", nil, fmt.Errorf("preserve column %q is not a transaction column", column)
+		}
+		if !slices.Contains(preservedColumns, column) {
+			preservedColumns = append(preservedColumns, column)
+		}
+	}
+
+	return syncMode, preservedColumns, nil
+}
+
 type normalizedBudgetGroupSettings struct {
 	groups        []BudgetGroup
 	colorsByGroup map[BudgetGroup]Color
@@ -183,7 +395,55 @@ func ValidateIngestProfiles(ingestProfiles []IngestProfile) error {
 
 	ingestProfileIDs := make(map[string]struct{}, len(ingestProfiles))
 	for _, ingestProfile := range ingestProfiles {
-		if ingestProfile.ID == "" || ingestProfile.NotionToken == "" || ingestProfile.NotionPageID == "" ||
+		if !ingestProfile.SheetProviderOrDefault().IsValid() {
+			return fmt.Errorf(
+				"ingest profile %q: unsupported sheet provider %q (supported: %s, %s, %s, %s)",
+				ingestProfile.ID,
+				ingestProfile.SheetProvider,
+				SheetProviderNotion,
+				SheetProviderGoogleSheets,
+				SheetProviderFile,
+				SheetProviderSQL,
+			)
+		}
+		if !ingestProfile.CategorizerOrDefault().IsValid() {
+			return fmt.Errorf(
+				"ingest profile %q: unsupported categorizer %q (supported: %s, %s)",
+				ingestProfile.ID,
+				ingestProfile.Categorizer,
+				CategorizerLLM,
+				CategorizerHeuristic,
+			)
+		}
+		if !ingestProfile.UsesLLM() && ingestProfile.LLM != nil {
+			return fmt.Errorf(
+				"ingest profile %q: LLM settings require the %s categorizer",
+				ingestProfile.ID,
+				CategorizerLLM,
+			)
+		}
+
+		llmConnection := ingestProfile.LLMConnectionOrDefault()
+		if !llmConnection.Provider.IsValid() {
+			return fmt.Errorf(
+				"ingest profile %q: unsupported LLM provider %q (supported: %s, %s, %s, %s)",
+				ingestProfile.ID,
+				llmConnection.Provider,
+				LLMProviderOpenAI,
+				LLMProviderAnthropic,
+				LLMProviderGemini,
+				LLMProviderOllama,
+			)
+		}
+		if llmConnection.ReasoningEffort != "" && !llmConnection.Provider.SupportsReasoningEffort() {
+			return fmt.Errorf(
+				"ingest profile %q: the %s LLM provider does not support a reasoning effort",
+				ingestProfile.ID,
+				llmConnection.Provider,
+			)
+		}
+		if ingestProfile.ID == "" || !ingestProfile.hasCompleteSheetSettings() ||
+			!ingestProfile.hasCompleteLLMSettings() ||
 			ingestProfile.PluggyClientID == "" || ingestProfile.PluggyClientSecret == "" ||
 			len(ingestProfile.PluggyAccountIDs) == 0 {
 			return fmt.Errorf("ingest profile %q has incomplete integration settings", ingestProfile.ID)
@@ -202,6 +462,7 @@ func ValidateIngestProfiles(ingestProfiles []IngestProfile) error {
 func ValidateCategories(
 	colorsByCategory map[Category]Color,
 	mappings map[string]Category,
+	sourceCategoryMappings map[string]Category,
 ) error {
 	if len(colorsByCategory) == 0 {
 		return errors.New("at least one category is required")
@@ -227,6 +488,32 @@ func ValidateCategories(
 		}
 	}
 
+	for sourceCategory, category := range sourceCategoryMappings {
+		if sourceCategory == "" {
+			return errors.New("mapping source category cannot be empty")
+		}
+
+		if _, ok := colorsByCategory[category]; !ok {
+			return fmt.Errorf("source category mapping category %q is not configured", category)
+		}
+	}
+
+	return nil
+}
+
+func validateCategoryKeywords(colorsByCategory map[Category]Color, keywordsByCategory map[Category][]string) error {
+	for category, keywords := range keywordsByCategory {
+		if _, ok := colorsByCategory[category]; !ok {
+			return fmt.Errorf("keyword category %q is not configured", category)
+		}
+		if len(keywords) == 0 {
+			return fmt.Errorf("keywords of category %q cannot be empty", category)
+		}
+		if slices.ContainsFunc(keywords, func(keyword string) bool { return strings.TrimSpace(keyword) == "" }) {
+			return fmt.Errorf("keyword of category %q cannot be empty", category)
+		}
+	}
+
 	return nil
 }
 
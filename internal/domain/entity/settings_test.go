@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"fmt"
 	"slices"
 	"testing"
 )
@@ -28,6 +29,8 @@ func TestNewIngestSettingsNormalizesEachIngestProfile(t *testing.T) {
 	second.CategoryMappings = map[string]Category{"Unknown store": "Outros"}
 	second.Fallback = "Outros"
 	second.Language = LanguagePortugueseBrazil
+	second.CategorizationChunkSize = 25
+	second.ReviewColumn = true
 
 	settings, err := NewIngestSettings([]IngestProfile{first, second})
 	if err != nil {
@@ -46,6 +49,9 @@ func TestNewIngestSettingsNormalizesEachIngestProfile(t *testing.T) {
 	if firstSettings.IgnoreSamePersonTransfers {
 		t.Fatal("first profile ignores same-person transfers, want false")
 	}
+	if firstSettings.SyncMode != SyncModeAppend || len(firstSettings.PreservedColumns) != 0 {
+		t.Fatalf("first sync mode = %q, preserved columns = %#v", firstSettings.SyncMode, firstSettings.PreservedColumns)
+	}
 	if len(firstSettings.Categories) != 2 || firstSettings.Categories[0] != "Food" ||
 		firstSettings.Categories[1] != DefaultFallbackCategory {
 		t.Fatalf("first categories = %#v", firstSettings.Categories)
@@ -54,6 +60,9 @@ func TestNewIngestSettingsNormalizesEachIngestProfile(t *testing.T) {
 		firstSettings.ColorsByCategory[DefaultFallbackCategory] != Gray {
 		t.Fatalf("first settings = %#v", firstSettings)
 	}
+	if firstSettings.CategorizationChunkSize != DefaultCategorizationChunkSize {
+		t.Fatalf("first categorization chunk size = %d", firstSettings.CategorizationChunkSize)
+	}
 	if _, mutated := first.Categories[DefaultFallbackCategory]; mutated {
 		t.Fatal("NewIngestSettings() mutated the input categories")
 	}
@@ -69,6 +78,9 @@ func TestNewIngestSettingsNormalizesEachIngestProfile(t *testing.T) {
 	if !secondSettings.IgnoreSamePersonTransfers {
 		t.Fatal("second profile ignores same-person transfers = false, want default true")
 	}
+	if firstSettings.ReviewColumn || !secondSettings.ReviewColumn {
+		t.Fatalf("review columns = %t, %t, want false, true", firstSettings.ReviewColumn, secondSettings.ReviewColumn)
+	}
 	if len(secondSettings.Categories) != 2 || secondSettings.Categories[0] != "Education" ||
 		secondSettings.Categories[1] != "Outros" {
 		t.Fatalf("second categories = %#v", secondSettings.Categories)
@@ -76,6 +88,9 @@ func TestNewIngestSettingsNormalizesEachIngestProfile(t *testing.T) {
 	if secondSettings.Fallback != "Outros" || secondSettings.ColorsByCategory["Outros"] != Gray {
 		t.Fatalf("second settings = %#v", secondSettings)
 	}
+	if secondSettings.CategorizationChunkSize != 25 {
+		t.Fatalf("second categorization chunk size = %d, want 25", secondSettings.CategorizationChunkSize)
+	}
 }
 
 func TestNewIngestSettingsPreservesConfiguredFallbackColor(t *testing.T) {
@@ -142,6 +157,334 @@ func TestNewIngestSettingsPreservesConfiguredBudgetGroupFallbackColor(t *testing
 	}
 }
 
+func TestNewIngestSettingsNormalizesReconcileSyncMode(t *testing.T) {
+	ingestProfile := validIngestProfile()
+	ingestProfile.SyncMode = SyncModeReconcile
+	ingestProfile.PreserveColumns = []TransactionColumn{
+		TransactionColumnCategory,
+		TransactionColumnName,
+		TransactionColumnCategory,
+	}
+
+	settings, err := NewIngestSettings([]IngestProfile{ingestProfile})
+	if err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+
+	ingestProfileSettings := settings.IngestProfiles[0]
+	if ingestProfileSettings.SyncMode != SyncModeReconcile ||
+		!slices.Equal(
+			ingestProfileSettings.PreservedColumns,
+			[]TransactionColumn{TransactionColumnCategory, TransactionColumnName},
+		) {
+		t.Fatalf(
+			"sync mode = %q, preserved columns = %#v",
+			ingestProfileSettings.SyncMode,
+			ingestProfileSettings.PreservedColumns,
+		)
+	}
+}
+
+func TestNewIngestSettingsNormalizesCategoryRules(t *testing.T) {
+	ingestProfile := validIngestProfile()
+	ingestProfile.BudgetGroups = map[BudgetGroup]Color{"Needs": Red}
+	ingestProfile.BudgetGroupMappings = map[Category]BudgetGroup{}
+	ingestProfile.CategoryRules = []CategoryRule{
+		{Name: "Uber", Category: "Food"},
+		{Name: "uber eats", NameMatch: NameMatchCaseInsensitive, BudgetGroup: "Needs"},
+		{Name: "PAG*", NameMatch: NameMatchPrefix, Category: "Food"},
+		{Name: "Market", NameMatch: NameMatchContains, Category: "Food"},
+		{Name: `^PARC \d+/\d+`, NameMatch: NameMatchRegex, Category: "Food"},
+		{
+			MinAmount:        new(10.0),
+			MaxAmount:        new(20.0),
+			PaymentMethods:   []PaymentMethod{PaymentMethodCreditCard},
+			CardLastDigits:   []string{"1234"},
+			SourceCategories: []string{"Groceries"},
+			Category:         "Food",
+		},
+	}
+
+	settings, err := NewIngestSettings([]IngestProfile{ingestProfile})
+	if err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+
+	rules := settings.IngestProfiles[0].CategoryRules
+	if len(rules) != len(ingestProfile.CategoryRules) || rules[0].NameMatch != DefaultNameMatch {
+		t.Fatalf("category rules = %#v", rules)
+	}
+
+	card := "1234"
+	otherCard := "9999"
+	tests := []struct {
+		name        string
+		rule        CategoryRule
+		transaction Transaction
+		want        bool
+	}{
+		{name: "exact", rule: rules[0], transaction: Transaction{Name: "Uber"}, want: true},
+		{name: "exact mismatch", rule: rules[0], transaction: Transaction{Name: "Uber Eats"}},
+		{name: "exact installment", rule: rules[0], transaction: Transaction{Name: "Uber PARC 01/02"}, want: true},
+		{name: "case insensitive", rule: rules[1], transaction: Transaction{Name: "UBER EATS"}, want: true},
+		{name: "prefix", rule: rules[2], transaction: Transaction{Name: "PAG*Store"}, want: true},
+		{name: "prefix mismatch", rule: rules[2], transaction: Transaction{Name: "Store PAG*"}},
+		{name: "contains", rule: rules[3], transaction: Transaction{Name: "Super Market SP"}, want: true},
+		{name: "regex", rule: rules[4], transaction: Transaction{Name: "PARC 03/10 Store"}, want: true},
+		{name: "regex mismatch", rule: rules[4], transaction: Transaction{Name: "Store PARC 03/10"}},
+		{
+			name: "conditions",
+			rule: rules[5],
+			transaction: Transaction{
+				Name:           "Anything",
+				Amount:         15,
+				PaymentMethod:  PaymentMethodCreditCard,
+				CardLastDigits: &card,
+				SourceCategory: "Groceries",
+			},
+			want: true,
+		},
+		{
+			name: "amount out of range",
+			rule: rules[5],
+			transaction: Transaction{
+				Amount:         25,
+				PaymentMethod:  PaymentMethodCreditCard,
+				CardLastDigits: &card,
+				SourceCategory: "Groceries",
+			},
+		},
+		{
+			name: "other card",
+			rule: rules[5],
+			transaction: Transaction{
+				Amount:         15,
+				PaymentMethod:  PaymentMethodCreditCard,
+				CardLastDigits: &otherCard,
+				SourceCategory: "Groceries",
+			},
+		},
+		{
+			name: "other source category",
+			rule: rules[5],
+			transaction: Transaction{
+				Amount:         15,
+				PaymentMethod:  PaymentMethodCreditCard,
+				CardLastDigits: &card,
+				SourceCategory: "Restaurants",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.rule.Matches(test.transaction); got != test.want {
+				t.Fatalf("Matches() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewIngestSettingsNormalizesSplitRules(t *testing.T) {
+	ingestProfile := validIngestProfile()
+	ingestProfile.Categories = map[Category]Color{"Food": Red, "Home": Blue}
+	ingestProfile.BudgetGroups = map[BudgetGroup]Color{"Needs": Red, "Wants": Blue}
+	ingestProfile.BudgetGroupMappings = map[Category]BudgetGroup{"Food": "Needs"}
+	ingestProfile.SplitRules = []SplitRule{
+		{
+			Name: "Market",
+			Parts: []SplitPart{
+				{Category: "Food", Ratio: new(1.0 / 3)},
+				{Category: "Home", Ratio: new(2.0 / 3), BudgetGroup: "Wants"},
+			},
+		},
+		{
+			Name:      "^RENT",
+			NameMatch: NameMatchRegex,
+			Parts: []SplitPart{
+				{Category: "Home", Amount: new(100.0)},
+				{Category: "Food"},
+			},
+		},
+	}
+
+	settings, err := NewIngestSettings([]IngestProfile{ingestProfile})
+	if err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+
+	rules := settings.IngestProfiles[0].SplitRules
+	if len(rules) != 2 || rules[0].NameMatch != DefaultNameMatch {
+		t.Fatalf("split rules = %#v", rules)
+	}
+	if rules[0].Parts[0].BudgetGroup != "Needs" || rules[0].Parts[1].BudgetGroup != "Wants" ||
+		rules[1].Parts[0].BudgetGroup != DefaultFallbackBudgetGroup {
+		t.Fatalf("split rule budget groups = %#v, %#v", rules[0].Parts, rules[1].Parts)
+	}
+	if !rules[1].Matches(Transaction{Name: "RENT 10/2026"}) || rules[1].Matches(Transaction{Name: "Market"}) {
+		t.Fatal("regex split rule matches unexpected names")
+	}
+
+	tests := []struct {
+		name        string
+		rule        SplitRule
+		transaction Transaction
+		want        []float64
+	}{
+		{
+			name:        "ratios keep the remainder in the last part",
+			rule:        rules[0],
+			transaction: Transaction{Name: "Market", Amount: 10},
+			want:        []float64{3.33, 6.67},
+		},
+		{
+			name:        "amount with rest",
+			rule:        rules[1],
+			transaction: Transaction{Name: "RENT", Amount: 150.5, ExternalID: "rent"},
+			want:        []float64{100, 50.5},
+		},
+		{
+			name:        "amounts leave nothing for the rest",
+			rule:        rules[1],
+			transaction: Transaction{Name: "RENT", Amount: 100},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts, ok := test.rule.Split(test.transaction)
+			if ok != (test.want != nil) {
+				t.Fatalf("Split() ok = %t, want %t", ok, test.want != nil)
+			}
+
+			amounts := make([]float64, 0, len(parts))
+			for index, part := range parts {
+				amounts = append(amounts, part.Amount)
+				if part.ParentID != test.transaction.ID() || part.Category != test.rule.Parts[index].Category {
+					t.Fatalf("part %d = %#v", index, part)
+				}
+				if test.transaction.ExternalID != "" &&
+					part.ExternalID != fmt.Sprintf("%s#%d", test.transaction.ExternalID, index+1) {
+					t.Fatalf("part %d external ID = %q", index, part.ExternalID)
+				}
+				if part.SourceID() != test.transaction.ID() {
+					t.Fatalf("part %d source ID = %q, want %q", index, part.SourceID(), test.transaction.ID())
+				}
+			}
+			if ok && !slices.Equal(amounts, test.want) {
+				t.Fatalf("Split() amounts = %v, want %v", amounts, test.want)
+			}
+		})
+	}
+}
+
+func TestNewIngestSettingsNormalizesIncomeCategories(t *testing.T) {
+	ingestProfile := validIngestProfile()
+	ingestProfile.IncludeCredits = true
+	ingestProfile.IncomeCategories = map[Category]Color{"Salary": Green}
+	ingestProfile.CategoryMappings = map[string]Category{"Market": "Food", "ACME PAYROLL": "Salary"}
+	ingestProfile.CategoryKeywords = map[Category][]string{"Food": {"market"}, "Salary": {"payroll"}}
+	ingestProfile.CategoryRules = []CategoryRule{
+		{Name: "Refund", Category: "Salary"},
+		{Name: "Uber", Category: "Food"},
+	}
+
+	settings, err := NewIngestSettings([]IngestProfile{ingestProfile})
+	if err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+
+	profileSettings := settings.IngestProfiles[0]
+	if !slices.Equal(profileSettings.Categories, []Category{"Food", DefaultFallbackCategory}) ||
+		!slices.Equal(profileSettings.IncomeCategories, []Category{DefaultIncomeFallbackCategory, "Salary"}) ||
+		profileSettings.IncomeFallback != DefaultIncomeFallbackCategory ||
+		profileSettings.ColorsByCategory["Salary"] != Green ||
+		profileSettings.ColorsByCategory[DefaultIncomeFallbackCategory] != Gray {
+		t.Fatalf("settings = %#v", profileSettings)
+	}
+
+	credits := profileSettings.ForDirection(TransactionDirectionCredit)
+	if credits.Direction != TransactionDirectionCredit ||
+		!slices.Equal(credits.Categories, profileSettings.IncomeCategories) ||
+		credits.Fallback != DefaultIncomeFallbackCategory ||
+		len(credits.Mappings) != 1 || credits.Mappings["ACME PAYROLL"] != "Salary" ||
+		len(credits.CategoryKeywords) != 1 || len(credits.CategoryKeywords["Salary"]) != 1 ||
+		len(credits.CategoryRules) != 1 || credits.CategoryRules[0].Name != "Refund" {
+		t.Fatalf("credit settings = %#v", credits)
+	}
+
+	debits := profileSettings.ForDirection(TransactionDirectionDebit)
+	if debits.Direction != TransactionDirectionDebit ||
+		!slices.Equal(debits.Categories, profileSettings.Categories) ||
+		debits.Fallback != DefaultFallbackCategory ||
+		len(debits.Mappings) != 1 || debits.Mappings["Market"] != "Food" ||
+		len(debits.CategoryRules) != 1 || debits.CategoryRules[0].Name != "Uber" {
+		t.Fatalf("debit settings = %#v", debits)
+	}
+	if len(profileSettings.Mappings) != 2 || len(profileSettings.CategoryRules) != 2 {
+		t.Fatalf("ForDirection() changed the profile settings: %#v", profileSettings)
+	}
+}
+
+func TestNewIngestSettingsNormalizesFilterRules(t *testing.T) {
+	defaults := validIngestProfile()
+	defaults.IgnoreSamePersonTransfers = new(false)
+	custom := validIngestProfile()
+	custom.ID = "custom"
+	custom.FilterRules = []FilterRule{{Reason: "internal", Name: `^TED .* Savings$`, NameMatch: NameMatchRegex}}
+	none := validIngestProfile()
+	none.ID = "none"
+	none.FilterRules = []FilterRule{}
+
+	settings, err := NewIngestSettings([]IngestProfile{defaults, custom, none})
+	if err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+
+	defaultRules := settings.IngestProfiles[0].FilterRules
+	if len(defaultRules) != 3 || defaultRules[0].Reason != FilterReasonInvestment {
+		t.Fatalf("default filter rules = %#v", defaultRules)
+	}
+	if noRules := settings.IngestProfiles[2].FilterRules; len(noRules) != 0 {
+		t.Fatalf("empty filter rules = %#v", noRules)
+	}
+
+	rule := settings.IngestProfiles[1].FilterRules[0]
+	if !rule.Matches(Transaction{Name: "TED to Savings"}) || rule.Matches(Transaction{Name: "TED to Checking"}) {
+		t.Fatalf("regex filter rule = %#v", rule)
+	}
+}
+
+func TestNewIngestSettingsNormalizesRefundMatching(t *testing.T) {
+	off := validIngestProfile()
+	defaultWindow := validIngestProfile()
+	defaultWindow.ID = "default-window"
+	defaultWindow.RefundMatching = &RefundMatching{Mode: RefundModeMark}
+	window := validIngestProfile()
+	window.ID = "window"
+	window.RefundMatching = &RefundMatching{Mode: RefundModeNet, WindowDays: 30}
+
+	settings, err := NewIngestSettings([]IngestProfile{off, defaultWindow, window})
+	if err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+
+	tests := []struct {
+		mode       RefundMode
+		windowDays int
+	}{
+		{mode: RefundModeOff},
+		{mode: RefundModeMark, windowDays: DefaultRefundWindowDays},
+		{mode: RefundModeNet, windowDays: 30},
+	}
+	for index, test := range tests {
+		profileSettings := settings.IngestProfiles[index]
+		if profileSettings.RefundMode != test.mode || profileSettings.RefundWindowDays != test.windowDays {
+			t.Fatalf("settings = %#v", profileSettings)
+		}
+	}
+}
+
 func TestNewIngestSettingsValidation(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -165,6 +508,140 @@ func TestNewIngestSettingsValidation(t *testing.T) {
 				return []IngestProfile{ingestProfile}
 			},
 		},
+		{
+			name: "unsupported sheet provider",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SheetProvider = "airtable"
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "incomplete google sheets integration",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SheetProvider = SheetProviderGoogleSheets
+				ingestProfile.GoogleSheets = &GoogleSheetsConnection{SpreadsheetID: "spreadsheet"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "incomplete file integration",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SheetProvider = SheetProviderFile
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "incomplete sql integration",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SheetProvider = SheetProviderSQL
+				ingestProfile.SQL = &SQLConnection{Driver: SQLDriverSQLite}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "unsupported categorizer",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.Categorizer = "bayes"
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "LLM settings on heuristic categorizer",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.Categorizer = CategorizerHeuristic
+				ingestProfile.LLM = &LLMConnection{Model: "model"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "unconfigured source category mapping",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SourceCategoryMappings = map[string]Category{"Groceries": "Groceries"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "empty source category",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SourceCategoryMappings = map[string]Category{"": "Food"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "unconfigured keyword category",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryKeywords = map[Category][]string{"Transport": {"uber"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "blank keyword",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryKeywords = map[Category][]string{"Food": {"market", " "}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "unsupported LLM provider",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.LLM = &LLMConnection{Provider: "mistral", Model: "model"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "incomplete anthropic integration",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.LLM = &LLMConnection{Provider: LLMProviderAnthropic, Model: "model"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "incomplete ollama integration",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.LLM = &LLMConnection{Provider: LLMProviderOllama}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "reasoning effort on gemini",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.LLM = &LLMConnection{
+					Provider:        LLMProviderGemini,
+					Model:           "model",
+					APIKey:          "key",
+					ReasoningEffort: ReasoningEffortLow,
+				}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
 		{
 			name: "unsupported language",
 			ingestProfiles: func() []IngestProfile {
@@ -295,6 +772,34 @@ func TestNewIngestSettingsValidation(t *testing.T) {
 				return []IngestProfile{ingestProfile}
 			},
 		},
+		{
+			name: "unsupported sync mode",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SyncMode = "mirror"
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "preserve columns without reconcile",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.PreserveColumns = []TransactionColumn{TransactionColumnCategory}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "unknown preserve column",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SyncMode = SyncModeReconcile
+				ingestProfile.PreserveColumns = []TransactionColumn{"external_id"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
 		{
 			name: "unknown budget group mapping category",
 			ingestProfiles: func() []IngestProfile {
@@ -312,6 +817,258 @@ func TestNewIngestSettingsValidation(t *testing.T) {
 				ingestProfile.BudgetGroups = map[BudgetGroup]Color{"Needs": Red}
 				ingestProfile.BudgetGroupMappings = map[Category]BudgetGroup{"Food": "Wants"}
 
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "negative categorization chunk size",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategorizationChunkSize = -1
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule without conditions",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{Category: "Food"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule without classification",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{Name: "Uber"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule with unknown category",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{Name: "Uber", Category: "Transport"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule with budget group but no budget groups",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{Name: "Uber", BudgetGroup: "Needs"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule with unsupported name match",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{Name: "Uber", NameMatch: "fuzzy", Category: "Food"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule with invalid regex",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{Name: "(", NameMatch: NameMatchRegex, Category: "Food"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule with inverted amount range",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{MinAmount: new(20.0), MaxAmount: new(10.0), Category: "Food"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "category rule with unknown payment method",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.CategoryRules = []CategoryRule{{PaymentMethods: []PaymentMethod{"CASH"}, Category: "Food"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "split rule with unknown category",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SplitRules = []SplitRule{{
+					Name:  "Market",
+					Parts: []SplitPart{{Category: "Food", Ratio: new(0.5)}, {Category: "Home", Ratio: new(0.5)}},
+				}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "split rule with one part",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SplitRules = []SplitRule{{Name: "Market", Parts: []SplitPart{{Category: "Food"}}}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "split rule with ratios not summing to one",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SplitRules = []SplitRule{{
+					Name:  "Market",
+					Parts: []SplitPart{{Category: "Food", Ratio: new(0.5)}, {Category: "Food", Ratio: new(0.4)}},
+				}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "split rule mixing ratios and amounts",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SplitRules = []SplitRule{{
+					Name:  "Market",
+					Parts: []SplitPart{{Category: "Food", Ratio: new(0.5)}, {Category: "Food", Amount: new(10.0)}},
+				}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "split rule without a rest part",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SplitRules = []SplitRule{{
+					Name:  "Market",
+					Parts: []SplitPart{{Category: "Food", Amount: new(5.0)}, {Category: "Food", Amount: new(10.0)}},
+				}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "split part with ratio and amount",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.SplitRules = []SplitRule{{
+					Name:  "Market",
+					Parts: []SplitPart{{Category: "Food", Ratio: new(1.0), Amount: new(5.0)}, {Category: "Food"}},
+				}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "income categories without credits",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.IncomeCategories = map[Category]Color{"Salary": Green}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "income category that is also an expense category",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.IncludeCredits = true
+				ingestProfile.IncomeCategories = map[Category]Color{"Food": Green}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "income category with invalid color",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.IncludeCredits = true
+				ingestProfile.IncomeCategories = map[Category]Color{"Salary": "neon"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "unsupported refund mode",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.RefundMatching = &RefundMatching{Mode: "ignore"}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "negative refund window",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.RefundMatching = &RefundMatching{Mode: RefundModeNet, WindowDays: -1}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "filter rule without a reason",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.FilterRules = []FilterRule{{Name: "Savings"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "filter rule without conditions",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.FilterRules = []FilterRule{{Reason: "savings"}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "filter rule with invalid regex",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.FilterRules = []FilterRule{{Reason: "savings", Name: "(", NameMatch: NameMatchRegex}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "filter rule with unknown direction",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.FilterRules = []FilterRule{{Reason: "savings", Directions: []TransactionDirection{"OUT"}}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "filter rule with inverted amount range",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.FilterRules = []FilterRule{{Reason: "large", MinAmount: new(20.0), MaxAmount: new(10.0)}}
+
+				return []IngestProfile{ingestProfile}
+			},
+		},
+		{
+			name: "filter rules with same-person transfers setting",
+			ingestProfiles: func() []IngestProfile {
+				ingestProfile := validIngestProfile()
+				ingestProfile.IgnoreSamePersonTransfers = new(true)
+				ingestProfile.FilterRules = []FilterRule{{Reason: "savings", AccountIDs: []string{"savings"}}}
+
 				return []IngestProfile{ingestProfile}
 			},
 		},
@@ -339,3 +1096,118 @@ func TestNewIngestSettingsUnsupportedLanguageError(t *testing.T) {
 		t.Fatalf("NewIngestSettings() error = %q, want %q", err, want)
 	}
 }
+
+func TestNewIngestSettingsUnsupportedSheetProviderError(t *testing.T) {
+	ingestProfile := validIngestProfile()
+	ingestProfile.SheetProvider = "airtable"
+
+	_, err := NewIngestSettings([]IngestProfile{ingestProfile})
+	if err == nil {
+		t.Fatal("NewIngestSettings() error = nil")
+	}
+	want := `ingest profile "ingest-profile": unsupported sheet provider "airtable" (supported: notion, google_sheets, file, sql)`
+	if err.Error() != want {
+		t.Fatalf("NewIngestSettings() error = %q, want %q", err, want)
+	}
+}
+
+func TestNewIngestSettingsAcceptsGoogleSheetsProfile(t *testing.T) {
+	ingestProfile := validIngestProfile()
+	ingestProfile.SheetProvider = SheetProviderGoogleSheets
+	ingestProfile.NotionToken = ""
+	ingestProfile.NotionPageID = ""
+	ingestProfile.GoogleSheets = &GoogleSheetsConnection{
+		SpreadsheetID: "spreadsheet",
+		ClientEmail:   "bot@project.iam.gserviceaccount.com",
+		PrivateKey:    "private-key",
+	}
+
+	if _, err := NewIngestSettings([]IngestProfile{ingestProfile}); err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+}
+
+func TestNewIngestSettingsAcceptsLLMConnections(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection *LLMConnection
+	}{
+		{name: "default OpenAI connection"},
+		{
+			name:       "OpenAI connection with reasoning effort",
+			connection: &LLMConnection{Model: "model", ReasoningEffort: ReasoningEffortHigh},
+		},
+		{
+			name:       "anthropic connection",
+			connection: &LLMConnection{Provider: LLMProviderAnthropic, Model: "model", APIKey: "key"},
+		},
+		{
+			name:       "gemini connection",
+			connection: &LLMConnection{Provider: LLMProviderGemini, Model: "model", APIKey: "key"},
+		},
+		{
+			name: "ollama connection",
+			connection: &LLMConnection{
+				Provider:        LLMProviderOllama,
+				Model:           "model",
+				ReasoningEffort: ReasoningEffortLow,
+				BaseURL:         "http://localhost:11434/v1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ingestProfile := validIngestProfile()
+			ingestProfile.LLM = test.connection
+
+			if _, err := NewIngestSettings([]IngestProfile{ingestProfile}); err != nil {
+				t.Fatalf("NewIngestSettings() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLLMConnectionOrDefault(t *testing.T) {
+	ingestProfile := validIngestProfile()
+	if got := ingestProfile.LLMConnectionOrDefault(); got.Provider != LLMProviderOpenAI {
+		t.Fatalf("LLMConnectionOrDefault() = %#v", got)
+	}
+
+	ingestProfile.LLM = &LLMConnection{Model: "model"}
+	if got := ingestProfile.LLMConnectionOrDefault(); got.Provider != LLMProviderOpenAI || got.Model != "model" {
+		t.Fatalf("LLMConnectionOrDefault() = %#v", got)
+	}
+	if ingestProfile.LLM.Provider != "" {
+		t.Fatalf("LLM provider = %q, want the profile unchanged", ingestProfile.LLM.Provider)
+	}
+}
+
+func TestNewIngestSettingsNormalizesHeuristicCategorizer(t *testing.T) {
+	llmProfile := validIngestProfile()
+	heuristicProfile := validIngestProfile()
+	heuristicProfile.ID = "heuristic"
+	heuristicProfile.Categorizer = CategorizerHeuristic
+	heuristicProfile.SourceCategoryMappings = map[string]Category{"Groceries": "Food"}
+	heuristicProfile.CategoryKeywords = map[Category][]string{"Food": {"market", "bakery"}}
+
+	settings, err := NewIngestSettings([]IngestProfile{llmProfile, heuristicProfile})
+	if err != nil {
+		t.Fatalf("NewIngestSettings() error = %v", err)
+	}
+
+	if got := settings.IngestProfiles[0].Categorizer; got != CategorizerLLM {
+		t.Fatalf("default categorizer = %q, want %q", got, CategorizerLLM)
+	}
+	heuristicSettings := settings.IngestProfiles[1]
+	if heuristicSettings.Categorizer != CategorizerHeuristic ||
+		heuristicSettings.SourceCategoryMappings["Groceries"] != "Food" ||
+		!slices.Equal(heuristicSettings.CategoryKeywords["Food"], []string{"market", "bakery"}) {
+		t.Fatalf("heuristic settings = %#v", heuristicSettings)
+	}
+
+	heuristicProfile.SourceCategoryMappings["Groceries"] = DefaultFallbackCategory
+	if heuristicSettings.SourceCategoryMappings["Groceries"] != "Food" {
+		t.Fatal("NewIngestSettings() kept a reference to the input source category mappings")
+	}
+}
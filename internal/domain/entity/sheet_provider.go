@@ -0,0 +1,35 @@
+package entity
+
+type SheetProvider string
+
+const (
+	SheetProviderNotion       SheetProvider = "notion"
+	SheetProviderGoogleSheets SheetProvider = "google_sheets"
+	SheetProviderFile         SheetProvider = "file"
+	SheetProviderSQL          SheetProvider = "sql"
+	DefaultSheetProvider      SheetProvider = SheetProviderNotion
+)
+
+func (provider SheetProvider) IsValid() bool {
+	switch provider {
+	case SheetProviderNotion, SheetProviderGoogleSheets, SheetProviderFile, SheetProviderSQL:
+		return true
+	default:
+		return false
+	}
+}
+
+type FileFormat string
+
+const (
+	FileFormatCSV     FileFormat = "csv"
+	FileFormatXLSX    FileFormat = "xlsx"
+	DefaultFileFormat FileFormat = FileFormatCSV
+)
+
+type SQLDriver string
+
+const (
+	SQLDriverSQLite   SQLDriver = "sqlite"
+	SQLDriverPostgres SQLDriver = "postgres"
+)
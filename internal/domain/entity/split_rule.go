@@ -0,0 +1,167 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// splitRatioTolerance absorbs the rounding of ratios written as decimals, such as three thirds.
+const splitRatioTolerance = 1e-6
+
+// SplitRule divides the transactions whose name matches into parts, written as one row each with
+// its own category. Either every part sets a Ratio, and the ratios sum to 1, or the parts set fixed
+// Amounts and exactly one of them leaves its amount out to take the rest of the transaction.
+type SplitRule struct {
+	Name      string      `json:"name"                 validate:"required"`
+	NameMatch NameMatch   `json:"name_match,omitempty" validate:"omitempty,oneof=exact case_insensitive prefix contains regex"`
+	Parts     []SplitPart `json:"parts"                validate:"required,min=2,dive"`
+
+	nameRegexp *regexp.Regexp
+}
+
+// SplitPart is one share of a split transaction. A part without a budget group takes the one the
+// profile maps its category to, or the budget group fallback.
+type SplitPart struct {
+	Category    Category    `json:"category"               validate:"required"`
+	BudgetGroup BudgetGroup `json:"budget_group,omitempty"`
+	Ratio       *float64    `json:"ratio,omitempty"        validate:"omitempty,gt=0,lte=1"`
+	Amount      *float64    `json:"amount,omitempty"       validate:"omitempty,gt=0"`
+}
+
+// Matches reports whether the rule splits the transaction. Regex rules only match once normalized
+// into the ingest profile settings, which compiles their expression.
+func (r SplitRule) Matches(transaction Transaction) bool {
+	return matchName(transaction.Name, r.Name, r.NameMatch, r.nameRegexp)
+}
+
+// Split divides the transaction into its parts. Amounts are split in cents, and the last ratio
+// part takes what rounding left over. It reports false when the fixed amounts leave nothing for
+// the rest, so the transaction is kept whole.
+func (r SplitRule) Split(transaction Transaction) ([]Transaction, bool) {
+	total := int64(math.Round(transaction.Amount * 100))
+	cents := make([]int64, len(r.Parts))
+	rest := -1
+	assigned := int64(0)
+	for index, part := range r.Parts {
+		switch {
+		case part.Ratio != nil && index < len(r.Parts)-1:
+			cents[index] = int64(math.Round(float64(total) * *part.Ratio))
+		case part.Amount != nil:
+			cents[index] = int64(math.Round(*part.Amount * 100))
+		default:
+			rest = index
+
+			continue
+		}
+		assigned += cents[index]
+	}
+	if rest >= 0 {
+		cents[rest] = total - assigned
+	}
+	if rest < 0 || cents[rest] <= 0 {
+		return nil, false
+	}
+
+	parts := make([]Transaction, 0, len(r.Parts))
+	for index, part := range r.Parts {
+		split := transaction
+		split.ParentID = transaction.ID()
+		if transaction.ExternalID != "" {
+			split.ExternalID = fmt.Sprintf("%s#%d", transaction.ExternalID, index+1)
+		}
+		split.Amount = float64(cents[index]) / 100
+		split.Category = part.Category
+		split.BudgetGroup = part.BudgetGroup
+		parts = append(parts, split)
+	}
+
+	return parts, true
+}
+
+func normalizeSplitRules(
+	rules []SplitRule,
+	colorsByCategory map[Category]Color,
+	budgetGroups normalizedBudgetGroupSettings,
+) ([]SplitRule, error) {
+	normalized := make([]SplitRule, 0, len(rules))
+	for index, rule := range rules {
+		normalizedRule, err := normalizeSplitRule(rule, colorsByCategory, budgetGroups)
+		if err != nil {
+			return nil, fmt.Errorf("split rule %d: %w", index+1, err)
+		}
+
+		normalized = append(normalized, normalizedRule)
+	}
+
+	return normalized, nil
+}
+
+func normalizeSplitRule(
+	rule SplitRule,
+	colorsByCategory map[Category]Color,
+	budgetGroups normalizedBudgetGroupSettings,
+) (SplitRule, error) {
+	if rule.Name == "" {
+		return SplitRule{}, errors.New("a name is required")
+	}
+	if len(rule.Parts) < 2 {
+		return SplitRule{}, errors.New("at least two parts are required")
+	}
+
+	nameMatch, nameRegexp, err := normalizeNameMatch(rule.Name, rule.NameMatch)
+	if err != nil {
+		return SplitRule{}, err
+	}
+	rule.NameMatch, rule.nameRegexp = nameMatch, nameRegexp
+
+	parts := make([]SplitPart, 0, len(rule.Parts))
+	ratioParts, restParts := 0, 0
+	ratios := 0.0
+	for index, part := range rule.Parts {
+		if _, ok := colorsByCategory[part.Category]; !ok {
+			return SplitRule{}, fmt.Errorf("part %d: category %q is not configured", index+1, part.Category)
+		}
+		if part.BudgetGroup != "" {
+			if _, ok := budgetGroups.colorsByGroup[part.BudgetGroup]; !ok {
+				return SplitRule{}, fmt.Errorf(
+					"part %d: budget group %q is not configured",
+					index+1,
+					part.BudgetGroup,
+				)
+			}
+		} else if len(budgetGroups.groups) > 0 {
+			part.BudgetGroup = budgetGroups.mappings[part.Category]
+			if part.BudgetGroup == "" {
+				part.BudgetGroup = budgetGroups.fallback
+			}
+		}
+
+		switch {
+		case part.Ratio != nil && part.Amount != nil:
+			return SplitRule{}, fmt.Errorf("part %d: set either a ratio or an amount", index+1)
+		case part.Ratio != nil:
+			ratioParts++
+			ratios += *part.Ratio
+		case part.Amount == nil:
+			restParts++
+		}
+
+		parts = append(parts, part)
+	}
+
+	switch {
+	case ratioParts == len(parts):
+		if math.Abs(ratios-1) > splitRatioTolerance {
+			return SplitRule{}, fmt.Errorf("ratios sum to %v, want 1", ratios)
+		}
+	case ratioParts > 0:
+		return SplitRule{}, errors.New("ratios and amounts cannot be mixed")
+	case restParts != 1:
+		return SplitRule{}, errors.New("exactly one part must leave its amount out to take the rest")
+	}
+	rule.Parts = parts
+
+	return rule, nil
+}
@@ -0,0 +1,45 @@
+package entity
+
+// SyncMode controls what an ingest does with transactions that already have a row.
+type SyncMode string
+
+const (
+	// SyncModeAppend only inserts transactions without a row.
+	SyncModeAppend SyncMode = "append"
+	// SyncModeReconcile also updates rows whose transaction changed at the source.
+	SyncModeReconcile SyncMode = "reconcile"
+	DefaultSyncMode   SyncMode = SyncModeAppend
+)
+
+func (mode SyncMode) IsValid() bool {
+	switch mode {
+	case SyncModeAppend, SyncModeReconcile:
+		return true
+	default:
+		return false
+	}
+}
+
+// TransactionColumn names a transaction table column independently of the table language.
+type TransactionColumn string
+
+const (
+	TransactionColumnName           TransactionColumn = "name"
+	TransactionColumnCategory       TransactionColumn = "category"
+	TransactionColumnBudgetGroup    TransactionColumn = "budget_group"
+	TransactionColumnAmount         TransactionColumn = "amount"
+	TransactionColumnPaymentMethod  TransactionColumn = "payment_method"
+	TransactionColumnCardLastDigits TransactionColumn = "card_last_digits"
+	TransactionColumnDate           TransactionColumn = "date"
+)
+
+func (column TransactionColumn) IsValid() bool {
+	switch column {
+	case TransactionColumnName, TransactionColumnCategory, TransactionColumnBudgetGroup,
+		TransactionColumnAmount, TransactionColumnPaymentMethod, TransactionColumnCardLastDigits,
+		TransactionColumnDate:
+		return true
+	default:
+		return false
+	}
+}
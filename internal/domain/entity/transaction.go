@@ -16,6 +16,12 @@ const (
 )
 
 type Transaction struct {
+	// ExternalID is the ID the open finance provider assigned to the transaction. Rows written
+	// before it was stored have none.
+	ExternalID string
+	// AccountID is the open finance account the transaction was listed from. Rows read back from a
+	// sheet have none.
+	AccountID      string
 	Name           string
 	Category       Category
 	BudgetGroup    BudgetGroup
@@ -24,6 +30,25 @@ type Transaction struct {
 	Date           time.Time
 	Direction      TransactionDirection
 	CardLastDigits *string
+	// SourceCategory is the category the open finance provider assigned to the transaction. Rows
+	// read back from a sheet have none.
+	SourceCategory string
+	// NeedsReview marks a transaction whose category is a guess worth checking by hand.
+	NeedsReview bool
+	// ParentID is the ID of the transaction a split rule divided into this part. Every part of a
+	// split shares it, and whole transactions have none.
+	ParentID string
+	// RefundMatchID is the ID of the transaction refund matching paired with this one: the purchase
+	// on a refund, and the first refund on a purchase.
+	RefundMatchID string
+	// Refunded marks a purchase refund matching reduced or linked to a refund in this run, so its
+	// existing row is updated in every sync mode. Rows read back from a sheet have none.
+	Refunded bool
+	// InstallmentNumber and TotalInstallments place a card purchase paid in installments, and
+	// PurchaseAmount is the amount of the whole purchase. Transactions paid at once have none.
+	InstallmentNumber int
+	TotalInstallments int
+	PurchaseAmount    float64
 }
 
 type TransactionDirection string
@@ -33,7 +58,19 @@ const (
 	TransactionDirectionDebit  TransactionDirection = "DEBIT"
 )
 
+var TransactionDirections = []TransactionDirection{
+	TransactionDirectionDebit,
+	TransactionDirectionCredit,
+}
+
+var TransactionDirectionColors = map[TransactionDirection]Color{
+	TransactionDirectionDebit:  Red,
+	TransactionDirectionCredit: Green,
+}
+
 type TransactionInput struct {
+	ExternalID              string
+	AccountID               string
 	AccountType             AccountType
 	Description             string
 	Amount                  float64
@@ -45,6 +82,9 @@ type TransactionInput struct {
 	ReceiverName            string
 	ReceiverDocument        string
 	CardLastDigits          *string
+	InstallmentNumber       *int
+	TotalInstallments       *int
+	TotalAmount             *float64
 }
 
 func NewTransaction(input TransactionInput) (Transaction, bool) {
@@ -54,10 +94,13 @@ func NewTransaction(input TransactionInput) (Transaction, bool) {
 	}
 
 	transaction := Transaction{
-		Amount:    math.Abs(amount),
-		Date:      input.Date,
-		Category:  Category(input.SourceCategory),
-		Direction: input.Direction,
+		ExternalID:     input.ExternalID,
+		AccountID:      input.AccountID,
+		Amount:         math.Abs(amount),
+		Date:           input.Date,
+		SourceCategory: input.SourceCategory,
+		Category:       Category(input.SourceCategory),
+		Direction:      input.Direction,
 	}
 
 	switch input.AccountType {
@@ -72,6 +115,7 @@ func NewTransaction(input TransactionInput) (Transaction, bool) {
 		transaction.Name = strings.TrimSpace(input.Description)
 		transaction.PaymentMethod = PaymentMethodCreditCard
 		transaction.CardLastDigits = input.CardLastDigits
+		applyInstallment(&transaction, input)
 	default:
 		return Transaction{}, false
 	}
@@ -110,7 +154,40 @@ func cleanBankTransactionName(name string) string {
 	return strings.TrimSpace(name)
 }
 
+// ID identifies the transaction by its external ID, or by its legacy ID when it has none.
 func (t Transaction) ID() string {
+	if t.ExternalID != "" {
+		return t.ExternalID
+	}
+
+	return t.LegacyID()
+}
+
+// SourceID identifies the open finance transaction the row stands for, which all parts of a split
+// share.
+func (t Transaction) SourceID() string {
+	if t.ParentID != "" {
+		return t.ParentID
+	}
+
+	return t.ID()
+}
+
+// CategoryDirection is the direction whose categories the transaction takes. Only credits take the
+// income categories, so transactions without a direction, such as rows written before it was
+// stored, count as debits.
+func (t Transaction) CategoryDirection() TransactionDirection {
+	if t.Direction == TransactionDirectionCredit {
+		return TransactionDirectionCredit
+	}
+
+	return TransactionDirectionDebit
+}
+
+// LegacyID identifies the transaction by name, amount and minute, as rows did before external IDs
+// were stored. Renamed transactions get a new legacy ID, and identical ones in the same minute
+// share it.
+func (t Transaction) LegacyID() string {
 	return fmt.Sprintf(
 		"%s:%d:%s",
 		t.Name,
@@ -20,6 +20,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			name: "bank description, account currency amount, and source metadata",
 			input: TransactionInput{
+				ExternalID:              "transaction-id",
 				AccountType:             AccountTypeBank,
 				Description:             "Market",
 				Amount:                  -99,
@@ -31,12 +32,14 @@ func TestNewTransaction(t *testing.T) {
 			},
 			accepted: true,
 			want: Transaction{
-				Name:          "Market",
-				Category:      "Groceries",
-				Amount:        12.34,
-				Date:          date,
-				Direction:     TransactionDirectionDebit,
-				PaymentMethod: PaymentMethodPix,
+				ExternalID:     "transaction-id",
+				Name:           "Market",
+				Category:       "Groceries",
+				Amount:         12.34,
+				Date:           date,
+				Direction:      TransactionDirectionDebit,
+				PaymentMethod:  PaymentMethodPix,
+				SourceCategory: "Groceries",
 			},
 		},
 		{
@@ -77,6 +80,45 @@ func TestNewTransaction(t *testing.T) {
 				CardLastDigits: &card,
 			},
 		},
+		{
+			name: "credit card installment marker",
+			input: TransactionInput{
+				AccountType:    AccountTypeCreditCard,
+				Description:    "Store PARC 03/10",
+				Amount:         -20.01,
+				CardLastDigits: &card,
+			},
+			accepted: true,
+			want: Transaction{
+				Name:              "Store PARC 03/10",
+				Amount:            20.01,
+				PaymentMethod:     PaymentMethodCreditCard,
+				CardLastDigits:    &card,
+				InstallmentNumber: 3,
+				TotalInstallments: 10,
+				PurchaseAmount:    200.1,
+			},
+		},
+		{
+			name: "credit card installment metadata",
+			input: TransactionInput{
+				AccountType:       AccountTypeCreditCard,
+				Description:       "Store",
+				Amount:            -20,
+				InstallmentNumber: new(2),
+				TotalInstallments: new(3),
+				TotalAmount:       new(59.9),
+			},
+			accepted: true,
+			want: Transaction{
+				Name:              "Store",
+				Amount:            20,
+				PaymentMethod:     PaymentMethodCreditCard,
+				InstallmentNumber: 2,
+				TotalInstallments: 3,
+				PurchaseAmount:    59.9,
+			},
+		},
 		{
 			name: "transaction filtering is deferred to the ingest use case",
 			input: TransactionInput{
@@ -88,10 +130,11 @@ func TestNewTransaction(t *testing.T) {
 			},
 			accepted: true,
 			want: Transaction{
-				Name:          "Aplicação automática",
-				Category:      "Same person transfer",
-				Direction:     TransactionDirectionCredit,
-				PaymentMethod: PaymentMethodPix,
+				Name:           "Aplicação automática",
+				Category:       "Same person transfer",
+				Direction:      TransactionDirectionCredit,
+				PaymentMethod:  PaymentMethodPix,
+				SourceCategory: "Same person transfer",
 			},
 		},
 		{
@@ -133,6 +176,37 @@ func TestTransactionID(t *testing.T) {
 	if got, want := transaction.ID(), "Store:1013:2026-08-09 12:34"; got != want {
 		t.Fatalf("ID() = %q, want %q", got, want)
 	}
+
+	transaction.ExternalID = "transaction-id"
+	if got, want := transaction.ID(), "transaction-id"; got != want {
+		t.Fatalf("ID() with external ID = %q, want %q", got, want)
+	}
+	if got, want := transaction.LegacyID(), "Store:1013:2026-08-09 12:34"; got != want {
+		t.Fatalf("LegacyID() = %q, want %q", got, want)
+	}
+}
+
+func TestTransactionCategorizationName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Store PARC 03/10", want: "Store"},
+		{name: "PARC03/10 Store Online", want: "Store Online"},
+		{name: "Store parcela 3 de 10", want: "Store"},
+		{name: "Store PARC 11/10", want: "Store PARC 11/10"},
+		{name: "Store 03/10", want: "Store 03/10"},
+		{name: "PARC 03/10", want: "PARC 03/10"},
+		{name: "Store", want: "Store"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := (Transaction{Name: test.name}).CategorizationName(); got != test.want {
+				t.Fatalf("CategorizationName() = %q, want %q", got, test.want)
+			}
+		})
+	}
 }
 
 func TestCleanBankTransactionName(t *testing.T) {
@@ -0,0 +1,183 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+)
+
+// cachedClassification is how the categorization cache stores the classifications of a name.
+// Fingerprint identifies the categories and budget groups the profile had when GPT answered, so
+// entries from before the profile changed them are ignored and eventually overwritten. Learned
+// entries hold corrections made by hand in the sheet; they outlive such changes for as long as
+// their values stay configured, and GPT never overwrites them.
+type cachedClassification struct {
+	Fingerprint string             `json:"fingerprint"`
+	Category    entity.Category    `json:"category"`
+	BudgetGroup entity.BudgetGroup `json:"budget_group,omitempty"`
+	Learned     bool               `json:"learned,omitempty"`
+}
+
+func (c cachedClassification) classifications() transactionClassifications {
+	return transactionClassifications{Category: c.Category, BudgetGroup: c.BudgetGroup}
+}
+
+// cachedClassifications returns the cache entries of the names that still apply to the profile's
+// categories and budget groups. The cache only saves GPT calls, so failing to read it is logged
+// and treated as a miss.
+func (s *Ingest) cachedClassifications(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	names []string,
+) map[string]cachedClassification {
+	entriesByName := make(map[string]cachedClassification, len(names))
+	if len(names) == 0 {
+		return entriesByName
+	}
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, categorizationCacheKey(settings, name))
+	}
+	values, err := s.categorizationCache.Get(ctx, keys)
+	if err != nil {
+		slog.Warn("failed to read categorization cache", "ingest_profile", settings.ID, "error", err)
+
+		return entriesByName
+	}
+
+	fingerprint := classificationsFingerprint(settings)
+	for index, name := range names {
+		value, exists := values[keys[index]]
+		if !exists {
+			continue
+		}
+
+		var entry cachedClassification
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			continue
+		}
+		if (entry.Learned || entry.Fingerprint == fingerprint) && entry.classifications().configured(settings) {
+			entriesByName[name] = entry
+		}
+	}
+
+	return entriesByName
+}
+
+// cacheClassifications stores the complete classifications GPT gave, so later runs reuse them.
+// Names GPT skipped or answered with unknown values are left out, leaving them to be asked again.
+func (s *Ingest) cacheClassifications(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	classificationsByName map[string]transactionClassifications,
+) {
+	fingerprint := classificationsFingerprint(settings)
+	values := make(map[string]string, len(classificationsByName))
+	for name, classifications := range classificationsByName {
+		if !classifications.configured(settings) {
+			continue
+		}
+
+		value, err := json.Marshal(cachedClassification{
+			Fingerprint: fingerprint,
+			Category:    classifications.Category,
+			BudgetGroup: classifications.BudgetGroup,
+		})
+		if err != nil {
+			slog.Warn("failed to encode categorization cache entry", "name", name, "error", err)
+
+			continue
+		}
+		values[categorizationCacheKey(settings, name)] = string(value)
+	}
+	if len(values) == 0 {
+		return
+	}
+
+	if err := s.categorizationCache.Set(ctx, values); err != nil {
+		slog.Warn("failed to write categorization cache", "ingest_profile", settings.ID, "error", err)
+	}
+}
+
+// learnCorrections stores the corrections as learned cache entries, which take precedence over GPT
+// in later runs. Each correction is stored with the settings of its direction.
+func (s *Ingest) learnCorrections(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	corrections []Correction,
+) error {
+	values := make(map[string]string, len(corrections))
+	for _, correction := range corrections {
+		directionSettings := settings.ForDirection(correction.Direction)
+		value, err := json.Marshal(cachedClassification{
+			Fingerprint: classificationsFingerprint(directionSettings),
+			Category:    correction.Category,
+			BudgetGroup: correction.BudgetGroup,
+			Learned:     true,
+		})
+		if err != nil {
+			return fmt.Errorf("encode correction of %q: %w", correction.Name, err)
+		}
+		values[categorizationCacheKey(directionSettings, correction.Name)] = string(value)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	if err := s.categorizationCache.Set(ctx, values); err != nil {
+		return fmt.Errorf("write categorization cache: %w", err)
+	}
+
+	return nil
+}
+
+// categorizationCacheKey identifies a name within a profile. Names are compared ignoring case and
+// repeated whitespace, so small formatting differences between months share an entry. Credits
+// take their own entries, since the same name may be an expense and an income.
+func categorizationCacheKey(settings entity.IngestProfileSettings, name string) string {
+	if settings.Direction == entity.TransactionDirectionCredit {
+		return settings.ID + "|credit|" + normalizedName(name)
+	}
+
+	return settings.ID + "|" + normalizedName(name)
+}
+
+// normalizedName lowercases the name and collapses its whitespace.
+func normalizedName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// classificationsFingerprint hashes the sorted categories and budget groups of the profile.
+func classificationsFingerprint(settings entity.IngestProfileSettings) string {
+	categories := make([]string, 0, len(settings.Categories))
+	for _, category := range settings.Categories {
+		categories = append(categories, string(category))
+	}
+	budgetGroups := make([]string, 0, len(settings.BudgetGroups))
+	for _, budgetGroup := range settings.BudgetGroups {
+		budgetGroups = append(budgetGroups, string(budgetGroup))
+	}
+	slices.Sort(categories)
+	slices.Sort(budgetGroups)
+
+	hash := sha256.New()
+	for _, value := range categories {
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+	hash.Write([]byte{1})
+	for _, value := range budgetGroups {
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,148 @@
+package ingest
+
+import (
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+)
+
+// ruleAssignment records which classifications the category rules or the source category mappings
+// set on a transaction.
+type ruleAssignment struct {
+	category    bool
+	budgetGroup bool
+	// sourceCategory marks a category set by the source category mappings rather than a rule.
+	sourceCategory bool
+	// split marks a transaction a split rule divides, whose parts take their classifications from
+	// the rule.
+	split bool
+}
+
+// complete reports whether the rules set every classification the profile uses, leaving nothing
+// for GPT.
+func (a ruleAssignment) complete(settings entity.IngestProfileSettings) bool {
+	return a.category && (a.budgetGroup || len(settings.BudgetGroups) == 0)
+}
+
+// applyCategoryRules classifies the transactions the profile's rules match. Each classification
+// comes from the first matching rule that sets it, so a later rule can still fill the budget
+// group of a transaction an earlier one only categorized.
+func applyCategoryRules(
+	settings entity.IngestProfileSettings,
+	transactions []entity.Transaction,
+) []ruleAssignment {
+	assignments := make([]ruleAssignment, len(transactions))
+	if len(settings.CategoryRules) == 0 {
+		return assignments
+	}
+
+	for index := range transactions {
+		assignment := &assignments[index]
+		for _, rule := range settings.CategoryRules {
+			if assignment.complete(settings) {
+				break
+			}
+			if !rule.Matches(transactions[index]) {
+				continue
+			}
+
+			if rule.Category != "" && !assignment.category {
+				transactions[index].Category = rule.Category
+				assignment.category = true
+			}
+			if rule.BudgetGroup != "" && !assignment.budgetGroup {
+				transactions[index].BudgetGroup = rule.BudgetGroup
+				assignment.budgetGroup = true
+			}
+		}
+	}
+
+	return assignments
+}
+
+// applySourceCategoryMappings categorizes the transactions the rules left uncategorized whose
+// source category the profile maps.
+func applySourceCategoryMappings(
+	settings entity.IngestProfileSettings,
+	transactions []entity.Transaction,
+	assignments []ruleAssignment,
+) {
+	for index := range transactions {
+		if assignments[index].category {
+			continue
+		}
+
+		category, mapped := settings.SourceCategoryMappings[transactions[index].SourceCategory]
+		if !mapped {
+			continue
+		}
+
+		transactions[index].Category = category
+		assignments[index].category = true
+		assignments[index].sourceCategory = true
+	}
+}
+
+// applySplitRules marks the transactions a split rule divides as classified, overriding the other
+// rules, since each part takes its classifications from the split rule. The transaction keeps the
+// classifications of its first part until it is split.
+func applySplitRules(
+	settings entity.IngestProfileSettings,
+	transactions []entity.Transaction,
+	assignments []ruleAssignment,
+) {
+	for index := range transactions {
+		rule, ok := splitRuleFor(settings, transactions[index])
+		if !ok {
+			continue
+		}
+
+		transactions[index].Category = rule.Parts[0].Category
+		transactions[index].BudgetGroup = rule.Parts[0].BudgetGroup
+		assignments[index] = ruleAssignment{category: true, budgetGroup: true, split: true}
+	}
+}
+
+// splitTransactions replaces the transactions a split rule of their direction divides with their
+// parts.
+func splitTransactions(
+	settings entity.IngestProfileSettings,
+	transactions []entity.Transaction,
+) []entity.Transaction {
+	if len(settings.SplitRules) == 0 {
+		return transactions
+	}
+
+	settingsByDirection := make(map[entity.TransactionDirection]entity.IngestProfileSettings)
+	for _, direction := range entity.TransactionDirections {
+		settingsByDirection[direction] = settings.ForDirection(direction)
+	}
+
+	split := make([]entity.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		rule, ok := splitRuleFor(settingsByDirection[transaction.CategoryDirection()], transaction)
+		if !ok {
+			split = append(split, transaction)
+
+			continue
+		}
+
+		parts, _ := rule.Split(transaction)
+		split = append(split, parts...)
+	}
+
+	return split
+}
+
+// splitRuleFor returns the first split rule matching the transaction that can divide it. A rule
+// whose fixed amounts leave nothing for the rest does not split it.
+func splitRuleFor(settings entity.IngestProfileSettings, transaction entity.Transaction) (entity.SplitRule, bool) {
+	for _, rule := range settings.SplitRules {
+		if !rule.Matches(transaction) {
+			continue
+		}
+		if _, ok := rule.Split(transaction); ok {
+			return rule, true
+		}
+	}
+
+	return entity.SplitRule{}, false
+}
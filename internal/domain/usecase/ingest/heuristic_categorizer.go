@@ -0,0 +1,165 @@
+package ingest
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+)
+
+// minNameSimilarity is the share of tokens a name must have in common with an already classified
+// name to take its category.
+const minNameSimilarity = 0.5
+
+// classifiedName is a name whose category is known, which similar names may borrow.
+type classifiedName struct {
+	tokens   []string
+	category entity.Category
+}
+
+// classifyHeuristically classifies the names offline, for profiles that do not use an LLM. Each
+// name takes the first category found among, in order: the category the rules already gave it,
+// the profile's mapping for it, and the category with the longest keyword found in it. Names still
+// unresolved take the category of the most similar classified or mapped name, and names matching
+// nothing are left out, so they get the fallbacks. The budget group follows the profile's mapping
+// for the category.
+func classifyHeuristically(
+	settings entity.IngestProfileSettings,
+	knownCategories map[string]entity.Category,
+	names []string,
+) map[string]transactionClassifications {
+	mappedCategories := make(map[string]entity.Category, len(settings.Mappings))
+	for name, category := range settings.Mappings {
+		mappedCategories[normalizedName(name)] = category
+	}
+
+	categoryByName := make(map[string]entity.Category, len(settings.Mappings)+len(knownCategories))
+	for name, category := range settings.Mappings {
+		categoryByName[name] = category
+	}
+	for name, category := range knownCategories {
+		categoryByName[name] = category
+	}
+
+	classificationsByName := make(map[string]transactionClassifications, len(names))
+	unresolved := make([]string, 0, len(names))
+	for _, name := range names {
+		category, ok := knownCategories[name]
+		if !ok {
+			category, ok = mappedCategories[normalizedName(name)]
+		}
+		if !ok {
+			category, ok = keywordCategory(settings, name)
+		}
+		if !ok {
+			unresolved = append(unresolved, name)
+
+			continue
+		}
+
+		classificationsByName[name] = heuristicClassifications(settings, category)
+		categoryByName[name] = category
+	}
+
+	classified := classifiedNames(categoryByName)
+	for _, name := range unresolved {
+		if category, ok := mostSimilarCategory(classified, nameTokens(name)); ok {
+			classificationsByName[name] = heuristicClassifications(settings, category)
+		}
+	}
+
+	return classificationsByName
+}
+
+func heuristicClassifications(
+	settings entity.IngestProfileSettings,
+	category entity.Category,
+) transactionClassifications {
+	return transactionClassifications{Category: category, BudgetGroup: settings.BudgetGroupMappings[category]}
+}
+
+// keywordCategory returns the category with the longest keyword found in the name as whole words,
+// preferring the first category in order on ties.
+func keywordCategory(settings entity.IngestProfileSettings, name string) (entity.Category, bool) {
+	words := " " + strings.Join(nameTokens(name), " ") + " "
+
+	var match entity.Category
+	longest := 0
+	for _, category := range settings.Categories {
+		for _, keyword := range settings.CategoryKeywords[category] {
+			keyword = strings.Join(nameTokens(keyword), " ")
+			if len(keyword) > longest && strings.Contains(words, " "+keyword+" ") {
+				match, longest = category, len(keyword)
+			}
+		}
+	}
+
+	return match, longest > 0
+}
+
+// classifiedNames tokenizes the classified names, sorted by name so ties resolve the same way on
+// every run.
+func classifiedNames(categoryByName map[string]entity.Category) []classifiedName {
+	names := make([]string, 0, len(categoryByName))
+	for name := range categoryByName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	classified := make([]classifiedName, 0, len(names))
+	for _, name := range names {
+		tokens := nameTokens(name)
+		if len(tokens) > 0 {
+			classified = append(classified, classifiedName{tokens: tokens, category: categoryByName[name]})
+		}
+	}
+
+	return classified
+}
+
+// mostSimilarCategory returns the category of the classified name sharing the largest share of
+// tokens with the given ones, when that share reaches minNameSimilarity.
+func mostSimilarCategory(classified []classifiedName, tokens []string) (entity.Category, bool) {
+	var match entity.Category
+	best := 0.0
+	for _, candidate := range classified {
+		if similarity := tokenSimilarity(tokens, candidate.tokens); similarity > best {
+			match, best = candidate.category, similarity
+		}
+	}
+
+	return match, best >= minNameSimilarity
+}
+
+// tokenSimilarity is the Jaccard index of the two token sets.
+func tokenSimilarity(first, second []string) float64 {
+	firstSet := make(map[string]struct{}, len(first))
+	for _, token := range first {
+		firstSet[token] = struct{}{}
+	}
+	secondSet := make(map[string]struct{}, len(second))
+	for _, token := range second {
+		secondSet[token] = struct{}{}
+	}
+
+	shared := 0
+	for token := range secondSet {
+		if _, ok := firstSet[token]; ok {
+			shared++
+		}
+	}
+	union := len(firstSet) + len(secondSet) - shared
+	if union == 0 {
+		return 0
+	}
+
+	return float64(shared) / float64(union)
+}
+
+// nameTokens splits a name into lowercase words of letters and digits.
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
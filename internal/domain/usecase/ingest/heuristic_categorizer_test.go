@@ -0,0 +1,115 @@
+package ingest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+)
+
+func heuristicProfileSettings() entity.IngestProfileSettings {
+	settings := testBudgetGroupProfileSettings("ingest-profile")
+	settings.Categorizer = entity.CategorizerHeuristic
+	settings.Categories = []entity.Category{"Food", "Health", entity.DefaultFallbackCategory, "Transport"}
+	settings.Mappings = map[string]entity.Category{"Market": "Food", "Posto Shell Centro": "Transport"}
+	settings.SourceCategoryMappings = map[string]entity.Category{"Pharmacy": "Health"}
+	settings.CategoryKeywords = map[entity.Category][]string{
+		"Food":      {"ifood", "padaria"},
+		"Transport": {"uber", "uber eats"},
+	}
+	settings.BudgetGroupMappings = map[entity.Category]entity.BudgetGroup{"Food": "Fixed Costs"}
+
+	return settings
+}
+
+func TestClassifyHeuristically(t *testing.T) {
+	tests := []struct {
+		name            string
+		transactions    []entity.Transaction
+		knownCategories map[string]entity.Category
+		want            map[string]transactionClassifications
+	}{
+		{
+			name:         "mapping ignores case and spacing",
+			transactions: []entity.Transaction{{Name: " MARKET  "}},
+			want: map[string]transactionClassifications{
+				" MARKET  ": {Category: "Food", BudgetGroup: "Fixed Costs"},
+			},
+		},
+		{
+			name:         "keyword as whole words",
+			transactions: []entity.Transaction{{Name: "PADARIA-REAL"}, {Name: "Padariareal"}},
+			want: map[string]transactionClassifications{
+				"PADARIA-REAL": {Category: "Food", BudgetGroup: "Fixed Costs"},
+			},
+		},
+		{
+			name:         "longest keyword wins",
+			transactions: []entity.Transaction{{Name: "UBER *EATS"}},
+			want:         map[string]transactionClassifications{"UBER *EATS": {Category: "Transport"}},
+		},
+		{
+			name:         "similar mapped name",
+			transactions: []entity.Transaction{{Name: "POSTO SHELL"}},
+			want:         map[string]transactionClassifications{"POSTO SHELL": {Category: "Transport"}},
+		},
+		{
+			name:         "similar name classified in the same run",
+			transactions: []entity.Transaction{{Name: "Padaria Bom Gosto"}, {Name: "Bom Gosto Lanches"}},
+			want: map[string]transactionClassifications{
+				"Padaria Bom Gosto": {Category: "Food", BudgetGroup: "Fixed Costs"},
+				"Bom Gosto Lanches": {Category: "Food", BudgetGroup: "Fixed Costs"},
+			},
+		},
+		{
+			name:            "category known from a rule",
+			transactions:    []entity.Transaction{{Name: "Uber"}},
+			knownCategories: map[string]entity.Category{"Uber": "Food"},
+			want: map[string]transactionClassifications{
+				"Uber": {Category: "Food", BudgetGroup: "Fixed Costs"},
+			},
+		},
+		{
+			name:            "similar known name",
+			transactions:    []entity.Transaction{{Name: "CLINIC SAO PAULO 2"}},
+			knownCategories: map[string]entity.Category{"Clinic Sao Paulo": "Health"},
+			want:            map[string]transactionClassifications{"CLINIC SAO PAULO 2": {Category: "Health"}},
+		},
+		{
+			name:         "unmatched name is left out",
+			transactions: []entity.Transaction{{Name: "Shell"}, {Name: "Unknown"}},
+			want:         map[string]transactionClassifications{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := classifyHeuristically(
+				heuristicProfileSettings(),
+				test.knownCategories,
+				uniqueTransactionNames(test.transactions),
+			)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("classifyHeuristically() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenSimilarity(t *testing.T) {
+	tests := []struct {
+		first  []string
+		second []string
+		want   float64
+	}{
+		{first: []string{"posto", "shell"}, second: []string{"posto", "shell", "centro"}, want: 2.0 / 3},
+		{first: []string{"a", "a", "b"}, second: []string{"b", "c", "c"}, want: 1.0 / 3},
+		{first: nil, second: nil, want: 0},
+	}
+
+	for _, test := range tests {
+		if got := tokenSimilarity(test.first, test.second); got != test.want {
+			t.Fatalf("tokenSimilarity(%v, %v) = %v, want %v", test.first, test.second, got, test.want)
+		}
+	}
+}
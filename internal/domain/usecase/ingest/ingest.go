@@ -3,8 +3,12 @@ package ingest
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math"
+	"slices"
 	"sync"
 	"time"
 
@@ -15,28 +19,72 @@ import (
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/pkg/validator"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/companyapi"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/openfinance"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 )
 
+// categorizationAttempts is how many times a categorization chunk is requested while its answers
+// cannot be decoded.
+const categorizationAttempts = 3
+
 const (
 	categorizationSystemMessage = "Categorize each transaction name using only the supplied categories. " +
 		"Return one JSON object whose keys are the exact transaction names and whose values are category names. " +
+		"Source categories, when given, are the categories the bank assigned to some names; use them as hints. " +
 		"Use the fallback when uncertain."
 	combinedCategorizationSystemMessage = "Classify each transaction name in two dimensions using only the supplied values. " +
 		"Category describes what the transaction represents. Budget group describes its financial or budgeting purpose. " +
 		"Budget group examples map category names to budget groups; use them as guidance and infer a group for unmapped categories. " +
 		"Return one JSON object whose keys are the exact transaction names and whose values are objects with category and budget_group fields. " +
+		"Source categories, when given, are the categories the bank assigned to some names; use them as hints. " +
 		"Use each dimension's fallback when uncertain."
 )
 
 type IngestInput struct {
 	StartDate time.Time `validate:"required"`
 	EndDate   time.Time `validate:"required,gtefield=StartDate"`
+	Prune     PruneMode `validate:"omitempty,oneof=dry-run archive"`
+	// DryRun fetches and categorizes transactions without writing to any sheet, returning the
+	// planned changes instead.
+	DryRun bool
+}
+
+type IngestOutput struct {
+	// Reports has one entry per profile, in the order of the settings.
+	Reports []ProfileReport
+	// Plan lists the changes of a dry run, one entry per profile and month. It is empty otherwise.
+	Plan []TablePlan
 }
 
+// TablePlan lists the changes a dry run would make to one monthly table.
+type TablePlan struct {
+	IngestProfileID string
+	Title           string
+	// Create reports that the table does not exist yet and would be created.
+	Create bool
+	// Columns are the columns a new table would have, or those ensured on an existing table,
+	// which are only added when missing.
+	Columns []string
+	Insert  []entity.Transaction
+	Update  []entity.Transaction
+	Archive []entity.Transaction
+}
+
+// PruneMode controls what happens to rows whose transaction the provider no longer returns, such
+// as pending transactions that never posted. Pruning is off by default.
+type PruneMode string
+
+const (
+	PruneModeOff PruneMode = ""
+	// PruneModeDryRun logs the rows that would be archived without changing them.
+	PruneModeDryRun PruneMode = "dry-run"
+	// PruneModeArchive deletes the rows, archiving them in Notion.
+	PruneModeArchive PruneMode = "archive"
+)
+
 type IngestExecutor interface {
-	Execute(ctx context.Context, input IngestInput) error
+	Execute(ctx context.Context, input IngestInput) (IngestOutput, error)
 }
 
 type Ingest struct {
@@ -45,6 +93,7 @@ type Ingest struct {
 	settings                entity.IngestSettings
 	companyAPIProvider      companyapi.APIProvider
 	gptProvider             gpt.Provider
+	categorizationCache     kvstore.Provider
 	sheetProvider           sheet.Provider
 	openFinanceAPIProvider  openfinance.APIProvider
 }
@@ -55,6 +104,7 @@ func NewIngest(
 	settings entity.IngestSettings,
 	companyAPIProvider companyapi.APIProvider,
 	gptProvider gpt.Provider,
+	categorizationCache kvstore.Provider,
 	sheetProvider sheet.Provider,
 	openFinanceAPIProvider openfinance.APIProvider,
 ) *Ingest {
@@ -64,61 +114,103 @@ func NewIngest(
 		settings:                settings,
 		companyAPIProvider:      companyAPIProvider,
 		gptProvider:             gptProvider,
+		categorizationCache:     categorizationCache,
 		sheetProvider:           sheetProvider,
 		openFinanceAPIProvider:  openFinanceAPIProvider,
 	}
 }
 
-func (s *Ingest) Execute(ctx context.Context, input IngestInput) error {
+func (s *Ingest) Execute(ctx context.Context, input IngestInput) (IngestOutput, error) {
 	if err := s.val.Validate(input); err != nil {
-		return fmt.Errorf("invalid ingest input: %w", err)
+		return IngestOutput{}, fmt.Errorf("invalid ingest input: %w", err)
 	}
 
-	group, groupContext := errgroup.WithContext(ctx)
+	// Profiles share no context, so one profile failing, for example on an expired Pluggy secret,
+	// does not cancel the others.
+	var group errgroup.Group
 	group.SetLimit(s.maxConcurrentOperations)
 
-	for _, ingestProfileSettings := range s.settings.IngestProfiles {
+	months := monthsInRange(input.StartDate, input.EndDate)
+	reports := make([]ProfileReport, len(s.settings.IngestProfiles))
+	plansByProfile := make([][]TablePlan, len(s.settings.IngestProfiles))
+	for index, ingestProfileSettings := range s.settings.IngestProfiles {
 		group.Go(func() error {
-			if err := s.ingestProfile(groupContext, ingestProfileSettings, input); err != nil {
-				return fmt.Errorf("ingest profile %q: %w", ingestProfileSettings.ID, err)
-			}
+			profileMonths := monthReports{}
+			plans, err := s.ingestProfile(ctx, ingestProfileSettings, input, months, profileMonths)
+			reports[index] = profileMonths.profileReport(ingestProfileSettings.ID, months, err)
+			plansByProfile[index] = plans
 
 			return nil
 		})
 	}
+	_ = group.Wait()
 
-	if err := group.Wait(); err != nil {
-		return fmt.Errorf("ingest profiles: %w", err)
+	var errs []error
+	for _, report := range reports {
+		if report.Err != nil {
+			errs = append(errs, fmt.Errorf("ingest profile %q: %w", report.IngestProfileID, report.Err))
+		}
 	}
 
-	return nil
+	output := IngestOutput{Reports: reports, Plan: slices.Concat(plansByProfile...)}
+	if len(errs) > 0 {
+		return output, fmt.Errorf("ingest profiles: %w", errors.Join(errs...))
+	}
+
+	return output, nil
 }
 
+// ingestProfile syncs the transactions of one profile, counting the work done in the reports.
+// A month that fails does not stop the others; the returned error joins their errors.
 func (s *Ingest) ingestProfile(
 	ctx context.Context,
 	settings entity.IngestProfileSettings,
 	input IngestInput,
-) error {
-	transactions, err := s.openFinanceAPIProvider.ListTransactionsByIngestProfileID(
+	months []time.Time,
+	reports monthReports,
+) ([]TablePlan, error) {
+	// Refunds may arrive up to the refund window after their purchase, so purchases dated before
+	// the range are listed too, only to be matched.
+	listStartDate := input.StartDate
+	if settings.RefundMode != entity.RefundModeOff {
+		listStartDate = input.StartDate.AddDate(0, 0, -settings.RefundWindowDays)
+	}
+	listed, err := s.openFinanceAPIProvider.ListTransactionsByIngestProfileID(
 		ctx,
 		settings.ID,
-		input.StartDate,
+		listStartDate,
 		input.EndDate,
 	)
 	if err != nil {
-		return fmt.Errorf("list transactions: %w", err)
+		return nil, fmt.Errorf("list transactions: %w", err)
 	}
-	transactions = filterTransactions(transactions, settings.IgnoreSamePersonTransfers)
+	earlier, transactions := splitEarlierTransactions(listed, input.StartDate)
+	for _, transaction := range transactions {
+		reports.month(transaction.Date).Fetched++
+	}
+	upstreamExternalIDs := externalIDs(transactions)
+	transactions = matchRefunds(slices.Concat(earlier, transactions), settings, reports)
+	earlier, transactions = splitEarlierTransactions(transactions, input.StartDate)
+	earlier = slices.DeleteFunc(earlier, func(transaction entity.Transaction) bool {
+		return !transaction.Refunded
+	})
+	transactions = filterTransactions(slices.Concat(earlier, transactions), settings, reports)
 
-	s.enrichTransactionNames(ctx, transactions)
+	s.enrichTransactionNames(ctx, transactions, reports)
 
-	if err := s.categorizeTransactions(ctx, settings, transactions); err != nil {
-		return fmt.Errorf("categorize transactions: %w", err)
+	sources, err := s.categorizeTransactions(ctx, settings, transactions)
+	if err != nil {
+		return nil, fmt.Errorf("categorize transactions: %w", err)
+	}
+	for index, transaction := range transactions {
+		reports.month(transaction.Date).Categorized[sources[index]]++
 	}
+	transactions = splitTransactions(settings, transactions)
+	earlier, transactions = splitEarlierTransactions(transactions, input.StartDate)
 
 	tables, err := s.sheetProvider.ListTables(ctx, settings.ID)
 	if err != nil {
-		return fmt.Errorf("list tables: %w", err)
+		return nil, fmt.Errorf("list tables: %w", err)
 	}
 
 	tableByTitle := make(map[string]sheet.Table, len(tables))
@@ -126,37 +218,207 @@ func (s *Ingest) ingestProfile(
 		tableByTitle[table.Title] = table
 	}
 
+	prune := input.Prune
+	if prune != PruneModeOff && len(upstreamExternalIDs) == 0 {
+		slog.Warn("skipping prune because no transactions were listed", "ingest_profile", settings.ID)
+		prune = PruneModeOff
+	}
+
+	var plans []TablePlan
+	var errs []error
 	transactionsByMonth := groupTransactionsByMonth(transactions)
-	for _, month := range monthsInRange(input.StartDate, input.EndDate) {
-		prepared, err := s.prepareTransactionTable(
+	for _, month := range months {
+		monthReport := reports.month(month)
+		plan, err := s.ingestMonth(
 			ctx,
 			settings,
+			input,
 			month,
 			tableByTitle,
 			transactionsByMonth[newTransactionMonth(month)],
+			prune,
+			upstreamExternalIDs,
+			monthReport,
 		)
 		if err != nil {
-			return err
+			monthReport.Err = err
+			errs = append(errs, err)
+
+			continue
+		}
+		if input.DryRun {
+			plans = append(plans, plan)
+		}
+	}
+	plans = append(plans, s.updateEarlierRefundedPurchases(ctx, settings, input, tableByTitle, earlier)...)
+
+	return plans, errors.Join(errs...)
+}
+
+// splitEarlierTransactions separates the transactions dated before the start date from the others.
+func splitEarlierTransactions(
+	transactions []entity.Transaction,
+	startDate time.Time,
+) ([]entity.Transaction, []entity.Transaction) {
+	var earlier, inRange []entity.Transaction
+	for _, transaction := range transactions {
+		if transaction.Date.Before(startDate) {
+			earlier = append(earlier, transaction)
+		} else {
+			inRange = append(inRange, transaction)
+		}
+	}
+
+	return earlier, inRange
+}
+
+// updateEarlierRefundedPurchases updates the rows of the purchases dated before the range that a
+// refund in the range changed. Months outside the range get no new rows, tables or pruning, and
+// are left out of the report, so failing to update them is only logged.
+func (s *Ingest) updateEarlierRefundedPurchases(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	input IngestInput,
+	tableByTitle map[string]sheet.Table,
+	purchases []entity.Transaction,
+) []TablePlan {
+	var plans []TablePlan
+	for _, monthPurchases := range groupTransactionsByMonth(purchases) {
+		month := monthPurchases[0].Date
+		if _, _, exists := transactionTableForMonth(tableByTitle, month, settings.Language); !exists {
+			continue
+		}
+
+		prepared, err := s.prepareTransactionTable(ctx, settings, month, tableByTitle, monthPurchases, input.DryRun)
+		if err != nil {
+			slog.Warn("failed to update refunded purchases", "ingest_profile", settings.ID, "error", err)
+
+			continue
 		}
+		if len(prepared.updates) == 0 {
+			continue
+		}
+		if input.DryRun {
+			// Only the updates are applied, so the plan leaves out the rows that would be new.
+			prepared.transactions = nil
+			plans = append(plans, prepared.plan(settings.ID, nil))
 
-		if err := s.insertTransactions(
+			continue
+		}
+
+		if _, err := s.updateTransactions(
 			ctx,
-			settings.ID,
+			settings,
 			prepared.table.ID,
 			prepared.language,
-			prepared.transactions,
+			prepared.updates,
 		); err != nil {
-			return fmt.Errorf("insert transactions into table %q: %w", prepared.table.Title, err)
+			slog.Warn(
+				"failed to update refunded purchases",
+				"ingest_profile", settings.ID,
+				"table", prepared.table.Title,
+				"error", err,
+			)
 		}
 	}
 
-	return nil
+	return plans
+}
+
+// ingestMonth writes the transactions of one month to its table, or only plans the changes in a
+// dry run.
+func (s *Ingest) ingestMonth(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	input IngestInput,
+	month time.Time,
+	tableByTitle map[string]sheet.Table,
+	transactions []entity.Transaction,
+	prune PruneMode,
+	upstreamExternalIDs map[string]struct{},
+	report *MonthReport,
+) (TablePlan, error) {
+	prepared, err := s.prepareTransactionTable(ctx, settings, month, tableByTitle, transactions, input.DryRun)
+	if err != nil {
+		return TablePlan{}, err
+	}
+
+	report.Title = prepared.table.Title
+	report.Duplicates = len(transactions) - len(prepared.transactions) - len(prepared.updates)
+
+	var vanished []existingTransactionRow
+	if prune != PruneModeOff {
+		vanished = vanishedTransactionRows(prepared.existing, upstreamExternalIDs, input.StartDate, input.EndDate)
+	}
+
+	if input.DryRun {
+		return prepared.plan(settings.ID, vanished), nil
+	}
+
+	inserted, err := s.insertTransactions(
+		ctx,
+		settings,
+		prepared.table.ID,
+		prepared.language,
+		prepared.transactions,
+	)
+	report.Inserted = inserted
+	report.Failed += len(prepared.transactions) - inserted
+	if err != nil {
+		return TablePlan{}, fmt.Errorf("insert transactions into table %q: %w", prepared.table.Title, err)
+	}
+
+	updated, err := s.updateTransactions(
+		ctx,
+		settings,
+		prepared.table.ID,
+		prepared.language,
+		prepared.updates,
+	)
+	report.Updated = updated
+	report.Failed += len(prepared.updates) - updated
+	if err != nil {
+		return TablePlan{}, fmt.Errorf("update transactions in table %q: %w", prepared.table.Title, err)
+	}
+
+	archived, err := s.pruneTransactions(ctx, settings.ID, prepared.table, prune, vanished)
+	report.Archived = archived
+	if err != nil {
+		return TablePlan{}, fmt.Errorf("prune transactions from table %q: %w", prepared.table.Title, err)
+	}
+
+	return TablePlan{}, nil
 }
 
 type preparedTransactionTable struct {
 	table        sheet.Table
 	language     entity.Language
+	created      bool
+	columns      []string
+	existing     []existingTransactionRow
 	transactions []entity.Transaction
+	updates      []transactionRowUpdate
+}
+
+func (p preparedTransactionTable) plan(
+	ingestProfileID string,
+	vanished []existingTransactionRow,
+) TablePlan {
+	plan := TablePlan{
+		IngestProfileID: ingestProfileID,
+		Title:           p.table.Title,
+		Create:          p.created,
+		Columns:         p.columns,
+		Insert:          p.transactions,
+	}
+	for _, update := range p.updates {
+		plan.Update = append(plan.Update, update.transaction)
+	}
+	for _, row := range vanished {
+		plan.Archive = append(plan.Archive, row.transaction)
+	}
+
+	return plan
 }
 
 func (s *Ingest) prepareTransactionTable(
@@ -165,62 +427,97 @@ func (s *Ingest) prepareTransactionTable(
 	month time.Time,
 	tableByTitle map[string]sheet.Table,
 	transactions []entity.Transaction,
+	dryRun bool,
 ) (preparedTransactionTable, error) {
 	configuredLanguage := normalizedLanguage(settings.Language)
 	title := localizedTransactionTableTitle(month, configuredLanguage)
 	table, tableLanguage, exists := transactionTableForMonth(tableByTitle, month, configuredLanguage)
 	if !exists {
-		created, err := s.sheetProvider.CreateTable(
-			ctx,
-			settings.ID,
-			transactionTableDefinition(title, settings),
-		)
+		definition := transactionTableDefinition(title, settings)
+		prepared := preparedTransactionTable{
+			table:        sheet.Table{Title: title},
+			language:     configuredLanguage,
+			created:      true,
+			transactions: transactions,
+		}
+		for _, column := range definition.Columns() {
+			prepared.columns = append(prepared.columns, column.Name())
+		}
+		if dryRun {
+			return prepared, nil
+		}
+
+		created, err := s.sheetProvider.CreateTable(ctx, settings.ID, definition)
 		if err != nil {
 			return preparedTransactionTable{}, fmt.Errorf("create table %q: %w", title, err)
 		}
 
 		tableByTitle[title] = created
+		prepared.table = created
 
-		return preparedTransactionTable{
-			table:        created,
-			language:     configuredLanguage,
-			transactions: transactions,
-		}, nil
+		return prepared, nil
 	}
 
+	columns := []sheet.Column{externalIDTableColumn(tableLanguage)}
+	// Tables created before the profile included credits lack the income categories.
+	if settings.IncludeCredits {
+		columns = append(columns, categoryTableColumn(settings, tableLanguage))
+	}
 	if budgetGroupColumn, enabled := budgetGroupTableColumn(settings, tableLanguage); enabled {
+		columns = append(columns, budgetGroupColumn)
+	}
+	if needsReviewColumn, enabled := needsReviewTableColumn(settings, tableLanguage); enabled {
+		columns = append(columns, needsReviewColumn)
+	}
+	if parentIDColumn, enabled := parentIDTableColumn(settings, tableLanguage); enabled {
+		columns = append(columns, parentIDColumn)
+	}
+	if directionColumn, enabled := directionTableColumn(settings, tableLanguage); enabled {
+		columns = append(columns, directionColumn)
+	}
+	if refundMatchIDColumn, enabled := refundMatchIDTableColumn(settings, tableLanguage); enabled {
+		columns = append(columns, refundMatchIDColumn)
+	}
+	columns = append(columns, installmentTableColumns(settings, tableLanguage)...)
+	columnNames := make([]string, 0, len(columns))
+	for _, column := range columns {
+		columnNames = append(columnNames, column.Definition().Name())
+	}
+	if !dryRun {
 		if err := s.sheetProvider.EnsureTableColumns(
 			ctx,
 			settings.ID,
 			table.ID,
-			budgetGroupColumn,
+			columns...,
 		); err != nil {
 			return preparedTransactionTable{}, fmt.Errorf("upgrade table %q: %w", table.Title, err)
 		}
 	}
 
-	newTransactions, err := s.onlyNewTransactions(
-		ctx,
-		settings.ID,
-		table.ID,
-		tableLanguage,
-		transactions,
-	)
+	existing, err := s.existingTransactionRows(ctx, settings.ID, table.ID, tableLanguage)
 	if err != nil {
 		return preparedTransactionTable{}, fmt.Errorf("filter transactions for table %q: %w", table.Title, err)
 	}
+	newTransactions, updates := planTransactionWrites(settings, existing, transactions)
 
 	return preparedTransactionTable{
 		table:        table,
 		language:     tableLanguage,
+		columns:      columnNames,
+		existing:     existing,
 		transactions: newTransactions,
+		updates:      updates,
 	}, nil
 }
 
-func (s *Ingest) enrichTransactionNames(ctx context.Context, transactions []entity.Transaction) {
+func (s *Ingest) enrichTransactionNames(
+	ctx context.Context,
+	transactions []entity.Transaction,
+	reports monthReports,
+) {
 	documents := uniqueCompanyDocuments(transactions)
 	companyNameByDocument := s.lookupCompanyNames(ctx, documents)
-	applyCompanyNames(transactions, companyNameByDocument)
+	applyCompanyNames(transactions, companyNameByDocument, reports)
 }
 
 func uniqueCompanyDocuments(transactions []entity.Transaction) []string {
@@ -284,88 +581,366 @@ func (s *Ingest) lookupCompanyName(ctx context.Context, document string) (string
 	return company.Name, company.Name != ""
 }
 
-func applyCompanyNames(transactions []entity.Transaction, companyNameByDocument map[string]string) {
+func applyCompanyNames(
+	transactions []entity.Transaction,
+	companyNameByDocument map[string]string,
+	reports monthReports,
+) {
 	for index := range transactions {
 		document := docutil.CleanDocument(transactions[index].Name)
 		if name, ok := companyNameByDocument[document]; ok {
 			transactions[index].Name = name
+			reports.month(transactions[index].Date).Renamed++
 		}
 	}
 }
 
+// categorizeTransactions classifies the transactions, and returns where each category came from.
+// Profiles that include credits categorize debits and credits apart, each with the settings of
+// its direction, so GPT only sees the categories valid for the direction.
 func (s *Ingest) categorizeTransactions(
 	ctx context.Context,
 	settings entity.IngestProfileSettings,
 	transactions []entity.Transaction,
-) error {
-	names := uniqueTransactionNames(transactions)
-	if len(names) == 0 {
-		return nil
+) ([]CategorySource, error) {
+	return s.categorize(ctx, settings, transactions, true)
+}
+
+// categorize classifies the transactions like categorizeTransactions. Without askGPT it has no side
+// effects: names the rules, mappings, cache and heuristic categorizer leave unresolved are not sent
+// to GPT and get the fallbacks, as when GPT gives no answer, and nothing is cached.
+func (s *Ingest) categorize(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	transactions []entity.Transaction,
+	askGPT bool,
+) ([]CategorySource, error) {
+	if !settings.IncludeCredits {
+		return s.categorizeDirection(ctx, settings, transactions, askGPT)
+	}
+
+	sources := make([]CategorySource, len(transactions))
+	for _, direction := range entity.TransactionDirections {
+		var indexes []int
+		var directionTransactions []entity.Transaction
+		for index, transaction := range transactions {
+			if transaction.CategoryDirection() == direction {
+				indexes = append(indexes, index)
+				directionTransactions = append(directionTransactions, transaction)
+			}
+		}
+		if len(directionTransactions) == 0 {
+			continue
+		}
+
+		directionSources, err := s.categorizeDirection(
+			ctx,
+			settings.ForDirection(direction),
+			directionTransactions,
+			askGPT,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("direction %s: %w", direction, err)
+		}
+		for position, index := range indexes {
+			transactions[index] = directionTransactions[position]
+			sources[index] = directionSources[position]
+		}
+	}
+
+	return sources, nil
+}
+
+// categorizeDirection classifies the transactions with the profile's split and category rules
+// first, then with its source category mappings and the categorization cache, learned corrections
+// included, and asks GPT only for the names none of them resolved, hinting the source categories.
+// Profiles using the heuristic categorizer classify those names offline instead, without caching
+// the results. It returns where each category came from and flags the low-confidence ones for
+// review. Without askGPT, the names left for GPT take the profile's mapping for them instead, if
+// any, and the fallbacks otherwise.
+func (s *Ingest) categorizeDirection(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	transactions []entity.Transaction,
+	askGPT bool,
+) ([]CategorySource, error) {
+	assignments := applyCategoryRules(settings, transactions)
+	applySourceCategoryMappings(settings, transactions, assignments)
+	applySplitRules(settings, transactions, assignments)
+
+	var pending []entity.Transaction
+	for index, assignment := range assignments {
+		if !assignment.complete(settings) {
+			pending = append(pending, transactions[index])
+		}
+	}
+
+	names := uniqueTransactionNames(pending)
+	cached := s.cachedClassifications(ctx, settings, names)
+	classificationsByName := make(map[string]transactionClassifications, len(names))
+	uncachedNames := make([]string, 0, len(names))
+	for _, name := range names {
+		if entry, exists := cached[name]; exists {
+			classificationsByName[name] = entry.classifications()
+
+			continue
+		}
+		uncachedNames = append(uncachedNames, name)
+	}
+
+	switch {
+	case settings.Categorizer == entity.CategorizerHeuristic:
+		knownCategories := make(map[string]entity.Category)
+		for index, assignment := range assignments {
+			if assignment.category && !assignment.split {
+				knownCategories[transactions[index].CategorizationName()] = transactions[index].Category
+			}
+		}
+		for name, classifications := range classificationsByName {
+			knownCategories[name] = classifications.Category
+		}
+
+		maps.Copy(classificationsByName, classifyHeuristically(settings, knownCategories, uncachedNames))
+	case askGPT:
+		answers, err := s.classifyNames(ctx, settings, uncachedNames, sourceCategoriesByName(pending))
+		if err != nil {
+			return nil, err
+		}
+		s.cacheClassifications(ctx, settings, answers)
+		maps.Copy(classificationsByName, answers)
+	default:
+		maps.Copy(classificationsByName, mappedClassifications(settings, uncachedNames))
+	}
+
+	sources := make([]CategorySource, len(transactions))
+	for index := range transactions {
+		assignment := assignments[index]
+		if !assignment.complete(settings) {
+			classifications := classificationsByName[transactions[index].CategorizationName()].withFallbacks(settings)
+			if !assignment.category {
+				transactions[index].Category = classifications.Category
+			}
+			if !assignment.budgetGroup && len(settings.BudgetGroups) > 0 {
+				transactions[index].BudgetGroup = classifications.BudgetGroup
+			}
+		}
+
+		entry, cachedName := cached[transactions[index].CategorizationName()]
+		switch {
+		case assignment.split:
+			sources[index] = CategorySourceSplit
+		case assignment.sourceCategory:
+			sources[index] = CategorySourceSourceCategory
+		case assignment.category:
+			sources[index] = CategorySourceRule
+		case cachedName && entry.Learned:
+			sources[index] = CategorySourceCorrection
+		case cachedName:
+			sources[index] = CategorySourceCache
+		default:
+			sources[index] = categorySource(settings, transactions[index])
+		}
+		transactions[index].NeedsReview = sources[index].Confidence() == CategoryConfidenceLow
+	}
+
+	return sources, nil
+}
+
+// mappedClassifications classifies the names the profile maps, with the budget group the profile
+// maps their category to.
+func mappedClassifications(
+	settings entity.IngestProfileSettings,
+	names []string,
+) map[string]transactionClassifications {
+	classificationsByName := make(map[string]transactionClassifications)
+	for _, name := range names {
+		if category, mapped := settings.Mappings[name]; mapped {
+			classificationsByName[name] = transactionClassifications{
+				Category:    category,
+				BudgetGroup: settings.BudgetGroupMappings[category],
+			}
+		}
+	}
+
+	return classificationsByName
+}
+
+type transactionClassifications struct {
+	Category    entity.Category    `json:"category"`
+	BudgetGroup entity.BudgetGroup `json:"budget_group"`
+}
+
+// configured reports whether the classifications are complete and use only the profile's values.
+func (c transactionClassifications) configured(settings entity.IngestProfileSettings) bool {
+	return slices.Contains(settings.Categories, c.Category) &&
+		(len(settings.BudgetGroups) == 0 || slices.Contains(settings.BudgetGroups, c.BudgetGroup))
+}
+
+func (c transactionClassifications) withFallbacks(settings entity.IngestProfileSettings) transactionClassifications {
+	if c.Category == "" {
+		c.Category = settings.Fallback
+	}
+	if c.BudgetGroup == "" {
+		c.BudgetGroup = settings.BudgetGroupFallback
+	}
+
+	return c
+}
+
+// classifyNames asks GPT for the category, and the budget group when the profile uses them, of
+// every transaction name, hinting the source categories of the names that have one. Names are
+// sent in chunks of the profile's size, concurrently. It returns only the configured values GPT
+// answered, leaving out names it skipped and leaving empty the classifications it got wrong. A
+// chunk whose answers cannot be decoded is retried, and its names are left out once the attempts
+// run out, so they get the fallbacks.
+func (s *Ingest) classifyNames(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	names []string,
+	sourceCategories map[string]string,
+) (map[string]transactionClassifications, error) {
+	classificationsByName := make(map[string]transactionClassifications, len(names))
+	var mutex sync.Mutex
+	group, groupContext := errgroup.WithContext(ctx)
+	group.SetLimit(s.maxConcurrentOperations)
+
+	for chunk := range slices.Chunk(names, settings.CategorizationChunkSize) {
+		group.Go(func() error {
+			classifications, err := s.classifyChunk(groupContext, settings, chunk, sourceCategories)
+			if err != nil {
+				return err
+			}
+
+			mutex.Lock()
+			maps.Copy(classificationsByName, classifications)
+			mutex.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return classificationsByName, nil
+}
+
+func (s *Ingest) classifyChunk(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	names []string,
+	sourceCategories map[string]string,
+) (map[string]transactionClassifications, error) {
+	hints := make(map[string]string)
+	for _, name := range names {
+		if sourceCategory, ok := sourceCategories[name]; ok {
+			hints[name] = sourceCategory
+		}
 	}
+
+	for attempt := 1; ; attempt++ {
+		classifications, err := s.requestClassifications(ctx, settings, names, hints)
+		var decodeErr completionDecodeError
+		if !errors.As(err, &decodeErr) {
+			return classifications, err
+		}
+		if attempt == categorizationAttempts {
+			slog.Warn(
+				"using fallbacks for undecodable categorization chunk",
+				"ingest_profile", settings.ID,
+				"names", len(names),
+				"error", err,
+			)
+
+			return map[string]transactionClassifications{}, nil
+		}
+
+		slog.Warn(
+			"retrying undecodable categorization chunk",
+			"ingest_profile", settings.ID,
+			"attempt", attempt,
+			"error", err,
+		)
+	}
+}
+
+// completionDecodeError marks a chat completion whose content is not the requested JSON, which
+// asking again may fix.
+type completionDecodeError struct {
+	err error
+}
+
+func (e completionDecodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e completionDecodeError) Unwrap() error {
+	return e.err
+}
+
+func (s *Ingest) requestClassifications(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	names []string,
+	sourceCategories map[string]string,
+) (map[string]transactionClassifications, error) {
 	if len(settings.BudgetGroups) > 0 {
-		return s.categorizeTransactionsWithBudgetGroups(ctx, settings, transactions, names)
+		return s.requestClassificationsWithBudgetGroups(ctx, settings, names, sourceCategories)
 	}
 
 	payload, err := json.Marshal(struct {
 		TransactionNames []string                   `json:"transaction_names"`
+		SourceCategories map[string]string          `json:"source_categories,omitempty"`
 		Categories       []entity.Category          `json:"categories"`
 		Mappings         map[string]entity.Category `json:"examples"`
 		Fallback         entity.Category            `json:"fallback"`
 	}{
 		TransactionNames: names,
+		SourceCategories: sourceCategories,
 		Categories:       settings.Categories,
 		Mappings:         settings.Mappings,
 		Fallback:         settings.Fallback,
 	})
 	if err != nil {
-		return fmt.Errorf("marshal categorization input: %w", err)
+		return nil, fmt.Errorf("marshal categorization input: %w", err)
 	}
 
 	content, err := s.gptProvider.CreateChatCompletion(
 		ctx,
+		settings.ID,
 		string(payload),
 		gpt.WithSystemMessage(categorizationSystemMessage),
-		gpt.WithJSONResponse(),
+		gpt.WithJSONSchema("transaction_categories", categorizationSchema(names, settings)),
 	)
 	if err != nil {
-		return fmt.Errorf("create categorization chat completion: %w", err)
+		return nil, fmt.Errorf("create categorization chat completion: %w", err)
 	}
 
 	categoryByName := make(map[string]entity.Category)
 	if err := json.Unmarshal([]byte(content), &categoryByName); err != nil {
-		return fmt.Errorf("decode categorization chat completion: %w", err)
-	}
-
-	allowedCategories := make(map[entity.Category]struct{}, len(settings.Categories))
-	for _, category := range settings.Categories {
-		allowedCategories[category] = struct{}{}
+		return nil, completionDecodeError{fmt.Errorf("decode categorization chat completion: %w", err)}
 	}
 
-	for index := range transactions {
-		category, ok := categoryByName[transactions[index].Name]
-		if _, allowed := allowedCategories[category]; !ok || !allowed {
-			category = settings.Fallback
+	classificationsByName := make(map[string]transactionClassifications, len(names))
+	for _, name := range names {
+		if category, ok := categoryByName[name]; ok && slices.Contains(settings.Categories, category) {
+			classificationsByName[name] = transactionClassifications{Category: category}
 		}
-
-		transactions[index].Category = category
 	}
 
-	return nil
+	return classificationsByName, nil
 }
 
-type transactionClassifications struct {
-	Category    entity.Category    `json:"category"`
-	BudgetGroup entity.BudgetGroup `json:"budget_group"`
-}
-
-func (s *Ingest) categorizeTransactionsWithBudgetGroups(
+func (s *Ingest) requestClassificationsWithBudgetGroups(
 	ctx context.Context,
 	settings entity.IngestProfileSettings,
-	transactions []entity.Transaction,
 	names []string,
-) error {
+	sourceCategories map[string]string,
+) (map[string]transactionClassifications, error) {
 	payload, err := json.Marshal(struct {
 		TransactionNames    []string                               `json:"transaction_names"`
+		SourceCategories    map[string]string                      `json:"source_categories,omitempty"`
 		Categories          []entity.Category                      `json:"categories"`
 		CategoryMappings    map[string]entity.Category             `json:"category_examples"`
 		CategoryFallback    entity.Category                        `json:"category_fallback"`
@@ -374,6 +949,7 @@ func (s *Ingest) categorizeTransactionsWithBudgetGroups(
 		BudgetGroupFallback entity.BudgetGroup                     `json:"budget_group_fallback"`
 	}{
 		TransactionNames:    names,
+		SourceCategories:    sourceCategories,
 		Categories:          settings.Categories,
 		CategoryMappings:    settings.Mappings,
 		CategoryFallback:    settings.Fallback,
@@ -382,64 +958,111 @@ func (s *Ingest) categorizeTransactionsWithBudgetGroups(
 		BudgetGroupFallback: settings.BudgetGroupFallback,
 	})
 	if err != nil {
-		return fmt.Errorf("marshal combined categorization input: %w", err)
+		return nil, fmt.Errorf("marshal combined categorization input: %w", err)
 	}
 
 	content, err := s.gptProvider.CreateChatCompletion(
 		ctx,
+		settings.ID,
 		string(payload),
 		gpt.WithSystemMessage(combinedCategorizationSystemMessage),
-		gpt.WithJSONResponse(),
+		gpt.WithJSONSchema("transaction_classifications", combinedCategorizationSchema(names, settings)),
 	)
 	if err != nil {
-		return fmt.Errorf("create combined categorization chat completion: %w", err)
+		return nil, fmt.Errorf("create combined categorization chat completion: %w", err)
 	}
 
-	classificationsByName := make(map[string]transactionClassifications)
-	if err := json.Unmarshal([]byte(content), &classificationsByName); err != nil {
-		return fmt.Errorf("decode combined categorization chat completion: %w", err)
+	answersByName := make(map[string]transactionClassifications)
+	if err := json.Unmarshal([]byte(content), &answersByName); err != nil {
+		return nil, completionDecodeError{fmt.Errorf("decode combined categorization chat completion: %w", err)}
 	}
 
-	allowedCategories := make(map[entity.Category]struct{}, len(settings.Categories))
-	for _, category := range settings.Categories {
-		allowedCategories[category] = struct{}{}
-	}
-	allowedBudgetGroups := make(map[entity.BudgetGroup]struct{}, len(settings.BudgetGroups))
-	for _, budgetGroup := range settings.BudgetGroups {
-		allowedBudgetGroups[budgetGroup] = struct{}{}
-	}
+	classificationsByName := make(map[string]transactionClassifications, len(names))
+	for _, name := range names {
+		answer, ok := answersByName[name]
+		if !ok {
+			continue
+		}
 
-	for index := range transactions {
-		classifications, ok := classificationsByName[transactions[index].Name]
-		if _, allowed := allowedCategories[classifications.Category]; !ok || !allowed {
-			classifications.Category = settings.Fallback
+		var classifications transactionClassifications
+		if slices.Contains(settings.Categories, answer.Category) {
+			classifications.Category = answer.Category
 		}
-		if _, allowed := allowedBudgetGroups[classifications.BudgetGroup]; !ok || !allowed {
-			classifications.BudgetGroup = settings.BudgetGroupFallback
+		if slices.Contains(settings.BudgetGroups, answer.BudgetGroup) {
+			classifications.BudgetGroup = answer.BudgetGroup
 		}
+		classificationsByName[name] = classifications
+	}
+
+	return classificationsByName, nil
+}
+
+// categorizationSchema requires a category for every name, limited to the profile's categories.
+func categorizationSchema(names []string, settings entity.IngestProfileSettings) map[string]any {
+	return namesObjectSchema(names, map[string]any{"type": "string", "enum": settings.Categories})
+}
+
+// combinedCategorizationSchema requires a category and a budget group for every name, limited to
+// the profile's values.
+func combinedCategorizationSchema(names []string, settings entity.IngestProfileSettings) map[string]any {
+	return namesObjectSchema(names, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"category":     map[string]any{"type": "string", "enum": settings.Categories},
+			"budget_group": map[string]any{"type": "string", "enum": settings.BudgetGroups},
+		},
+		"required":             []string{"category", "budget_group"},
+		"additionalProperties": false,
+	})
+}
 
-		transactions[index].Category = classifications.Category
-		transactions[index].BudgetGroup = classifications.BudgetGroup
+// namesObjectSchema describes an object with one required property per name, each following the
+// value schema.
+func namesObjectSchema(names []string, value map[string]any) map[string]any {
+	properties := make(map[string]any, len(names))
+	for _, name := range names {
+		properties[name] = value
 	}
 
-	return nil
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             names,
+		"additionalProperties": false,
+	}
 }
 
+// uniqueTransactionNames returns the names the transactions are categorized by, once each.
 func uniqueTransactionNames(transactions []entity.Transaction) []string {
 	names := make([]string, 0, len(transactions))
 	seen := make(map[string]struct{}, len(transactions))
 	for _, transaction := range transactions {
-		if _, exists := seen[transaction.Name]; exists {
+		name := transaction.CategorizationName()
+		if _, exists := seen[name]; exists {
 			continue
 		}
 
-		seen[transaction.Name] = struct{}{}
-		names = append(names, transaction.Name)
+		seen[name] = struct{}{}
+		names = append(names, name)
 	}
 
 	return names
 }
 
+// sourceCategoriesByName returns the first source category found for each name the transactions
+// are categorized by.
+func sourceCategoriesByName(transactions []entity.Transaction) map[string]string {
+	sourceCategories := make(map[string]string)
+	for _, transaction := range transactions {
+		name := transaction.CategorizationName()
+		if _, exists := sourceCategories[name]; !exists && transaction.SourceCategory != "" {
+			sourceCategories[name] = transaction.SourceCategory
+		}
+	}
+
+	return sourceCategories
+}
+
 type transactionMonth struct {
 	year  int
 	month time.Month
@@ -491,74 +1114,298 @@ func monthsInRange(startDate, endDate time.Time) []time.Time {
 	return months
 }
 
-func (s *Ingest) onlyNewTransactions(
+// transactionRowUpdate rewrites the existing row with the given ID with a transaction that
+// changed at the source.
+type transactionRowUpdate struct {
+	rowID       string
+	transaction entity.Transaction
+}
+
+type existingTransactionRow struct {
+	id          string
+	transaction entity.Transaction
+}
+
+func (s *Ingest) existingTransactionRows(
 	ctx context.Context,
 	ingestProfileID, tableID string,
 	language entity.Language,
-	transactions []entity.Transaction,
-) ([]entity.Transaction, error) {
-	rows, err := s.sheetProvider.ListRows(ctx, ingestProfileID, tableID)
+) ([]existingTransactionRow, error) {
+	records, err := s.sheetProvider.ListRows(ctx, ingestProfileID, tableID)
 	if err != nil {
 		return nil, fmt.Errorf("list existing transactions: %w", err)
 	}
 
-	existingTransactions := make([]entity.Transaction, 0, len(rows))
-	for _, row := range rows {
-		transaction, err := rowToTransaction(row, language)
+	rows := make([]existingTransactionRow, 0, len(records))
+	for _, record := range records {
+		transaction, err := rowToTransaction(record.Row, language)
 		if err != nil {
 			return nil, fmt.Errorf("map existing transaction row: %w", err)
 		}
-		existingTransactions = append(existingTransactions, transaction)
+		rows = append(rows, existingTransactionRow{id: record.ID, transaction: transaction})
 	}
 
-	seen := make(map[string]struct{}, len(existingTransactions)+len(transactions))
-	for _, transaction := range existingTransactions {
-		seen[transaction.ID()] = struct{}{}
+	return rows, nil
+}
+
+// planTransactionWrites compares the transactions with the rows already in the table. It returns
+// the transactions without a row and, in the reconcile sync mode, the rows to update. The parts
+// of a split count as their source transaction, so each source transaction is written once.
+func planTransactionWrites(
+	settings entity.IngestProfileSettings,
+	existing []existingTransactionRow,
+	transactions []entity.Transaction,
+) ([]entity.Transaction, []transactionRowUpdate) {
+	seen := make(map[string]struct{}, len(existing)+len(transactions))
+	seenSources := make(map[string]struct{}, len(existing))
+	unmatchedLegacyRows := make(map[string]int)
+	rowByExternalID := make(map[string]existingTransactionRow, len(existing))
+	for _, row := range existing {
+		seen[row.transaction.ID()] = struct{}{}
+		seenSources[row.transaction.SourceID()] = struct{}{}
+		if row.transaction.ExternalID == "" {
+			unmatchedLegacyRows[row.transaction.LegacyID()]++
+
+			continue
+		}
+		if _, exists := rowByExternalID[row.transaction.ExternalID]; !exists {
+			rowByExternalID[row.transaction.ExternalID] = row
+		}
 	}
 
 	newTransactions := make([]entity.Transaction, 0, len(transactions))
+	var updates []transactionRowUpdate
 	for _, transaction := range transactions {
 		id := transaction.ID()
 		if _, exists := seen[id]; exists {
+			stored, ok := rowByExternalID[transaction.ExternalID]
+			delete(rowByExternalID, transaction.ExternalID)
+			if (settings.SyncMode == entity.SyncModeReconcile || transaction.Refunded) && ok &&
+				transactionChanged(stored.transaction, transaction, settings.PreservedColumns) {
+				updates = append(updates, transactionRowUpdate{rowID: stored.id, transaction: transaction})
+			}
+
 			continue
 		}
-
 		seen[id] = struct{}{}
+
+		// The parts of a split stand for one source transaction, so a transaction written whole
+		// is not written again as parts once a split rule matches it, nor the other way around.
+		if _, exists := seenSources[transaction.SourceID()]; exists {
+			continue
+		}
+
+		// Rows written before external IDs were stored only match by legacy ID. Each of them
+		// stands for one transaction, so identical transactions beyond their count are new.
+		if legacyID := transaction.LegacyID(); transaction.ExternalID != "" && unmatchedLegacyRows[legacyID] > 0 {
+			unmatchedLegacyRows[legacyID]--
+
+			continue
+		}
+
 		newTransactions = append(newTransactions, transaction)
 	}
 
-	return newTransactions, nil
+	return newTransactions, updates
 }
 
+// transactionChanged reports whether the source data of a stored transaction differs from the
+// incoming one, ignoring preserved columns. Category and Budget Group are classified here rather
+// than by the source, so they never count as changes. A new refund link does, so purchases written
+// before their refund arrived get linked.
+func transactionChanged(
+	stored, incoming entity.Transaction,
+	preservedColumns []entity.TransactionColumn,
+) bool {
+	compared := func(column entity.TransactionColumn) bool {
+		return !slices.Contains(preservedColumns, column)
+	}
+
+	return compared(entity.TransactionColumnName) && stored.Name != incoming.Name ||
+		compared(entity.TransactionColumnAmount) &&
+			math.Round(stored.Amount*100) != math.Round(incoming.Amount*100) ||
+		compared(entity.TransactionColumnPaymentMethod) && stored.PaymentMethod != incoming.PaymentMethod ||
+		compared(entity.TransactionColumnCardLastDigits) &&
+			cardLastDigits(stored) != cardLastDigits(incoming) ||
+		compared(entity.TransactionColumnDate) &&
+			!stored.Date.Truncate(time.Minute).Equal(incoming.Date.Truncate(time.Minute)) ||
+		incoming.RefundMatchID != "" && stored.RefundMatchID != incoming.RefundMatchID
+}
+
+func cardLastDigits(transaction entity.Transaction) string {
+	if transaction.CardLastDigits == nil {
+		return ""
+	}
+
+	return *transaction.CardLastDigits
+}
+
+// insertTransactions writes the transactions of a table in one batch and returns how many were
+// inserted. When some rows fail, the error names each failed transaction; the others stay
+// inserted.
 func (s *Ingest) insertTransactions(
 	ctx context.Context,
-	ingestProfileID, tableID string,
+	settings entity.IngestProfileSettings,
+	tableID string,
 	language entity.Language,
 	transactions []entity.Transaction,
-) error {
-	group, groupContext := errgroup.WithContext(ctx)
-	group.SetLimit(s.maxConcurrentOperations)
+) (int, error) {
+	if len(transactions) == 0 {
+		return 0, nil
+	}
 
+	rows := make([]sheet.Row, 0, len(transactions))
 	for _, transaction := range transactions {
-		group.Go(func() error {
-			if err := s.sheetProvider.InsertRow(
-				groupContext,
-				ingestProfileID,
-				tableID,
-				transactionToRow(transaction, language),
-			); err != nil {
-				return fmt.Errorf("insert transaction %q: %w", transaction.ID(), err)
-			}
+		rows = append(rows, transactionToProfileRow(transaction, language, settings))
+	}
 
-			return nil
-		})
+	failedRows := sheet.FailedRows(
+		s.sheetProvider.InsertRows(ctx, settings.ID, tableID, rows),
+		len(rows),
+	)
+	if len(failedRows) == 0 {
+		return len(rows), nil
 	}
 
-	if err := group.Wait(); err != nil {
-		return fmt.Errorf("insert transaction batch: %w", err)
+	errs := make([]error, 0, len(failedRows))
+	for _, failedRow := range failedRows {
+		if failedRow.Index < 0 || failedRow.Index >= len(transactions) {
+			errs = append(errs, failedRow)
+
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf(
+			"insert transaction %q: %w",
+			transactions[failedRow.Index].ID(),
+			failedRow.Err,
+		))
+	}
+
+	inserted := len(rows) - len(failedRows)
+
+	return inserted, fmt.Errorf(
+		"inserted %d of %d transactions: %w",
+		inserted,
+		len(rows),
+		errors.Join(errs...),
+	)
+}
+
+// updateTransactions rewrites the rows of changed transactions, leaving the preserved columns
+// untouched, and returns how many were updated. A failed update does not stop the others.
+func (s *Ingest) updateTransactions(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	tableID string,
+	language entity.Language,
+	updates []transactionRowUpdate,
+) (int, error) {
+	var errs []error
+	for _, update := range updates {
+		row := transactionToUpdatedRow(update.transaction, language, settings)
+		if err := s.sheetProvider.UpdateRow(ctx, settings.ID, tableID, update.rowID, row); err != nil {
+			errs = append(errs, fmt.Errorf("update transaction %q: %w", update.transaction.ID(), err))
+		}
+	}
+	updated := len(updates) - len(errs)
+	if len(errs) == 0 {
+		return updated, nil
+	}
+
+	return updated, fmt.Errorf(
+		"updated %d of %d transactions: %w",
+		updated,
+		len(updates),
+		errors.Join(errs...),
+	)
+}
+
+func externalIDs(transactions []entity.Transaction) map[string]struct{} {
+	ids := make(map[string]struct{}, len(transactions))
+	for _, transaction := range transactions {
+		if transaction.ExternalID != "" {
+			ids[transaction.ExternalID] = struct{}{}
+		}
+	}
+
+	return ids
+}
+
+// vanishedTransactionRows returns the rows dated within the ingest range whose external ID, or
+// the one of the transaction they were split from, the provider no longer lists. Rows without an
+// external ID cannot be told apart from renamed ones, so they are never pruned.
+func vanishedTransactionRows(
+	existing []existingTransactionRow,
+	upstreamExternalIDs map[string]struct{},
+	startDate, endDate time.Time,
+) []existingTransactionRow {
+	var vanished []existingTransactionRow
+	for _, row := range existing {
+		transaction := row.transaction
+		if transaction.ExternalID == "" || transaction.Date.Before(startDate) || transaction.Date.After(endDate) {
+			continue
+		}
+		if _, exists := upstreamExternalIDs[transaction.SourceID()]; !exists {
+			vanished = append(vanished, row)
+		}
+	}
+
+	return vanished
+}
+
+// pruneTransactions deletes the vanished rows, or only logs them in the dry-run mode, and returns
+// how many were deleted.
+func (s *Ingest) pruneTransactions(
+	ctx context.Context,
+	ingestProfileID string,
+	table sheet.Table,
+	mode PruneMode,
+	vanished []existingTransactionRow,
+) (int, error) {
+	if mode == PruneModeDryRun {
+		for _, row := range vanished {
+			slog.Info("would archive vanished transaction", vanishedTransactionAttributes(ingestProfileID, table, row)...)
+		}
+
+		return 0, nil
 	}
 
-	return nil
+	var errs []error
+	for _, row := range vanished {
+		if err := s.sheetProvider.DeleteRow(ctx, ingestProfileID, table.ID, row.id); err != nil {
+			errs = append(errs, fmt.Errorf("archive transaction %q: %w", row.transaction.ID(), err))
+
+			continue
+		}
+		slog.Info("archived vanished transaction", vanishedTransactionAttributes(ingestProfileID, table, row)...)
+	}
+	archived := len(vanished) - len(errs)
+	if len(errs) == 0 {
+		return archived, nil
+	}
+
+	return archived, fmt.Errorf(
+		"archived %d of %d vanished transactions: %w",
+		archived,
+		len(vanished),
+		errors.Join(errs...),
+	)
+}
+
+func vanishedTransactionAttributes(
+	ingestProfileID string,
+	table sheet.Table,
+	row existingTransactionRow,
+) []any {
+	return []any{
+		"ingest_profile", ingestProfileID,
+		"table", table.Title,
+		"external_id", row.transaction.ExternalID,
+		"name", row.transaction.Name,
+		"amount", row.transaction.Amount,
+		"date", row.transaction.Date,
+	}
 }
 
 var _ IngestExecutor = (*Ingest)(nil)
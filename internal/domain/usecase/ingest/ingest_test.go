@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -18,6 +21,8 @@ import (
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/companyapi/mockcompanyapi"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/mockgpt"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore/mockkvstore"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/openfinance/mockopenfinance"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/mocksheet"
@@ -32,6 +37,7 @@ const testMaxConcurrentOperations = 4
 
 type categorizationInput struct {
 	TransactionNames []string                   `json:"transaction_names"`
+	SourceCategories map[string]string          `json:"source_categories"`
 	Categories       []entity.Category          `json:"categories"`
 	Mappings         map[string]entity.Category `json:"examples"`
 	Fallback         entity.Category            `json:"fallback"`
@@ -75,8 +81,9 @@ func testIngestProfileSettings(ingestProfileID string) entity.IngestProfileSetti
 			"Food":                         entity.Red,
 			entity.DefaultFallbackCategory: entity.Gray,
 		},
-		Mappings: map[string]entity.Category{"Market": "Food"},
-		Fallback: entity.DefaultFallbackCategory,
+		Mappings:                map[string]entity.Category{"Market": "Food"},
+		CategorizationChunkSize: entity.DefaultCategorizationChunkSize,
+		Fallback:                entity.DefaultFallbackCategory,
 	}
 }
 
@@ -109,6 +116,32 @@ func noCompanyLookup(t *testing.T) *mockcompanyapi.MockCompanyAPI {
 	return mockcompanyapi.NewMockCompanyAPI(t)
 }
 
+// newCategorizingIngest returns a use case with only what categorization needs.
+func newCategorizingIngest(gptProvider gpt.Provider, categorizationCache kvstore.Provider) *Ingest {
+	return &Ingest{
+		maxConcurrentOperations: testMaxConcurrentOperations,
+		gptProvider:             gptProvider,
+		categorizationCache:     categorizationCache,
+	}
+}
+
+// emptyCategorizationCache misses every lookup and accepts every write.
+func emptyCategorizationCache(t *testing.T) *mockkvstore.MockKVStore {
+	t.Helper()
+
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().Get(mock.Anything, mock.Anything).Return(map[string]string{}, nil).Maybe()
+	cache.EXPECT().Set(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return cache
+}
+
+func externalIDColumn(name string) any {
+	return mock.MatchedBy(func(columns []sheet.Column) bool {
+		return len(columns) == 1 && columns[0].Definition().Name() == name && columns[0].Definition().Hidden()
+	})
+}
+
 func TestIngestInputValidation(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -128,6 +161,12 @@ func TestIngestInputValidation(t *testing.T) {
 			name:  "start date before end date",
 			input: IngestInput{StartDate: now, EndDate: now.Add(time.Second)},
 		},
+		{name: "archive prune", input: IngestInput{StartDate: now, EndDate: now, Prune: PruneModeArchive}},
+		{
+			name:    "unknown prune mode",
+			input:   IngestInput{StartDate: now, EndDate: now, Prune: "delete"},
+			wantErr: true,
+		},
 	}
 	val := validator.NewValidator()
 
@@ -148,62 +187,150 @@ func TestIngestExecuteRejectsInvalidInputBeforeCallingProviders(t *testing.T) {
 		testSettings("ingest-profile"),
 		noCompanyLookup(t),
 		mockgpt.NewMockGPT(t),
+		emptyCategorizationCache(t),
 		mocksheet.NewMockSheet(t),
 		mockopenfinance.NewMockOpenFinance(t),
 	)
 
-	err := ingestUseCase.Execute(t.Context(), IngestInput{})
+	_, err := ingestUseCase.Execute(t.Context(), IngestInput{})
 	if err == nil || !strings.Contains(err.Error(), "invalid ingest input") {
 		t.Fatalf("Execute() error = %v, want invalid ingest input", err)
 	}
 }
 
-func TestCategorizeTransactionsRejectsInvalidCompletion(t *testing.T) {
+func TestCategorizeTransactionsRetriesUndecodableCompletion(t *testing.T) {
 	tests := []struct {
-		name    string
-		content string
+		name     string
+		settings entity.IngestProfileSettings
+		contents []string
+		want     transactionClassifications
 	}{
-		{name: "empty"},
-		{name: "malformed", content: "not JSON"},
+		{
+			name:     "empty then valid",
+			settings: testIngestProfileSettings("ingest-profile"),
+			contents: []string{"", `{"Store":"Food"}`},
+			want:     transactionClassifications{Category: "Food"},
+		},
+		{
+			name:     "always malformed",
+			settings: testIngestProfileSettings("ingest-profile"),
+			contents: slices.Repeat([]string{"not JSON"}, categorizationAttempts),
+			want:     transactionClassifications{Category: entity.DefaultFallbackCategory},
+		},
+		{
+			name:     "always malformed with budget groups",
+			settings: testBudgetGroupProfileSettings("ingest-profile"),
+			contents: slices.Repeat([]string{"not JSON"}, categorizationAttempts),
+			want:     transactionClassifications{Category: entity.DefaultFallbackCategory, BudgetGroup: "Other"},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			categorizer := mockgpt.NewMockGPT(t)
-			categorizer.EXPECT().
-				CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
-				Return(test.content, nil).
-				Once()
-
-			ingestUseCase := &Ingest{
-				settings:    testSettings(),
-				gptProvider: categorizer,
+			for _, content := range test.contents {
+				categorizer.EXPECT().
+					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(content, nil).
+					Once()
 			}
-			err := ingestUseCase.categorizeTransactions(
+
+			transactions := []entity.Transaction{{Name: "Store"}}
+			if _, err := newCategorizingIngest(categorizer, emptyCategorizationCache(t)).categorizeTransactions(
 				t.Context(),
-				testIngestProfileSettings("ingest-profile"),
-				[]entity.Transaction{{Name: "Store"}},
-			)
-			if err == nil {
-				t.Fatal("categorizeTransactions() error = nil")
+				test.settings,
+				transactions,
+			); err != nil {
+				t.Fatalf("categorizeTransactions() error = %v", err)
+			}
+
+			got := transactionClassifications{Category: transactions[0].Category, BudgetGroup: transactions[0].BudgetGroup}
+			if got != test.want {
+				t.Fatalf("classifications = %#v, want %#v", got, test.want)
 			}
 		})
 	}
 }
 
+func TestCategorizeTransactionsSplitsNamesIntoChunks(t *testing.T) {
+	var mutex sync.Mutex
+	var requested [][]string
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, message string, _ ...gpt.ChatCompletionOption) (string, error) {
+			var input categorizationInput
+			if err := json.Unmarshal([]byte(message), &input); err != nil {
+				t.Errorf("categorization input = %q: %v", message, err)
+			}
+
+			mutex.Lock()
+			requested = append(requested, input.TransactionNames)
+			mutex.Unlock()
+
+			// The chunk holding Broken never decodes, so only its names fall back.
+			if slices.Contains(input.TransactionNames, "Broken") {
+				return "not JSON", nil
+			}
+
+			answers := make(map[string]entity.Category, len(input.TransactionNames))
+			for _, name := range input.TransactionNames {
+				answers[name] = "Food"
+			}
+			content, err := json.Marshal(answers)
+
+			return string(content), err
+		}).
+		Times(2 + categorizationAttempts)
+
+	settings := testIngestProfileSettings("ingest-profile")
+	settings.CategorizationChunkSize = 2
+	transactions := []entity.Transaction{
+		{Name: "A"},
+		{Name: "B"},
+		{Name: "C"},
+		{Name: "D"},
+		{Name: "Broken"},
+		{Name: "E"},
+	}
+	if _, err := newCategorizingIngest(categorizer, emptyCategorizationCache(t)).categorizeTransactions(
+		t.Context(),
+		settings,
+		transactions,
+	); err != nil {
+		t.Fatalf("categorizeTransactions() error = %v", err)
+	}
+
+	for _, names := range requested {
+		if len(names) != 2 {
+			t.Fatalf("requested names = %v, want chunks of 2", requested)
+		}
+	}
+	wantCategories := []entity.Category{
+		"Food",
+		"Food",
+		"Food",
+		"Food",
+		entity.DefaultFallbackCategory,
+		entity.DefaultFallbackCategory,
+	}
+	for index, transaction := range transactions {
+		if transaction.Category != wantCategories[index] {
+			t.Fatalf("transaction %d = %#v, want category %q", index, transaction, wantCategories[index])
+		}
+	}
+}
+
 func TestCategorizeTransactionsUsesFallbackForMissingAndInvalidCategories(t *testing.T) {
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(`{"Invalid":"not-configured"}`, nil).
 		Once()
 
 	transactions := []entity.Transaction{{Name: "Missing"}, {Name: "Invalid"}}
-	ingestUseCase := &Ingest{
-		settings:    testSettings(),
-		gptProvider: categorizer,
-	}
-	if err := ingestUseCase.categorizeTransactions(
+	ingestUseCase := newCategorizingIngest(categorizer, emptyCategorizationCache(t))
+	if _, err := ingestUseCase.categorizeTransactions(
 		t.Context(),
 		testIngestProfileSettings("ingest-profile"),
 		transactions,
@@ -221,9 +348,10 @@ func TestCategorizeTransactionsUsesFallbackForMissingAndInvalidCategories(t *tes
 func TestCategorizeTransactionsClassifiesBudgetGroupsInSameRequest(t *testing.T) {
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		RunAndReturn(func(
 			_ context.Context,
+			_ string,
 			message string,
 			options ...gpt.ChatCompletionOption,
 		) (string, error) {
@@ -241,9 +369,17 @@ func TestCategorizeTransactionsClassifiesBudgetGroupsInSameRequest(t *testing.T)
 
 			completionOptions := applyChatCompletionOptions(options)
 			if completionOptions.SystemMessage != combinedCategorizationSystemMessage ||
-				!completionOptions.JSONResponse {
+				completionOptions.JSONSchema == nil ||
+				completionOptions.JSONSchema.Name != "transaction_classifications" {
 				t.Fatalf("completion options = %#v", completionOptions)
 			}
+			schema := completionOptions.JSONSchema.Schema
+			storeSchema, _ := schema["properties"].(map[string]any)["Store"].(map[string]any)
+			budgetGroupSchema, _ := storeSchema["properties"].(map[string]any)["budget_group"].(map[string]any)
+			if !reflect.DeepEqual(schema["required"], input.TransactionNames) ||
+				!reflect.DeepEqual(budgetGroupSchema["enum"], input.BudgetGroups) {
+				t.Fatalf("JSON schema = %#v", schema)
+			}
 
 			return `{
 				"Store":{"category":"Food","budget_group":"Lifestyle"},
@@ -261,7 +397,7 @@ func TestCategorizeTransactionsClassifiesBudgetGroupsInSameRequest(t *testing.T)
 		{Name: "Missing"},
 	}
 	settings := testBudgetGroupProfileSettings("ingest-profile")
-	if err := (&Ingest{gptProvider: categorizer}).categorizeTransactions(
+	if _, err := newCategorizingIngest(categorizer, emptyCategorizationCache(t)).categorizeTransactions(
 		t.Context(),
 		settings,
 		transactions,
@@ -282,333 +418,1584 @@ func TestCategorizeTransactionsClassifiesBudgetGroupsInSameRequest(t *testing.T)
 	}
 }
 
-func TestCategorizeTransactionsRejectsMalformedCombinedCompletion(t *testing.T) {
+func TestCategorizeTransactionsAppliesRulesBeforeGPT(t *testing.T) {
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
-		Return("not JSON", nil).
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, message string, _ ...gpt.ChatCompletionOption) (string, error) {
+			var input combinedCategorizationInput
+			if err := json.Unmarshal([]byte(message), &input); err != nil {
+				t.Fatalf("combined categorization input = %q: %v", message, err)
+			}
+			if !slices.Equal(input.TransactionNames, []string{"Gym", "Store"}) {
+				t.Fatalf("transaction names = %v, want only the names rules left incomplete", input.TransactionNames)
+			}
+
+			return `{
+				"Gym":{"category":"Food","budget_group":"Other"},
+				"Store":{"category":"Food","budget_group":"Lifestyle"}
+			}`, nil
+		}).
 		Once()
 
-	err := (&Ingest{gptProvider: categorizer}).categorizeTransactions(
+	settings := testBudgetGroupProfileSettings("ingest-profile")
+	settings.CategoryRules = []entity.CategoryRule{
+		{Name: "UBER", NameMatch: entity.NameMatchContains, Category: "Food", BudgetGroup: "Fixed Costs"},
+		{Name: "Gym", BudgetGroup: "Lifestyle"},
+		{SourceCategories: []string{"Groceries"}, MinAmount: new(10.0), Category: entity.DefaultFallbackCategory},
+	}
+	transactions := []entity.Transaction{
+		{Name: "UBER *TRIP"},
+		{Name: "Gym"},
+		{Name: "Store", Amount: 20, SourceCategory: "Groceries"},
+	}
+	sources, err := newCategorizingIngest(categorizer, emptyCategorizationCache(t)).categorizeTransactions(
 		t.Context(),
-		testBudgetGroupProfileSettings("ingest-profile"),
-		[]entity.Transaction{{Name: "Store"}},
+		settings,
+		transactions,
 	)
-	if err == nil {
-		t.Fatal("categorizeTransactions() error = nil")
+	if err != nil {
+		t.Fatalf("categorizeTransactions() error = %v", err)
 	}
-}
-
-func TestIngestUsesIngestProfileSpecificCategorizationSettings(t *testing.T) {
-	date := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
-	settings := entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{
-		{
-			ID:               "first-ingest-profile",
-			Categories:       []entity.Category{"Alpha", "Others"},
-			ColorsByCategory: map[entity.Category]entity.Color{"Alpha": entity.Red, "Others": entity.Gray},
-			Mappings:         map[string]entity.Category{"First example": "Alpha"},
-			Fallback:         "Others",
-		},
-		{
-			ID:               "second-ingest-profile",
-			Categories:       []entity.Category{"Beta", "Outros"},
-			ColorsByCategory: map[entity.Category]entity.Color{"Beta": entity.Blue, "Outros": entity.Purple},
-			Mappings:         map[string]entity.Category{"Second example": "Beta"},
-			Fallback:         "Outros",
-		},
-	}}
 
-	source := mockopenfinance.NewMockOpenFinance(t)
-	source.EXPECT().
-		ListTransactionsByIngestProfileID(mock.Anything, mock.Anything, date, date).
-		RunAndReturn(func(_ context.Context, ingestProfileID string, _, _ time.Time) ([]entity.Transaction, error) {
-			return []entity.Transaction{{Name: ingestProfileID + " transaction", Amount: 10, Date: date}}, nil
-		}).
-		Twice()
+	want := []transactionClassifications{
+		{Category: "Food", BudgetGroup: "Fixed Costs"},
+		{Category: "Food", BudgetGroup: "Lifestyle"},
+		{Category: entity.DefaultFallbackCategory, BudgetGroup: "Lifestyle"},
+	}
+	for index, transaction := range transactions {
+		if transaction.Category != want[index].Category || transaction.BudgetGroup != want[index].BudgetGroup {
+			t.Fatalf("transaction %d = %#v, want %#v", index, transaction, want[index])
+		}
+	}
+	wantSources := []CategorySource{CategorySourceRule, CategorySourceGPT, CategorySourceRule}
+	if !slices.Equal(sources, wantSources) {
+		t.Fatalf("sources = %v, want %v", sources, wantSources)
+	}
+}
 
-	inputs := make(map[string]categorizationInput, 2)
-	var inputsMutex sync.Mutex
+func TestCategorizeTransactionsMapsSourceCategoriesBeforeGPT(t *testing.T) {
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
-		RunAndReturn(func(
-			_ context.Context,
-			message string,
-			_ ...gpt.ChatCompletionOption,
-		) (string, error) {
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, message string, _ ...gpt.ChatCompletionOption) (string, error) {
 			var input categorizationInput
 			if err := json.Unmarshal([]byte(message), &input); err != nil {
-				return "", fmt.Errorf("decode categorization input: %w", err)
+				t.Fatalf("categorization input = %q: %v", message, err)
 			}
-			if len(input.TransactionNames) != 1 {
-				return "", fmt.Errorf("transaction names = %#v", input.TransactionNames)
+			if !slices.Equal(input.TransactionNames, []string{"Drugstore", "Store"}) {
+				t.Fatalf("transaction names = %v, want only the unmapped names", input.TransactionNames)
 			}
-
-			name := input.TransactionNames[0]
-			inputsMutex.Lock()
-			inputs[name] = input
-			inputsMutex.Unlock()
-
-			if name == "first-ingest-profile transaction" {
-				return `{"first-ingest-profile transaction":"Beta"}`, nil
+			if !reflect.DeepEqual(input.SourceCategories, map[string]string{"Drugstore": "Pharmacy"}) {
+				t.Fatalf("source categories = %v, want the hint of the unmapped name", input.SourceCategories)
 			}
 
-			return `{"second-ingest-profile transaction":"Alpha"}`, nil
+			return `{"Drugstore":"Food","Store":"Others"}`, nil
 		}).
-		Twice()
+		Once()
 
-	store := mocksheet.NewMockSheet(t)
-	store.EXPECT().ListTables(mock.Anything, mock.Anything).Return(nil, nil).Twice()
-	store.EXPECT().
-		CreateTable(mock.Anything, mock.Anything, mock.Anything).
-		RunAndReturn(func(
-			_ context.Context,
-			ingestProfileID string,
-			definition sheet.TableDefinition,
-		) (sheet.Table, error) {
-			return sheet.Table{ID: ingestProfileID + "-table", Title: definition.Title()}, nil
-		}).
-		Twice()
+	settings := testIngestProfileSettings("ingest-profile")
+	settings.SourceCategoryMappings = map[string]entity.Category{"Groceries": "Food"}
+	settings.CategoryRules = []entity.CategoryRule{{Name: "Gym", Category: entity.DefaultFallbackCategory}}
+	transactions := []entity.Transaction{
+		{Name: "Supermarket", SourceCategory: "Groceries"},
+		{Name: "Gym", SourceCategory: "Groceries"},
+		{Name: "Drugstore", SourceCategory: "Pharmacy"},
+		{Name: "Store"},
+	}
+	sources, err := newCategorizingIngest(categorizer, emptyCategorizationCache(t)).categorizeTransactions(
+		t.Context(),
+		settings,
+		transactions,
+	)
+	if err != nil {
+		t.Fatalf("categorizeTransactions() error = %v", err)
+	}
 
-	insertedCategories := make(map[string]entity.Category, 2)
-	var insertedMutex sync.Mutex
-	store.EXPECT().
-		InsertRow(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Run(func(_ context.Context, ingestProfileID, _ string, row sheet.Row) {
-			transaction, err := rowToTransaction(row, entity.LanguageEnglish)
-			if err != nil {
-				t.Errorf("rowToTransaction() error = %v", err)
+	wantCategories := []entity.Category{"Food", entity.DefaultFallbackCategory, "Food", entity.DefaultFallbackCategory}
+	for index, transaction := range transactions {
+		if transaction.Category != wantCategories[index] {
+			t.Fatalf("transaction %d = %#v, want category %q", index, transaction, wantCategories[index])
+		}
+	}
+	wantSources := []CategorySource{
+		CategorySourceSourceCategory,
+		CategorySourceRule,
+		CategorySourceGPT,
+		CategorySourceFallback,
+	}
+	if !slices.Equal(sources, wantSources) {
+		t.Fatalf("sources = %v, want %v", sources, wantSources)
+	}
+}
 
-				return
-			}
-			insertedMutex.Lock()
-			insertedCategories[ingestProfileID] = transaction.Category
-			insertedMutex.Unlock()
+func TestCategorizeTransactionsUsesCategorizationCache(t *testing.T) {
+	settings := testIngestProfileSettings("ingest-profile")
+	staleSettings := settings
+	staleSettings.Categories = []entity.Category{"Food"}
+	cachedEntry := func(settings entity.IngestProfileSettings, category entity.Category, learned bool) string {
+		value, err := json.Marshal(cachedClassification{
+			Fingerprint: classificationsFingerprint(settings),
+			Category:    category,
+			Learned:     learned,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(value)
+	}
+
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().
+		Get(mock.Anything, []string{
+			"ingest-profile|store",
+			"ingest-profile|fixed",
+			"ingest-profile|old",
+			"ingest-profile|new",
 		}).
+		Return(map[string]string{
+			"ingest-profile|store": cachedEntry(settings, "Food", false),
+			"ingest-profile|fixed": cachedEntry(staleSettings, "Food", true),
+			"ingest-profile|old":   cachedEntry(staleSettings, "Food", false),
+		}, nil).
+		Once()
+	cache.EXPECT().
+		Set(mock.Anything, map[string]string{"ingest-profile|old": cachedEntry(settings, "Food", false)}).
 		Return(nil).
-		Twice()
+		Once()
 
-	err := NewIngest(
-		validator.NewValidator(),
-		testMaxConcurrentOperations,
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, message string, _ ...gpt.ChatCompletionOption) (string, error) {
+			var input categorizationInput
+			if err := json.Unmarshal([]byte(message), &input); err != nil {
+				t.Fatalf("categorization input = %q: %v", message, err)
+			}
+			if !slices.Equal(input.TransactionNames, []string{"Old", "New"}) {
+				t.Fatalf("transaction names = %v, want only uncached names", input.TransactionNames)
+			}
+
+			return `{"Old":"Food","New":"not-configured"}`, nil
+		}).
+		Once()
+
+	transactions := []entity.Transaction{{Name: "  STORE "}, {Name: "Fixed"}, {Name: "Old"}, {Name: "New"}}
+	sources, err := newCategorizingIngest(categorizer, cache).categorizeTransactions(
+		t.Context(),
 		settings,
-		noCompanyLookup(t),
-		categorizer,
-		store,
-		source,
-	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date})
+		transactions,
+	)
 	if err != nil {
-		t.Fatalf("Execute() error = %v", err)
+		t.Fatalf("categorizeTransactions() error = %v", err)
 	}
 
-	firstInput := inputs["first-ingest-profile transaction"]
-	if len(firstInput.Categories) != 2 || firstInput.Categories[0] != "Alpha" ||
-		firstInput.Mappings["First example"] != "Alpha" || firstInput.Fallback != "Others" {
-		t.Fatalf("first categorization input = %#v", firstInput)
+	wantCategories := []entity.Category{"Food", "Food", "Food", entity.DefaultFallbackCategory}
+	for index, transaction := range transactions {
+		if transaction.Category != wantCategories[index] {
+			t.Fatalf("transaction %d = %#v, want category %q", index, transaction, wantCategories[index])
+		}
+		if wantReview := index == 3; transaction.NeedsReview != wantReview {
+			t.Fatalf("transaction %d needs review = %t, want %t", index, transaction.NeedsReview, wantReview)
+		}
 	}
-	secondInput := inputs["second-ingest-profile transaction"]
-	if len(secondInput.Categories) != 2 || secondInput.Categories[0] != "Beta" ||
-		secondInput.Mappings["Second example"] != "Beta" || secondInput.Fallback != "Outros" {
-		t.Fatalf("second categorization input = %#v", secondInput)
+	wantSources := []CategorySource{
+		CategorySourceCache,
+		CategorySourceCorrection,
+		CategorySourceGPT,
+		CategorySourceFallback,
 	}
-	if insertedCategories["first-ingest-profile"] != "Others" ||
-		insertedCategories["second-ingest-profile"] != "Outros" {
-		t.Fatalf("inserted categories = %#v", insertedCategories)
+	if !slices.Equal(sources, wantSources) {
+		t.Fatalf("sources = %v, want %v", sources, wantSources)
 	}
 }
 
-func TestIngestProcessesEveryMonthAndDeduplicates(t *testing.T) {
-	janDate := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
-	febDate := time.Date(2026, time.February, 10, 12, 0, 0, 0, time.UTC)
-	existing := entity.Transaction{Name: "Market", Amount: 10, Date: janDate}
-	newJanuary := entity.Transaction{Name: "Cafe", Amount: 20, Date: janDate}
-	newFebruary := entity.Transaction{Name: "Unknown", Amount: 30, Date: febDate}
-
-	source := mockopenfinance.NewMockOpenFinance(t)
-	source.EXPECT().
-		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", janDate, febDate).
-		Return([]entity.Transaction{existing, existing, newJanuary, newFebruary}, nil).
+func TestCategorizeTransactionsSharesClassificationsAcrossInstallments(t *testing.T) {
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().
+		Get(mock.Anything, []string{"ingest-profile|store", "ingest-profile|market"}).
+		Return(map[string]string{}, nil).
 		Once()
+	cache.EXPECT().Set(mock.Anything, mock.Anything).Return(nil).Once()
 
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
-		RunAndReturn(func(
-			_ context.Context,
-			message string,
-			options ...gpt.ChatCompletionOption,
-		) (string, error) {
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, message string, _ ...gpt.ChatCompletionOption) (string, error) {
 			var input categorizationInput
 			if err := json.Unmarshal([]byte(message), &input); err != nil {
 				t.Fatalf("categorization input = %q: %v", message, err)
 			}
-			if len(input.TransactionNames) != 3 {
-				t.Fatalf("unique names = %#v", input.TransactionNames)
-			}
-			if len(input.Categories) != 2 || input.Categories[0] != "Food" {
-				t.Fatalf("categories = %#v", input.Categories)
-			}
-			if input.Mappings["Market"] != "Food" || input.Fallback != entity.DefaultFallbackCategory {
-				t.Fatalf("categorization input = %#v", input)
-			}
-
-			completionOptions := applyChatCompletionOptions(options)
-			if completionOptions.SystemMessage != categorizationSystemMessage {
-				t.Fatalf("system message = %q", completionOptions.SystemMessage)
-			}
-			if !completionOptions.JSONResponse {
-				t.Fatal("JSON response option is disabled")
+			if !slices.Equal(input.TransactionNames, []string{"Store", "Market"}) {
+				t.Fatalf("transaction names = %v, want names without installment markers", input.TransactionNames)
 			}
 
-			return `{"Market":"Food","Cafe":"Food","Unknown":"not-configured"}`, nil
+			return `{"Store":"Food","Market":"Food"}`, nil
 		}).
 		Once()
 
+	settings := testIngestProfileSettings("ingest-profile")
+	settings.CategoryRules = []entity.CategoryRule{{Name: "Gym", Category: entity.DefaultFallbackCategory}}
+	transactions := []entity.Transaction{
+		{Name: "Store PARC 01/03"},
+		{Name: "Store PARC 02/03"},
+		{Name: "Market"},
+		{Name: "Gym PARC 03/12"},
+	}
+	sources, err := newCategorizingIngest(categorizer, cache).categorizeTransactions(
+		t.Context(),
+		settings,
+		transactions,
+	)
+	if err != nil {
+		t.Fatalf("categorizeTransactions() error = %v", err)
+	}
+
+	wantCategories := []entity.Category{"Food", "Food", "Food", entity.DefaultFallbackCategory}
+	for index, transaction := range transactions {
+		if transaction.Category != wantCategories[index] {
+			t.Fatalf("transaction %d = %#v, want category %q", index, transaction, wantCategories[index])
+		}
+	}
+	if sources[3] != CategorySourceRule {
+		t.Fatalf("sources = %v, want the installment categorized by its rule", sources)
+	}
+}
+
+func TestCategorizeTransactionsSeparatesIncomeFromExpenses(t *testing.T) {
+	settings := testIngestProfileSettings("ingest-profile")
+	settings.IncludeCredits = true
+	settings.IncomeCategories = []entity.Category{"Other income", "Salary"}
+	settings.IncomeFallback = "Other income"
+	settings.ColorsByCategory["Other income"] = entity.Gray
+	settings.ColorsByCategory["Salary"] = entity.Green
+	settings.Mappings = map[string]entity.Category{"Market": "Food", "ACME": "Salary"}
+
+	var cachedKeys []string
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().Get(mock.Anything, mock.Anything).Return(map[string]string{}, nil).Times(2)
+	cache.EXPECT().
+		Set(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, values map[string]string) {
+			cachedKeys = slices.AppendSeq(cachedKeys, maps.Keys(values))
+		}).
+		Return(nil).
+		Times(2)
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, message string, _ ...gpt.ChatCompletionOption) (string, error) {
+			var input categorizationInput
+			if err := json.Unmarshal([]byte(message), &input); err != nil {
+				t.Fatalf("categorization input = %q: %v", message, err)
+			}
+
+			if slices.Equal(input.Categories, settings.IncomeCategories) {
+				if !slices.Equal(input.TransactionNames, []string{"PIX John", "Bonus"}) ||
+					!maps.Equal(input.Mappings, map[string]entity.Category{"ACME": "Salary"}) ||
+					input.Fallback != "Other income" {
+					t.Fatalf("income categorization input = %#v", input)
+				}
+
+				return `{"PIX John":"Salary","Bonus":"Food"}`, nil
+			}
+
+			if !slices.Equal(input.Categories, settings.Categories) ||
+				!slices.Equal(input.TransactionNames, []string{"PIX John"}) ||
+				!maps.Equal(input.Mappings, map[string]entity.Category{"Market": "Food"}) ||
+				input.Fallback != entity.DefaultFallbackCategory {
+				t.Fatalf("expense categorization input = %#v", input)
+			}
+
+			return `{"PIX John":"Food"}`, nil
+		}).
+		Times(2)
+
+	transactions := []entity.Transaction{
+		{Name: "PIX John", Direction: entity.TransactionDirectionCredit},
+		{Name: "PIX John", Direction: entity.TransactionDirectionDebit},
+		{Name: "Bonus", Direction: entity.TransactionDirectionCredit},
+	}
+	sources, err := newCategorizingIngest(categorizer, cache).categorizeTransactions(
+		t.Context(),
+		settings,
+		transactions,
+	)
+	if err != nil {
+		t.Fatalf("categorizeTransactions() error = %v", err)
+	}
+
+	wantCategories := []entity.Category{"Salary", "Food", "Other income"}
+	for index, transaction := range transactions {
+		if transaction.Category != wantCategories[index] {
+			t.Fatalf("transaction %d = %#v, want category %q", index, transaction, wantCategories[index])
+		}
+	}
+	wantSources := []CategorySource{CategorySourceGPT, CategorySourceGPT, CategorySourceFallback}
+	if !slices.Equal(sources, wantSources) {
+		t.Fatalf("sources = %v, want %v", sources, wantSources)
+	}
+	slices.Sort(cachedKeys)
+	if want := []string{"ingest-profile|credit|pix john", "ingest-profile|pix john"}; !slices.Equal(cachedKeys, want) {
+		t.Fatalf("cached keys = %v, want %v", cachedKeys, want)
+	}
+}
+
+func TestCategorizeTransactionsClassifiesHeuristicallyWithoutGPT(t *testing.T) {
+	settings := heuristicProfileSettings()
+	settings.CategoryRules = []entity.CategoryRule{{Name: "Gym", BudgetGroup: "Lifestyle"}}
+	learned, err := json.Marshal(cachedClassification{Category: "Health", BudgetGroup: "Lifestyle", Learned: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cache is read for corrections, but heuristic answers are never written back.
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().
+		Get(mock.Anything, mock.Anything).
+		Return(map[string]string{"ingest-profile|corrected": string(learned)}, nil).
+		Once()
+
+	transactions := []entity.Transaction{
+		{Name: "Corrected"},
+		{Name: "iFood *Restaurant"},
+		{Name: "Gym", SourceCategory: "Pharmacy"},
+		{Name: "Unknown"},
+	}
+	sources, err := newCategorizingIngest(mockgpt.NewMockGPT(t), cache).categorizeTransactions(
+		t.Context(),
+		settings,
+		transactions,
+	)
+	if err != nil {
+		t.Fatalf("categorizeTransactions() error = %v", err)
+	}
+
+	want := []entity.Transaction{
+		{Name: "Corrected", Category: "Health", BudgetGroup: "Lifestyle"},
+		{Name: "iFood *Restaurant", Category: "Food", BudgetGroup: "Fixed Costs", NeedsReview: true},
+		{Name: "Gym", SourceCategory: "Pharmacy", Category: "Health", BudgetGroup: "Lifestyle"},
+		{Name: "Unknown", Category: entity.DefaultFallbackCategory, BudgetGroup: "Other", NeedsReview: true},
+	}
+	if !reflect.DeepEqual(transactions, want) {
+		t.Fatalf("transactions = %#v, want %#v", transactions, want)
+	}
+	wantSources := []CategorySource{
+		CategorySourceCorrection,
+		CategorySourceHeuristic,
+		CategorySourceSourceCategory,
+		CategorySourceFallback,
+	}
+	if !slices.Equal(sources, wantSources) {
+		t.Fatalf("sources = %v, want %v", sources, wantSources)
+	}
+}
+
+func TestCategorySourceConfidence(t *testing.T) {
+	tests := map[CategorySource]CategoryConfidence{
+		CategorySourceSplit:          CategoryConfidenceHigh,
+		CategorySourceRule:           CategoryConfidenceHigh,
+		CategorySourceSourceCategory: CategoryConfidenceHigh,
+		CategorySourceCorrection:     CategoryConfidenceHigh,
+		CategorySourceMapping:        CategoryConfidenceHigh,
+		CategorySourceCache:          CategoryConfidenceMedium,
+		CategorySourceGPT:            CategoryConfidenceMedium,
+		CategorySourceHeuristic:      CategoryConfidenceLow,
+		CategorySourceFallback:       CategoryConfidenceLow,
+	}
+
+	for source, want := range tests {
+		if got := source.Confidence(); got != want {
+			t.Errorf("%s confidence = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func TestCategorizeTransactionsIgnoresCategorizationCacheFailures(t *testing.T) {
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("disk full")).Once()
+	cache.EXPECT().Set(mock.Anything, mock.Anything).Return(errors.New("disk full")).Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"Store":"Food"}`, nil).
+		Once()
+
+	transactions := []entity.Transaction{{Name: "Store"}}
+	if _, err := newCategorizingIngest(categorizer, cache).categorizeTransactions(
+		t.Context(),
+		testIngestProfileSettings("ingest-profile"),
+		transactions,
+	); err != nil {
+		t.Fatalf("categorizeTransactions() error = %v", err)
+	}
+	if transactions[0].Category != "Food" {
+		t.Fatalf("transaction = %#v", transactions[0])
+	}
+}
+
+func TestIngestUsesIngestProfileSpecificCategorizationSettings(t *testing.T) {
+	date := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	settings := entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{
+		{
+			ID:                      "first-ingest-profile",
+			Categories:              []entity.Category{"Alpha", "Others"},
+			ColorsByCategory:        map[entity.Category]entity.Color{"Alpha": entity.Red, "Others": entity.Gray},
+			Mappings:                map[string]entity.Category{"First example": "Alpha"},
+			Fallback:                "Others",
+			CategorizationChunkSize: entity.DefaultCategorizationChunkSize,
+		},
+		{
+			ID:                      "second-ingest-profile",
+			Categories:              []entity.Category{"Beta", "Outros"},
+			ColorsByCategory:        map[entity.Category]entity.Color{"Beta": entity.Blue, "Outros": entity.Purple},
+			Mappings:                map[string]entity.Category{"Second example": "Beta"},
+			Fallback:                "Outros",
+			CategorizationChunkSize: entity.DefaultCategorizationChunkSize,
+		},
+	}}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, mock.Anything, date, date).
+		RunAndReturn(func(_ context.Context, ingestProfileID string, _, _ time.Time) ([]entity.Transaction, error) {
+			return []entity.Transaction{{Name: ingestProfileID + " transaction", Amount: 10, Date: date}}, nil
+		}).
+		Twice()
+
+	inputs := make(map[string]categorizationInput, 2)
+	var inputsMutex sync.Mutex
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			ingestProfileID string,
+			message string,
+			_ ...gpt.ChatCompletionOption,
+		) (string, error) {
+			var input categorizationInput
+			if err := json.Unmarshal([]byte(message), &input); err != nil {
+				return "", fmt.Errorf("decode categorization input: %w", err)
+			}
+			if len(input.TransactionNames) != 1 {
+				return "", fmt.Errorf("transaction names = %#v", input.TransactionNames)
+			}
+
+			name := input.TransactionNames[0]
+			if name != ingestProfileID+" transaction" {
+				return "", fmt.Errorf("connection ID = %q for %q", ingestProfileID, name)
+			}
+			inputsMutex.Lock()
+			inputs[name] = input
+			inputsMutex.Unlock()
+
+			if name == "first-ingest-profile transaction" {
+				return `{"first-ingest-profile transaction":"Beta"}`, nil
+			}
+
+			return `{"second-ingest-profile transaction":"Alpha"}`, nil
+		}).
+		Twice()
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().ListTables(mock.Anything, mock.Anything).Return(nil, nil).Twice()
+	store.EXPECT().
+		CreateTable(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			ingestProfileID string,
+			definition sheet.TableDefinition,
+		) (sheet.Table, error) {
+			return sheet.Table{ID: ingestProfileID + "-table", Title: definition.Title()}, nil
+		}).
+		Twice()
+
+	insertedCategories := make(map[string]entity.Category, 2)
+	var insertedMutex sync.Mutex
+	store.EXPECT().
+		InsertRows(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(_ context.Context, ingestProfileID, _ string, rows []sheet.Row) {
+			for _, row := range rows {
+				transaction, err := rowToTransaction(row, entity.LanguageEnglish)
+				if err != nil {
+					t.Errorf("rowToTransaction() error = %v", err)
+
+					return
+				}
+				insertedMutex.Lock()
+				insertedCategories[ingestProfileID] = transaction.Category
+				insertedMutex.Unlock()
+			}
+		}).
+		Return(nil).
+		Twice()
+
+	_, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		settings,
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	firstInput := inputs["first-ingest-profile transaction"]
+	if len(firstInput.Categories) != 2 || firstInput.Categories[0] != "Alpha" ||
+		firstInput.Mappings["First example"] != "Alpha" || firstInput.Fallback != "Others" {
+		t.Fatalf("first categorization input = %#v", firstInput)
+	}
+	secondInput := inputs["second-ingest-profile transaction"]
+	if len(secondInput.Categories) != 2 || secondInput.Categories[0] != "Beta" ||
+		secondInput.Mappings["Second example"] != "Beta" || secondInput.Fallback != "Outros" {
+		t.Fatalf("second categorization input = %#v", secondInput)
+	}
+	if insertedCategories["first-ingest-profile"] != "Others" ||
+		insertedCategories["second-ingest-profile"] != "Outros" {
+		t.Fatalf("inserted categories = %#v", insertedCategories)
+	}
+}
+
+func TestIngestProcessesEveryMonthAndDeduplicates(t *testing.T) {
+	janDate := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+	febDate := time.Date(2026, time.February, 10, 12, 0, 0, 0, time.UTC)
+	existing := entity.Transaction{Name: "Market", Amount: 10, Date: janDate}
+	newJanuary := entity.Transaction{Name: "Cafe", Amount: 20, Date: janDate}
+	newFebruary := entity.Transaction{Name: "Unknown", Amount: 30, Date: febDate}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", janDate, febDate).
+		Return([]entity.Transaction{existing, existing, newJanuary, newFebruary}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(
+			_ context.Context,
+			_ string,
+			message string,
+			options ...gpt.ChatCompletionOption,
+		) (string, error) {
+			var input categorizationInput
+			if err := json.Unmarshal([]byte(message), &input); err != nil {
+				t.Fatalf("categorization input = %q: %v", message, err)
+			}
+			if len(input.TransactionNames) != 3 {
+				t.Fatalf("unique names = %#v", input.TransactionNames)
+			}
+			if len(input.Categories) != 2 || input.Categories[0] != "Food" {
+				t.Fatalf("categories = %#v", input.Categories)
+			}
+			if input.Mappings["Market"] != "Food" || input.Fallback != entity.DefaultFallbackCategory {
+				t.Fatalf("categorization input = %#v", input)
+			}
+
+			completionOptions := applyChatCompletionOptions(options)
+			if completionOptions.SystemMessage != categorizationSystemMessage {
+				t.Fatalf("system message = %q", completionOptions.SystemMessage)
+			}
+			if completionOptions.JSONSchema == nil || completionOptions.JSONSchema.Name != "transaction_categories" {
+				t.Fatalf("JSON schema = %#v", completionOptions.JSONSchema)
+			}
+			schema := completionOptions.JSONSchema.Schema
+			marketSchema, _ := schema["properties"].(map[string]any)["Market"].(map[string]any)
+			if !reflect.DeepEqual(schema["required"], input.TransactionNames) ||
+				!reflect.DeepEqual(marketSchema["enum"], input.Categories) ||
+				schema["additionalProperties"] != false {
+				t.Fatalf("JSON schema = %#v", schema)
+			}
+
+			return `{"Market":"Food","Cafe":"Food","Unknown":"not-configured"}`, nil
+		}).
+		Once()
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "jan", Title: "Jan 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(mock.Anything, "ingest-profile", "jan", externalIDColumn("External ID")).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "jan").
+		Return([]sheet.Record{{ID: "existing", Row: transactionToRow(existing, entity.LanguageEnglish)}}, nil).
+		Once()
+	store.EXPECT().
+		CreateTable(
+			mock.Anything,
+			"ingest-profile",
+			mock.Anything,
+		).
+		Return(sheet.Table{ID: "created-Feb 2026", Title: "Feb 2026"}, nil).
+		Once()
+
+	var insertedMutex sync.Mutex
+	inserted := make([]insertedTransaction, 0, 2)
+	store.EXPECT().
+		InsertRows(mock.Anything, "ingest-profile", mock.Anything, mock.Anything).
+		Run(func(_ context.Context, _ string, tableID string, rows []sheet.Row) {
+			for _, row := range rows {
+				transaction, err := rowToTransaction(row, entity.LanguageEnglish)
+				if err != nil {
+					t.Errorf("rowToTransaction() error = %v", err)
+
+					return
+				}
+				insertedMutex.Lock()
+				inserted = append(inserted, insertedTransaction{tableID: tableID, transaction: transaction})
+				insertedMutex.Unlock()
+			}
+		}).
+		Return(nil).
+		Twice()
+
+	ingestUseCase := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		testSettings("ingest-profile"),
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	)
+	output, err := ingestUseCase.Execute(context.Background(), IngestInput{
+		StartDate: janDate,
+		EndDate:   febDate,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(inserted) != 2 {
+		t.Fatalf("inserted = %#v, want 2 transactions", inserted)
+	}
+	if inserted[0].tableID != "jan" || inserted[0].transaction.Name != "Cafe" {
+		t.Fatalf("January insert = %#v", inserted[0])
+	}
+	if inserted[1].tableID != "created-Feb 2026" ||
+		inserted[1].transaction.Category != entity.DefaultFallbackCategory {
+		t.Fatalf("February insert = %#v", inserted[1])
+	}
+
+	wantReports := []ProfileReport{{
+		IngestProfileID: "ingest-profile",
+		Status:          ProfileStatusSucceeded,
+		Months: []MonthReport{
+			{
+				Month:       time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+				Title:       "Jan 2026",
+				Fetched:     3,
+				Filtered:    map[FilterReason]int{},
+				Categorized: map[CategorySource]int{CategorySourceMapping: 2, CategorySourceGPT: 1},
+				Duplicates:  2,
+				Inserted:    1,
+			},
+			{
+				Month:       time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+				Title:       "Feb 2026",
+				Fetched:     1,
+				Filtered:    map[FilterReason]int{},
+				Categorized: map[CategorySource]int{CategorySourceFallback: 1},
+				Inserted:    1,
+			},
+		},
+	}}
+	if !reflect.DeepEqual(output.Reports, wantReports) {
+		t.Fatalf("reports = %#v, want %#v", output.Reports, wantReports)
+	}
+}
+
+func TestIngestReusesExistingTableLanguage(t *testing.T) {
+	tests := []struct {
+		name                  string
+		configuredLanguage    entity.Language
+		existingTitle         string
+		existingTableLanguage entity.Language
+		externalIDColumn      string
+		paymentMethodColumn   string
+		paymentMethodLabel    sheet.SelectCell
+	}{
+		{
+			name:                  "Portuguese profile reuses English table",
+			configuredLanguage:    entity.LanguagePortugueseBrazil,
+			existingTitle:         "Aug 2026",
+			existingTableLanguage: entity.LanguageEnglish,
+			externalIDColumn:      "External ID",
+			paymentMethodColumn:   "Payment Method",
+			paymentMethodLabel:    "CREDIT CARD",
+		},
+		{
+			name:                  "English profile reuses Portuguese table",
+			configuredLanguage:    entity.LanguageEnglish,
+			existingTitle:         "Ago 2026",
+			existingTableLanguage: entity.LanguagePortugueseBrazil,
+			externalIDColumn:      "ID externo",
+			paymentMethodColumn:   "Forma de pagamento",
+			paymentMethodLabel:    "CARTÃO DE CRÉDITO",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+			source := mockopenfinance.NewMockOpenFinance(t)
+			source.EXPECT().
+				ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
+				Return([]entity.Transaction{{
+					Name:          "Store",
+					Amount:        10,
+					PaymentMethod: entity.PaymentMethodCreditCard,
+					Date:          date,
+				}}, nil).
+				Once()
+
+			categorizer := mockgpt.NewMockGPT(t)
+			categorizer.EXPECT().
+				CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(`{"Store":"Food"}`, nil).
+				Once()
+
+			store := mocksheet.NewMockSheet(t)
+			store.EXPECT().
+				ListTables(mock.Anything, "ingest-profile").
+				Return([]sheet.Table{{ID: "existing", Title: test.existingTitle}}, nil).
+				Once()
+			store.EXPECT().
+				EnsureTableColumns(
+					mock.Anything,
+					"ingest-profile",
+					"existing",
+					externalIDColumn(test.externalIDColumn),
+				).
+				Return(nil).
+				Once()
+			store.EXPECT().
+				ListRows(mock.Anything, "ingest-profile", "existing").
+				Return(nil, nil).
+				Once()
+			store.EXPECT().
+				InsertRows(
+					mock.Anything,
+					"ingest-profile",
+					"existing",
+					mock.MatchedBy(func(rows []sheet.Row) bool {
+						if len(rows) != 1 {
+							return false
+						}
+						transaction, err := rowToTransaction(rows[0], test.existingTableLanguage)
+
+						return err == nil && transaction.Name == "Store" &&
+							transaction.PaymentMethod == entity.PaymentMethodCreditCard &&
+							rows[0][test.paymentMethodColumn] == test.paymentMethodLabel
+					}),
+				).
+				Return(nil).
+				Once()
+
+			settings := testSettings("ingest-profile")
+			settings.IngestProfiles[0].Language = test.configuredLanguage
+			if _, err := NewIngest(
+				validator.NewValidator(),
+				testMaxConcurrentOperations,
+				settings,
+				noCompanyLookup(t),
+				categorizer,
+				emptyCategorizationCache(t),
+				store,
+				source,
+			).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestIngestUpgradesExistingTableAndOnlyCategorizesNewRows(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	existing := entity.Transaction{Name: "Rent", Amount: 1000, Date: date}
+	newTransaction := entity.Transaction{Name: "Cafe", Amount: 20, Date: date}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
+		Return([]entity.Transaction{existing, newTransaction}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{
+			"Rent":{"category":"Food","budget_group":"Fixed Costs"},
+			"Cafe":{"category":"Food","budget_group":"Lifestyle"}
+		}`, nil).
+		Once()
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "august", Title: "Ago 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			mock.MatchedBy(func(columns []sheet.Column) bool {
+				if len(columns) != 2 || columns[0].Definition().Name() != "ID externo" {
+					return false
+				}
+				definition := columns[1].Definition()
+				if definition.Name() != "Grupo do orçamento" {
+					return false
+				}
+				options := definition.SelectOptions()
+
+				return len(options) == 3 && options[0].Name() == "Fixed Costs" &&
+					options[1].Name() == "Lifestyle" && options[2].Name() == "Other"
+			}),
+		).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "august").
+		Return([]sheet.Record{
+			{ID: "existing", Row: transactionToRow(existing, entity.LanguagePortugueseBrazil)},
+		}, nil).
+		Once()
+	store.EXPECT().
+		InsertRows(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			mock.MatchedBy(func(rows []sheet.Row) bool {
+				if len(rows) != 1 {
+					return false
+				}
+				transaction, err := rowToTransaction(rows[0], entity.LanguagePortugueseBrazil)
+
+				return err == nil && transaction.Name == "Cafe" &&
+					transaction.Category == "Food" && transaction.BudgetGroup == "Lifestyle"
+			}),
+		).
+		Return(nil).
+		Once()
+
+	settings := testBudgetGroupProfileSettings("ingest-profile")
+	settings.Language = entity.LanguagePortugueseBrazil
+	if _, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{settings}},
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+}
+
+func TestIngestDeduplicatesByExternalIDWithLegacyFallback(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	legacyCoffee := entity.Transaction{Name: "Coffee", Amount: 5, Date: date}
+	renamed := entity.Transaction{ExternalID: "renamed", Name: "Old name", Amount: 30, Date: date}
+	firstCoffee := entity.Transaction{ExternalID: "coffee-1", Name: "Coffee", Amount: 5, Date: date}
+	secondCoffee := entity.Transaction{ExternalID: "coffee-2", Name: "Coffee", Amount: 5, Date: date}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
+		Return([]entity.Transaction{
+			firstCoffee,
+			secondCoffee,
+			{ExternalID: "renamed", Name: "New name", Amount: 30, Date: date},
+			firstCoffee,
+		}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"Coffee":"Food"}`, nil).
+		Once()
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(mock.Anything, "ingest-profile", "august", externalIDColumn("External ID")).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "august").
+		Return([]sheet.Record{
+			{ID: "legacy-coffee", Row: transactionToRow(legacyCoffee, entity.LanguageEnglish)},
+			{ID: "renamed", Row: transactionToRow(renamed, entity.LanguageEnglish)},
+		}, nil).
+		Once()
+	store.EXPECT().
+		InsertRows(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			mock.MatchedBy(func(rows []sheet.Row) bool {
+				if len(rows) != 1 {
+					return false
+				}
+				transaction, err := rowToTransaction(rows[0], entity.LanguageEnglish)
+
+				return err == nil && transaction.ExternalID == "coffee-2" && transaction.Name == "Coffee"
+			}),
+		).
+		Return(nil).
+		Once()
+
+	if _, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		testSettings("ingest-profile"),
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+}
+
+func TestIngestReconcileUpdatesChangedTransactions(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	storedChanged := entity.Transaction{ExternalID: "changed", Name: "Store", Category: "Home", Amount: 10, Date: date}
+	storedSame := entity.Transaction{ExternalID: "same", Name: "Cafe", Category: "Home", Amount: 5, Date: date}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
+		Return([]entity.Transaction{
+			{ExternalID: "changed", Name: "Store", Amount: 12, Date: date},
+			{ExternalID: "same", Name: "Cafe", Amount: 5, Date: date.Add(30 * time.Second)},
+			{ExternalID: "new", Name: "Bakery", Amount: 3, Date: date},
+		}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"Store":"Food","Cafe":"Food","Bakery":"Food"}`, nil).
+		Once()
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(mock.Anything, "ingest-profile", "august", externalIDColumn("External ID")).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "august").
+		Return([]sheet.Record{
+			{ID: "row-changed", Row: transactionToRow(storedChanged, entity.LanguageEnglish)},
+			{ID: "row-same", Row: transactionToRow(storedSame, entity.LanguageEnglish)},
+		}, nil).
+		Once()
+	store.EXPECT().
+		InsertRows(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			mock.MatchedBy(func(rows []sheet.Row) bool {
+				return len(rows) == 1 && rows[0]["Name"] == sheet.TitleCell("Bakery")
+			}),
+		).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		UpdateRow(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			"row-changed",
+			mock.MatchedBy(func(row sheet.Row) bool {
+				_, hasCategory := row["Category"]
+
+				return !hasCategory && row["Amount"] == sheet.NumberCell(12) &&
+					row["Name"] == sheet.TitleCell("Store") && row["External ID"] == sheet.TextCell("changed")
+			}),
+		).
+		Return(nil).
+		Once()
+
+	settings := testIngestProfileSettings("ingest-profile")
+	settings.SyncMode = entity.SyncModeReconcile
+	settings.PreservedColumns = []entity.TransactionColumn{entity.TransactionColumnCategory}
+	if _, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{settings}},
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+}
+
+func TestIngestPrunesVanishedTransactions(t *testing.T) {
+	startDate := time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, time.August, 20, 0, 0, 0, 0, time.UTC)
+	date := time.Date(2026, time.August, 15, 12, 0, 0, 0, time.UTC)
+	kept := entity.Transaction{ExternalID: "kept", Name: "Store", Amount: 10, Date: date}
+	transfer := entity.Transaction{
+		ExternalID: "transfer",
+		Name:       "Savings",
+		Amount:     50,
+		Date:       date,
+		Direction:  entity.TransactionDirectionCredit,
+	}
+	stored := []sheet.Record{
+		{ID: "row-kept", Row: transactionToRow(kept, entity.LanguageEnglish)},
+		{ID: "row-pending", Row: transactionToRow(
+			entity.Transaction{ExternalID: "pending", Name: "Hotel", Amount: 300, Date: date},
+			entity.LanguageEnglish,
+		)},
+		{ID: "row-transfer", Row: transactionToRow(transfer, entity.LanguageEnglish)},
+		{ID: "row-legacy", Row: transactionToRow(
+			entity.Transaction{Name: "Cafe", Amount: 5, Date: date},
+			entity.LanguageEnglish,
+		)},
+		{ID: "row-before-range", Row: transactionToRow(
+			entity.Transaction{ExternalID: "before", Name: "Bakery", Amount: 3, Date: startDate.AddDate(0, 0, -1)},
+			entity.LanguageEnglish,
+		)},
+		{ID: "row-declined", Row: transactionToRow(
+			entity.Transaction{ExternalID: "declined", Name: "Market", Amount: 20, Date: date},
+			entity.LanguageEnglish,
+		)},
+	}
+
+	tests := []struct {
+		name        string
+		prune       PruneMode
+		upstream    []entity.Transaction
+		wantDeleted []string
+	}{
+		{
+			name:        "archive",
+			prune:       PruneModeArchive,
+			upstream:    []entity.Transaction{kept, transfer},
+			wantDeleted: []string{"row-pending", "row-declined"},
+		},
+		{name: "dry run", prune: PruneModeDryRun, upstream: []entity.Transaction{kept, transfer}},
+		{name: "no listed transactions", prune: PruneModeArchive},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := mockopenfinance.NewMockOpenFinance(t)
+			source.EXPECT().
+				ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", startDate, endDate).
+				Return(slices.Clone(test.upstream), nil).
+				Once()
+
+			categorizer := mockgpt.NewMockGPT(t)
+			if len(test.upstream) > 0 {
+				categorizer.EXPECT().
+					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(`{"Store":"Food"}`, nil).
+					Once()
+			}
+
+			store := mocksheet.NewMockSheet(t)
+			store.EXPECT().
+				ListTables(mock.Anything, "ingest-profile").
+				Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+				Once()
+			store.EXPECT().
+				EnsureTableColumns(mock.Anything, "ingest-profile", "august", externalIDColumn("External ID")).
+				Return(nil).
+				Once()
+			store.EXPECT().
+				ListRows(mock.Anything, "ingest-profile", "august").
+				Return(stored, nil).
+				Once()
+			var deleted []string
+			for _, rowID := range test.wantDeleted {
+				store.EXPECT().
+					DeleteRow(mock.Anything, "ingest-profile", "august", rowID).
+					Run(func(_ context.Context, _, _, rowID string) {
+						deleted = append(deleted, rowID)
+					}).
+					Return(nil).
+					Once()
+			}
+
+			if _, err := NewIngest(
+				validator.NewValidator(),
+				testMaxConcurrentOperations,
+				testSettings("ingest-profile"),
+				noCompanyLookup(t),
+				categorizer,
+				emptyCategorizationCache(t),
+				store,
+				source,
+			).Execute(
+				context.Background(),
+				IngestInput{StartDate: startDate, EndDate: endDate, Prune: test.prune},
+			); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !slices.Equal(deleted, test.wantDeleted) {
+				t.Fatalf("deleted rows = %#v, want %#v", deleted, test.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestIngestSplitsTransactionsIntoLinkedParts(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	rent := entity.Transaction{ExternalID: "rent", Name: "Rent", Amount: 150, Date: date}
+	market := entity.Transaction{ExternalID: "market", Name: "Market", Amount: 40, Date: date}
+	coffee := entity.Transaction{ExternalID: "coffee", Name: "Coffee", Amount: 5, Date: date}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
+		Return([]entity.Transaction{rent, market, coffee}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, message string, _ ...gpt.ChatCompletionOption) (string, error) {
+			var input categorizationInput
+			if err := json.Unmarshal([]byte(message), &input); err != nil {
+				t.Fatalf("categorization input = %q: %v", message, err)
+			}
+			if !slices.Equal(input.TransactionNames, []string{"Coffee"}) {
+				t.Fatalf("transaction names = %v, want only the names no split rule matches", input.TransactionNames)
+			}
+
+			return `{"Coffee":"Food"}`, nil
+		}).
+		Once()
+
+	// Market was written whole before its split rule existed, and the parts of Gone were split from
+	// a transaction the provider no longer lists.
+	gone := entity.Transaction{ExternalID: "gone", Name: "Rent", Amount: 150, Date: date}
+	stored := []sheet.Record{{ID: "row-market", Row: transactionToRow(market, entity.LanguageEnglish)}}
+	for index, amount := range []float64{100, 50} {
+		part := gone
+		part.ExternalID = fmt.Sprintf("gone#%d", index+1)
+		part.ParentID = gone.ID()
+		part.Amount = amount
+		stored = append(stored, sheet.Record{
+			ID:  fmt.Sprintf("row-gone-%d", index+1),
+			Row: transactionToRow(part, entity.LanguageEnglish),
+		})
+	}
+
+	var inserted []entity.Transaction
+	var deleted []string
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			mock.MatchedBy(func(columns []sheet.Column) bool {
+				return len(columns) == 2 && columns[0].Definition().Name() == "External ID" &&
+					columns[1].Definition().Name() == "Parent ID"
+			}),
+		).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "august").
+		Return(stored, nil).
+		Once()
+	store.EXPECT().
+		InsertRows(mock.Anything, "ingest-profile", "august", mock.Anything).
+		RunAndReturn(func(_ context.Context, _, _ string, rows []sheet.Row) error {
+			for _, row := range rows {
+				transaction, err := rowToTransaction(row, entity.LanguageEnglish)
+				if err != nil {
+					t.Fatalf("rowToTransaction() error = %v", err)
+				}
+				inserted = append(inserted, transaction)
+			}
+
+			return nil
+		}).
+		Once()
+	store.EXPECT().
+		DeleteRow(mock.Anything, "ingest-profile", "august", mock.Anything).
+		Run(func(_ context.Context, _, _, rowID string) {
+			deleted = append(deleted, rowID)
+		}).
+		Return(nil).
+		Times(2)
+
+	settings := testSettings("ingest-profile")
+	settings.IngestProfiles[0].SplitRules = []entity.SplitRule{
+		{Name: "Rent", Parts: []entity.SplitPart{{Category: "Food", Amount: new(100.0)}, {Category: "Outros"}}},
+		{
+			Name:  "Market",
+			Parts: []entity.SplitPart{{Category: "Food", Ratio: new(0.5)}, {Category: "Outros", Ratio: new(0.5)}},
+		},
+	}
+
+	output, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		settings,
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date, Prune: PruneModeArchive})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []entity.Transaction{
+		{ExternalID: "rent#1", ParentID: "rent", Name: "Rent", Category: "Food", Amount: 100, Date: date},
+		{ExternalID: "rent#2", ParentID: "rent", Name: "Rent", Category: "Outros", Amount: 50, Date: date},
+		{ExternalID: "coffee", Name: "Coffee", Category: "Food", Amount: 5, Date: date},
+	}
+	if !reflect.DeepEqual(inserted, want) {
+		t.Fatalf("inserted = %#v, want %#v", inserted, want)
+	}
+	if !slices.Equal(deleted, []string{"row-gone-1", "row-gone-2"}) {
+		t.Fatalf("deleted rows = %#v", deleted)
+	}
+
+	report := output.Reports[0].Months[0]
+	if report.Categorized[CategorySourceSplit] != 2 || report.Inserted != 3 || report.Duplicates != 2 ||
+		report.Archived != 2 {
+		t.Fatalf("report = %#v", report)
+	}
+}
+
+func TestIngestLinksMarkedRefundsToTheirPurchase(t *testing.T) {
+	card := "1234"
+	startDate := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	purchase := entity.Transaction{
+		ExternalID:     "purchase",
+		Name:           "AMAZON MARKETPLACE",
+		Amount:         100,
+		PaymentMethod:  entity.PaymentMethodCreditCard,
+		CardLastDigits: &card,
+		Direction:      entity.TransactionDirectionDebit,
+		Date:           date.AddDate(0, 0, -3),
+	}
+	refund := entity.Transaction{
+		ExternalID:     "refund",
+		Name:           "ESTORNO AMAZON",
+		Amount:         40,
+		PaymentMethod:  entity.PaymentMethodCreditCard,
+		CardLastDigits: &card,
+		Direction:      entity.TransactionDirectionCredit,
+		Date:           date,
+	}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(
+			mock.Anything,
+			"ingest-profile",
+			startDate.AddDate(0, 0, -entity.DefaultRefundWindowDays),
+			date,
+		).
+		Return([]entity.Transaction{purchase, refund}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"AMAZON MARKETPLACE":"Food","ESTORNO AMAZON":"Food"}`, nil).
+		Once()
+
+	// The purchase was written before its refund arrived.
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			mock.MatchedBy(func(columns []sheet.Column) bool {
+				return len(columns) == 2 && columns[1].Definition().Name() == "Refund Link"
+			}),
+		).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "august").
+		Return([]sheet.Record{{ID: "row-purchase", Row: transactionToRow(purchase, entity.LanguageEnglish)}}, nil).
+		Once()
+	store.EXPECT().
+		InsertRows(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			mock.MatchedBy(func(rows []sheet.Row) bool {
+				return len(rows) == 1 && rows[0]["External ID"] == sheet.TextCell("refund") &&
+					rows[0]["Refund Link"] == sheet.TextCell("purchase")
+			}),
+		).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		UpdateRow(
+			mock.Anything,
+			"ingest-profile",
+			"august",
+			"row-purchase",
+			mock.MatchedBy(func(row sheet.Row) bool {
+				return row["Amount"] == sheet.NumberCell(100) && row["Refund Link"] == sheet.TextCell("refund")
+			}),
+		).
+		Return(nil).
+		Once()
+
+	settings := testSettings("ingest-profile")
+	settings.IngestProfiles[0].SyncMode = entity.SyncModeReconcile
+	settings.IngestProfiles[0].RefundMode = entity.RefundModeMark
+	settings.IngestProfiles[0].RefundWindowDays = entity.DefaultRefundWindowDays
+
+	output, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		settings,
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(context.Background(), IngestInput{StartDate: startDate, EndDate: date})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	report := output.Reports[0].Months[0]
+	if len(report.Filtered) != 0 || report.Inserted != 1 || report.Updated != 1 {
+		t.Fatalf("report = %#v", report)
+	}
+}
+
+func TestIngestNetsRefundsOfPurchasesFromEarlierMonths(t *testing.T) {
+	card := "1234"
+	startDate := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, time.August, 31, 0, 0, 0, 0, time.UTC)
+	purchase := entity.Transaction{
+		ExternalID:     "purchase",
+		Name:           "AMAZON MARKETPLACE",
+		Amount:         100,
+		PaymentMethod:  entity.PaymentMethodCreditCard,
+		CardLastDigits: &card,
+		Direction:      entity.TransactionDirectionDebit,
+		Date:           time.Date(2026, time.July, 20, 12, 0, 0, 0, time.UTC),
+	}
+	refund := entity.Transaction{
+		ExternalID:     "refund",
+		Name:           "ESTORNO AMAZON",
+		Amount:         40,
+		PaymentMethod:  entity.PaymentMethodCreditCard,
+		CardLastDigits: &card,
+		Direction:      entity.TransactionDirectionCredit,
+		Date:           time.Date(2026, time.August, 5, 12, 0, 0, 0, time.UTC),
+	}
+
+	// The purchase is only listed because of the refund window.
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(
+			mock.Anything,
+			"ingest-profile",
+			startDate.AddDate(0, 0, -entity.DefaultRefundWindowDays),
+			endDate,
+		).
+		Return([]entity.Transaction{purchase, refund}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"AMAZON MARKETPLACE":"Food"}`, nil).
+		Once()
+
+	// The purchase was written by the run of July, and is updated even in append mode.
 	store := mocksheet.NewMockSheet(t)
 	store.EXPECT().
 		ListTables(mock.Anything, "ingest-profile").
-		Return([]sheet.Table{{ID: "jan", Title: "Jan 2026"}}, nil).
+		Return([]sheet.Table{{ID: "july", Title: "Jul 2026"}, {ID: "august", Title: "Aug 2026"}}, nil).
 		Once()
 	store.EXPECT().
-		ListRows(mock.Anything, "ingest-profile", "jan").
-		Return([]sheet.Row{transactionToRow(existing, entity.LanguageEnglish)}, nil).
+		EnsureTableColumns(mock.Anything, "ingest-profile", "august", mock.Anything).
+		Return(nil).
 		Once()
 	store.EXPECT().
-		CreateTable(
+		ListRows(mock.Anything, "ingest-profile", "august").
+		Return(nil, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(mock.Anything, "ingest-profile", "july", mock.Anything).
+		Return(nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "july").
+		Return([]sheet.Record{{ID: "row-purchase", Row: transactionToRow(purchase, entity.LanguageEnglish)}}, nil).
+		Once()
+	store.EXPECT().
+		UpdateRow(
 			mock.Anything,
 			"ingest-profile",
-			mock.Anything,
+			"july",
+			"row-purchase",
+			mock.MatchedBy(func(row sheet.Row) bool {
+				return row["Amount"] == sheet.NumberCell(60)
+			}),
 		).
-		Return(sheet.Table{ID: "created-Feb 2026", Title: "Feb 2026"}, nil).
+		Return(nil).
 		Once()
 
-	var insertedMutex sync.Mutex
-	inserted := make([]insertedTransaction, 0, 2)
-	store.EXPECT().
-		InsertRow(mock.Anything, "ingest-profile", mock.Anything, mock.Anything).
-		Run(func(_ context.Context, _ string, tableID string, row sheet.Row) {
-			transaction, err := rowToTransaction(row, entity.LanguageEnglish)
-			if err != nil {
-				t.Errorf("rowToTransaction() error = %v", err)
-
-				return
-			}
-			insertedMutex.Lock()
-			inserted = append(inserted, insertedTransaction{tableID: tableID, transaction: transaction})
-			insertedMutex.Unlock()
-		}).
-		Return(nil).
-		Twice()
+	settings := testSettings("ingest-profile")
+	settings.IngestProfiles[0].RefundMode = entity.RefundModeNet
+	settings.IngestProfiles[0].RefundWindowDays = entity.DefaultRefundWindowDays
 
-	ingestUseCase := NewIngest(
+	output, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
-		testSettings("ingest-profile"),
+		settings,
 		noCompanyLookup(t),
 		categorizer,
+		emptyCategorizationCache(t),
 		store,
 		source,
-	)
-	err := ingestUseCase.Execute(context.Background(), IngestInput{
-		StartDate: janDate,
-		EndDate:   febDate,
-	})
+	).Execute(context.Background(), IngestInput{StartDate: startDate, EndDate: endDate})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	if len(inserted) != 2 {
-		t.Fatalf("inserted = %#v, want 2 transactions", inserted)
-	}
-	if inserted[0].tableID != "jan" || inserted[0].transaction.Name != "Cafe" {
-		t.Fatalf("January insert = %#v", inserted[0])
-	}
-	if inserted[1].tableID != "created-Feb 2026" ||
-		inserted[1].transaction.Category != entity.DefaultFallbackCategory {
-		t.Fatalf("February insert = %#v", inserted[1])
+	report := output.Reports[0].Months[0]
+	if report.Fetched != 1 || report.Filtered[FilterReasonRefund] != 1 || report.Inserted != 0 {
+		t.Fatalf("report = %#v", report)
 	}
 }
 
-func TestIngestReusesExistingTableLanguage(t *testing.T) {
+func TestIngestWritesInstallmentsOnlyWithInstallmentColumns(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	installment := entity.Transaction{
+		ExternalID:        "market-2",
+		Name:              "Market PARC 02/03",
+		Amount:            50,
+		InstallmentNumber: 2,
+		TotalInstallments: 3,
+		PurchaseAmount:    150,
+		Date:              date,
+	}
+
 	tests := []struct {
-		name                  string
-		configuredLanguage    entity.Language
-		existingTitle         string
-		existingTableLanguage entity.Language
-		paymentMethodColumn   string
-		paymentMethodLabel    sheet.SelectCell
+		name               string
+		installmentColumns bool
+		wantColumns        int
 	}{
-		{
-			name:                  "Portuguese profile reuses English table",
-			configuredLanguage:    entity.LanguagePortugueseBrazil,
-			existingTitle:         "Aug 2026",
-			existingTableLanguage: entity.LanguageEnglish,
-			paymentMethodColumn:   "Payment Method",
-			paymentMethodLabel:    "CREDIT CARD",
-		},
-		{
-			name:                  "English profile reuses Portuguese table",
-			configuredLanguage:    entity.LanguageEnglish,
-			existingTitle:         "Ago 2026",
-			existingTableLanguage: entity.LanguagePortugueseBrazil,
-			paymentMethodColumn:   "Forma de pagamento",
-			paymentMethodLabel:    "CARTÃO DE CRÉDITO",
-		},
+		{name: "off"},
+		{name: "on", installmentColumns: true, wantColumns: 3},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
 			source := mockopenfinance.NewMockOpenFinance(t)
 			source.EXPECT().
 				ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
-				Return([]entity.Transaction{{
-					Name:          "Store",
-					Amount:        10,
-					PaymentMethod: entity.PaymentMethodCreditCard,
-					Date:          date,
-				}}, nil).
+				Return([]entity.Transaction{installment}, nil).
 				Once()
 
 			categorizer := mockgpt.NewMockGPT(t)
 			categorizer.EXPECT().
-				CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
-				Return(`{"Store":"Food"}`, nil).
+				CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(`{"Market":"Food"}`, nil).
 				Once()
 
+			installmentCells := func(row sheet.Row) int {
+				cells := 0
+				for _, name := range []string{"Installment", "Installments", "Purchase Amount"} {
+					if _, exists := row[name]; exists {
+						cells++
+					}
+				}
+
+				return cells
+			}
 			store := mocksheet.NewMockSheet(t)
 			store.EXPECT().
 				ListTables(mock.Anything, "ingest-profile").
-				Return([]sheet.Table{{ID: "existing", Title: test.existingTitle}}, nil).
+				Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
 				Once()
 			store.EXPECT().
-				ListRows(mock.Anything, "ingest-profile", "existing").
-				Return(nil, nil).
+				EnsureTableColumns(
+					mock.Anything,
+					"ingest-profile",
+					"august",
+					mock.MatchedBy(func(columns []sheet.Column) bool {
+						return len(columns) == 1+test.wantColumns
+					}),
+				).
+				Return(nil).
 				Once()
+			store.EXPECT().ListRows(mock.Anything, "ingest-profile", "august").Return(nil, nil).Once()
 			store.EXPECT().
-				InsertRow(
+				InsertRows(
 					mock.Anything,
 					"ingest-profile",
-					"existing",
-					mock.MatchedBy(func(row sheet.Row) bool {
-						transaction, err := rowToTransaction(row, test.existingTableLanguage)
-
-						return err == nil && transaction.Name == "Store" &&
-							transaction.PaymentMethod == entity.PaymentMethodCreditCard &&
-							row[test.paymentMethodColumn] == test.paymentMethodLabel
+					"august",
+					mock.MatchedBy(func(rows []sheet.Row) bool {
+						return len(rows) == 1 && installmentCells(rows[0]) == test.wantColumns
 					}),
 				).
 				Return(nil).
 				Once()
 
 			settings := testSettings("ingest-profile")
-			settings.IngestProfiles[0].Language = test.configuredLanguage
-			if err := NewIngest(
+			settings.IngestProfiles[0].InstallmentColumns = test.installmentColumns
+
+			if _, err := NewIngest(
 				validator.NewValidator(),
 				testMaxConcurrentOperations,
 				settings,
 				noCompanyLookup(t),
 				categorizer,
+				emptyCategorizationCache(t),
 				store,
 				source,
 			).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
@@ -618,83 +2005,145 @@ func TestIngestReusesExistingTableLanguage(t *testing.T) {
 	}
 }
 
-func TestIngestUpgradesExistingTableAndOnlyCategorizesNewRows(t *testing.T) {
+func TestIngestDryRunPlansChangesWithoutWriting(t *testing.T) {
+	startDate := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC)
+	august := time.Date(2026, time.August, 15, 12, 0, 0, 0, time.UTC)
+	september := time.Date(2026, time.September, 2, 12, 0, 0, 0, time.UTC)
+	stored := entity.Transaction{ExternalID: "changed", Name: "Store", Amount: 10, Date: august}
+	vanished := entity.Transaction{ExternalID: "pending", Name: "Hotel", Amount: 300, Date: august}
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", startDate, endDate).
+		Return([]entity.Transaction{
+			{ExternalID: "changed", Name: "Store", Amount: 12, Date: august},
+			{ExternalID: "new", Name: "Cafe", Amount: 5, Date: august},
+			{ExternalID: "next-month", Name: "Market", Amount: 20, Date: september},
+		}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"Store":"Food","Cafe":"Food","Market":"Food"}`, nil).
+		Once()
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		ListRows(mock.Anything, "ingest-profile", "august").
+		Return([]sheet.Record{
+			{ID: "row-changed", Row: transactionToRow(stored, entity.LanguageEnglish)},
+			{ID: "row-pending", Row: transactionToRow(vanished, entity.LanguageEnglish)},
+		}, nil).
+		Once()
+
+	settings := testIngestProfileSettings("ingest-profile")
+	settings.SyncMode = entity.SyncModeReconcile
+	output, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{settings}},
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(
+		context.Background(),
+		IngestInput{StartDate: startDate, EndDate: endDate, Prune: PruneModeArchive, DryRun: true},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(output.Plan) != 2 {
+		t.Fatalf("plan = %#v, want August and September", output.Plan)
+	}
+	augustPlan, septemberPlan := output.Plan[0], output.Plan[1]
+	if augustPlan.IngestProfileID != "ingest-profile" || augustPlan.Title != "Aug 2026" || augustPlan.Create ||
+		!slices.Equal(augustPlan.Columns, []string{"External ID"}) ||
+		len(augustPlan.Insert) != 1 || augustPlan.Insert[0].Name != "Cafe" ||
+		len(augustPlan.Update) != 1 || augustPlan.Update[0].Amount != 12 ||
+		len(augustPlan.Archive) != 1 || augustPlan.Archive[0].ExternalID != "pending" {
+		t.Fatalf("August plan = %#v", augustPlan)
+	}
+	if septemberPlan.Title != "Sep 2026" || !septemberPlan.Create ||
+		len(septemberPlan.Columns) != 7 || septemberPlan.Columns[0] != "Name" ||
+		len(septemberPlan.Insert) != 1 || septemberPlan.Insert[0].Name != "Market" ||
+		len(septemberPlan.Update) != 0 || len(septemberPlan.Archive) != 0 {
+		t.Fatalf("September plan = %#v", septemberPlan)
+	}
+}
+
+func TestIngestReconcileReportsFailedUpdates(t *testing.T) {
 	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
-	existing := entity.Transaction{Name: "Rent", Amount: 1000, Date: date}
-	newTransaction := entity.Transaction{Name: "Cafe", Amount: 20, Date: date}
+	stored := []entity.Transaction{
+		{ExternalID: "first", Name: "Store", Amount: 10, Date: date},
+		{ExternalID: "second", Name: "Cafe", Amount: 5, Date: date},
+	}
 
 	source := mockopenfinance.NewMockOpenFinance(t)
 	source.EXPECT().
 		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
-		Return([]entity.Transaction{existing, newTransaction}, nil).
+		Return([]entity.Transaction{
+			{ExternalID: "first", Name: "Store", Amount: 11, Date: date},
+			{ExternalID: "second", Name: "Cafe", Amount: 6, Date: date},
+		}, nil).
 		Once()
 
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
-		Return(`{
-			"Rent":{"category":"Food","budget_group":"Fixed Costs"},
-			"Cafe":{"category":"Food","budget_group":"Lifestyle"}
-		}`, nil).
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"Store":"Food","Cafe":"Food"}`, nil).
 		Once()
 
 	store := mocksheet.NewMockSheet(t)
 	store.EXPECT().
 		ListTables(mock.Anything, "ingest-profile").
-		Return([]sheet.Table{{ID: "august", Title: "Ago 2026"}}, nil).
+		Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
 		Once()
 	store.EXPECT().
-		EnsureTableColumns(
-			mock.Anything,
-			"ingest-profile",
-			"august",
-			mock.MatchedBy(func(columns []sheet.Column) bool {
-				if len(columns) != 1 {
-					return false
-				}
-				definition := columns[0].Definition()
-				if definition.Name() != "Grupo do orçamento" {
-					return false
-				}
-				options := definition.SelectOptions()
-
-				return len(options) == 3 && options[0].Name() == "Fixed Costs" &&
-					options[1].Name() == "Lifestyle" && options[2].Name() == "Other"
-			}),
-		).
+		EnsureTableColumns(mock.Anything, "ingest-profile", "august", externalIDColumn("External ID")).
 		Return(nil).
 		Once()
 	store.EXPECT().
 		ListRows(mock.Anything, "ingest-profile", "august").
-		Return([]sheet.Row{transactionToRow(existing, entity.LanguagePortugueseBrazil)}, nil).
+		Return([]sheet.Record{
+			{ID: "row-first", Row: transactionToRow(stored[0], entity.LanguageEnglish)},
+			{ID: "row-second", Row: transactionToRow(stored[1], entity.LanguageEnglish)},
+		}, nil).
 		Once()
+	rowErr := errors.New("row was deleted")
 	store.EXPECT().
-		InsertRow(
-			mock.Anything,
-			"ingest-profile",
-			"august",
-			mock.MatchedBy(func(row sheet.Row) bool {
-				transaction, err := rowToTransaction(row, entity.LanguagePortugueseBrazil)
-
-				return err == nil && transaction.Name == "Cafe" &&
-					transaction.Category == "Food" && transaction.BudgetGroup == "Lifestyle"
-			}),
-		).
+		UpdateRow(mock.Anything, "ingest-profile", "august", "row-first", mock.Anything).
+		Return(rowErr).
+		Once()
+	store.EXPECT().
+		UpdateRow(mock.Anything, "ingest-profile", "august", "row-second", mock.Anything).
 		Return(nil).
 		Once()
 
-	settings := testBudgetGroupProfileSettings("ingest-profile")
-	settings.Language = entity.LanguagePortugueseBrazil
-	if err := NewIngest(
+	settings := testIngestProfileSettings("ingest-profile")
+	settings.SyncMode = entity.SyncModeReconcile
+	_, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
 		entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{settings}},
 		noCompanyLookup(t),
 		categorizer,
+		emptyCategorizationCache(t),
 		store,
 		source,
-	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
-		t.Fatalf("Execute() error = %v", err)
+	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date})
+	if !errors.Is(err, rowErr) ||
+		!strings.Contains(err.Error(), "updated 1 of 2 transactions") ||
+		!strings.Contains(err.Error(), `update transaction "first"`) {
+		t.Fatalf("Execute() error = %v, want the failed update", err)
 	}
 }
 
@@ -716,8 +2165,8 @@ func TestIngestCreatesPortugueseTable(t *testing.T) {
 			definition sheet.TableDefinition,
 		) (sheet.Table, error) {
 			columns := definition.Columns()
-			if len(columns) != 6 || columns[0].Name() != "Nome" ||
-				columns[3].Name() != "Forma de pagamento" {
+			if len(columns) != 7 || columns[0].Name() != "Nome" ||
+				columns[3].Name() != "Forma de pagamento" || !columns[6].Hidden() {
 				t.Fatalf("table definition = %#v", definition)
 			}
 
@@ -727,12 +2176,13 @@ func TestIngestCreatesPortugueseTable(t *testing.T) {
 
 	settings := testSettings("ingest-profile")
 	settings.IngestProfiles[0].Language = entity.LanguagePortugueseBrazil
-	if err := NewIngest(
+	if _, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
 		settings,
 		noCompanyLookup(t),
 		mockgpt.NewMockGPT(t),
+		emptyCategorizationCache(t),
 		store,
 		source,
 	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
@@ -814,7 +2264,7 @@ func TestIngestEnrichesUniqueCompany(t *testing.T) {
 
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(`{"Company":"Food"}`, nil).
 		Once()
 
@@ -825,33 +2275,43 @@ func TestIngestEnrichesUniqueCompany(t *testing.T) {
 		Return(sheet.Table{ID: "jan", Title: "Jan 2026"}, nil).
 		Once()
 	store.EXPECT().
-		InsertRow(
+		InsertRows(
 			mock.Anything,
 			"ingest-profile",
 			"jan",
-			mock.MatchedBy(func(row sheet.Row) bool {
-				transaction, err := rowToTransaction(row, entity.LanguageEnglish)
+			mock.MatchedBy(func(rows []sheet.Row) bool {
+				for _, row := range rows {
+					transaction, err := rowToTransaction(row, entity.LanguageEnglish)
+					if err != nil || transaction.Name != "Company" || transaction.Category != "Food" {
+						return false
+					}
+				}
 
-				return err == nil && transaction.Name == "Company" && transaction.Category == "Food"
+				return len(rows) == 2
 			}),
 		).
 		Return(nil).
-		Twice()
+		Once()
 
-	if err := NewIngest(
+	output, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
 		testSettings("ingest-profile"),
 		company,
 		categorizer,
+		emptyCategorizationCache(t),
 		store,
 		source,
 	).Execute(
 		context.Background(),
 		IngestInput{StartDate: date, EndDate: date},
-	); err != nil {
+	)
+	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
+	if report := output.Reports[0].Months[0]; report.Renamed != 2 || report.Inserted != 2 {
+		t.Fatalf("report = %#v, want 2 renamed and inserted transactions", report)
+	}
 }
 
 func TestIngestCompanyLookupFailureIsNonFatal(t *testing.T) {
@@ -871,7 +2331,7 @@ func TestIngestCompanyLookupFailureIsNonFatal(t *testing.T) {
 
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(`{"12.345.678/0001-95":"Food"}`, nil).
 		Once()
 
@@ -882,12 +2342,15 @@ func TestIngestCompanyLookupFailureIsNonFatal(t *testing.T) {
 		Return(sheet.Table{ID: "jan", Title: "Jan 2026"}, nil).
 		Once()
 	store.EXPECT().
-		InsertRow(
+		InsertRows(
 			mock.Anything,
 			"ingest-profile",
 			"jan",
-			mock.MatchedBy(func(row sheet.Row) bool {
-				transaction, err := rowToTransaction(row, entity.LanguageEnglish)
+			mock.MatchedBy(func(rows []sheet.Row) bool {
+				if len(rows) != 1 {
+					return false
+				}
+				transaction, err := rowToTransaction(rows[0], entity.LanguageEnglish)
 
 				return err == nil && transaction.Name == "12.345.678/0001-95"
 			}),
@@ -895,12 +2358,13 @@ func TestIngestCompanyLookupFailureIsNonFatal(t *testing.T) {
 		Return(nil).
 		Once()
 
-	if err := NewIngest(
+	if _, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
 		testSettings("ingest-profile"),
 		company,
 		categorizer,
+		emptyCategorizationCache(t),
 		store,
 		source,
 	).Execute(
@@ -927,12 +2391,13 @@ func TestIngestEmptyRangeSkipsCategorizer(t *testing.T) {
 		Return(sheet.Table{ID: "jan", Title: "Jan 2026"}, nil).
 		Once()
 
-	if err := NewIngest(
+	if _, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
 		testSettings("ingest-profile"),
 		noCompanyLookup(t),
 		categorizer,
+		emptyCategorizationCache(t),
 		store,
 		source,
 	).Execute(
@@ -953,12 +2418,13 @@ func TestIngestPropagatesSourceErrorAndCancellation(t *testing.T) {
 			Return(nil, wantErr).
 			Once()
 
-		err := NewIngest(
+		_, err := NewIngest(
 			validator.NewValidator(),
 			testMaxConcurrentOperations,
 			testSettings("ingest-profile"),
 			noCompanyLookup(t),
 			mockgpt.NewMockGPT(t),
+			emptyCategorizationCache(t),
 			mocksheet.NewMockSheet(t),
 			source,
 		).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date})
@@ -979,12 +2445,13 @@ func TestIngestPropagatesSourceErrorAndCancellation(t *testing.T) {
 			}).
 			Once()
 
-		err := NewIngest(
+		_, err := NewIngest(
 			validator.NewValidator(),
 			testMaxConcurrentOperations,
 			testSettings("ingest-profile"),
 			noCompanyLookup(t),
 			mockgpt.NewMockGPT(t),
+			emptyCategorizationCache(t),
 			mocksheet.NewMockSheet(t),
 			source,
 		).Execute(canceledContext, IngestInput{StartDate: date, EndDate: date})
@@ -1024,18 +2491,18 @@ func TestIngestPropagatesCategorizerAndStoreErrors(t *testing.T) {
 			switch test.stage {
 			case "categorizer":
 				categorizer.EXPECT().
-					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return("", categorizerErr).
 					Once()
 			case "list tables":
 				categorizer.EXPECT().
-					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(`{"Store":"Food"}`, nil).
 					Once()
 				store.EXPECT().ListTables(mock.Anything, "ingest-profile").Return(nil, listTablesErr).Once()
 			case "insert":
 				categorizer.EXPECT().
-					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+					CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(`{"Store":"Food"}`, nil).
 					Once()
 				store.EXPECT().ListTables(mock.Anything, "ingest-profile").Return(nil, nil).Once()
@@ -1044,17 +2511,18 @@ func TestIngestPropagatesCategorizerAndStoreErrors(t *testing.T) {
 					Return(sheet.Table{ID: "jan", Title: "Jan 2026"}, nil).
 					Once()
 				store.EXPECT().
-					InsertRow(mock.Anything, "ingest-profile", "jan", mock.Anything).
+					InsertRows(mock.Anything, "ingest-profile", "jan", mock.Anything).
 					Return(insertErr).
 					Once()
 			}
 
-			err := NewIngest(
+			_, err := NewIngest(
 				validator.NewValidator(),
 				testMaxConcurrentOperations,
 				testSettings("ingest-profile"),
 				noCompanyLookup(t),
 				categorizer,
+				emptyCategorizationCache(t),
 				store,
 				source,
 			).Execute(
@@ -1108,12 +2576,13 @@ func TestIngestBoundsIngestProfileConcurrency(t *testing.T) {
 		}).
 		Times(len(ingestProfileIDs))
 
-	if err := NewIngest(
+	if _, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
 		testSettings(ingestProfileIDs...),
 		noCompanyLookup(t),
 		mockgpt.NewMockGPT(t),
+		emptyCategorizationCache(t),
 		store,
 		source,
 	).Execute(context.Background(), IngestInput{StartDate: date, EndDate: date}); err != nil {
@@ -1128,7 +2597,94 @@ func TestIngestBoundsIngestProfileConcurrency(t *testing.T) {
 	}
 }
 
-func TestIngestBoundsInsertConcurrency(t *testing.T) {
+func TestIngestIsolatesFailingProfilesAndMonths(t *testing.T) {
+	janDate := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+	febDate := time.Date(2026, time.February, 10, 12, 0, 0, 0, time.UTC)
+	sourceErr := errors.New("item login expired")
+	listRowsErr := errors.New("list rows failed")
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "expired", janDate, febDate).
+		Return(nil, sourceErr).
+		Once()
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "healthy", janDate, febDate).
+		RunAndReturn(func(ctx context.Context, _ string, _, _ time.Time) ([]entity.Transaction, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			return []entity.Transaction{
+				{Name: "Market", Amount: 10, Date: janDate},
+				{Name: "Market", Amount: 20, Date: febDate},
+			}, nil
+		}).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"Market":"Food"}`, nil).
+		Once()
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "healthy").
+		Return([]sheet.Table{{ID: "jan", Title: "Jan 2026"}}, nil).
+		Once()
+	store.EXPECT().
+		EnsureTableColumns(mock.Anything, "healthy", "jan", externalIDColumn("External ID")).
+		Return(nil).
+		Once()
+	store.EXPECT().ListRows(mock.Anything, "healthy", "jan").Return(nil, listRowsErr).Once()
+	store.EXPECT().
+		CreateTable(mock.Anything, "healthy", mock.Anything).
+		Return(sheet.Table{ID: "feb", Title: "Feb 2026"}, nil).
+		Once()
+	store.EXPECT().
+		InsertRows(mock.Anything, "healthy", "feb", mock.MatchedBy(func(rows []sheet.Row) bool {
+			return len(rows) == 1
+		})).
+		Return(nil).
+		Once()
+
+	output, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		testSettings("expired", "healthy"),
+		noCompanyLookup(t),
+		categorizer,
+		emptyCategorizationCache(t),
+		store,
+		source,
+	).Execute(context.Background(), IngestInput{StartDate: janDate, EndDate: febDate})
+	if !errors.Is(err, sourceErr) || !errors.Is(err, listRowsErr) {
+		t.Fatalf("Execute() error = %v, want both profile errors", err)
+	}
+
+	if len(output.Reports) != 2 {
+		t.Fatalf("reports = %#v", output.Reports)
+	}
+	expired, healthy := output.Reports[0], output.Reports[1]
+	if expired.IngestProfileID != "expired" || expired.Status != ProfileStatusFailed ||
+		!errors.Is(expired.Err, sourceErr) || !errors.Is(expired.Months[0].Err, sourceErr) {
+		t.Fatalf("expired report = %#v", expired)
+	}
+	if healthy.IngestProfileID != "healthy" || healthy.Status != ProfileStatusPartial {
+		t.Fatalf("healthy report = %#v", healthy)
+	}
+	if january := healthy.Months[0]; !errors.Is(january.Err, listRowsErr) || january.Inserted != 0 {
+		t.Fatalf("January report = %#v", january)
+	}
+	if february := healthy.Months[1]; february.Err != nil || february.Inserted != 1 {
+		t.Fatalf("February report = %#v", february)
+	}
+}
+
+func TestIngestInsertsTableInOneBatchAndReportsFailedRows(t *testing.T) {
 	date := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
 	transactions := make([]entity.Transaction, 12)
 	for index := range transactions {
@@ -1147,9 +2703,10 @@ func TestIngestBoundsInsertConcurrency(t *testing.T) {
 
 	categorizer := mockgpt.NewMockGPT(t)
 	categorizer.EXPECT().
-		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything).
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		RunAndReturn(func(
 			_ context.Context,
+			_ string,
 			message string,
 			_ ...gpt.ChatCompletionOption,
 		) (string, error) {
@@ -1169,8 +2726,7 @@ func TestIngestBoundsInsertConcurrency(t *testing.T) {
 		}).
 		Once()
 
-	var activeInserts atomic.Int32
-	var maximumInserts atomic.Int32
+	rowErr := errors.New("row rejected")
 	store := mocksheet.NewMockSheet(t)
 	store.EXPECT().ListTables(mock.Anything, "ingest-profile").Return(nil, nil).Once()
 	store.EXPECT().
@@ -1178,40 +2734,38 @@ func TestIngestBoundsInsertConcurrency(t *testing.T) {
 		Return(sheet.Table{ID: "jan", Title: "Jan 2026"}, nil).
 		Once()
 	store.EXPECT().
-		InsertRow(mock.Anything, "ingest-profile", "jan", mock.Anything).
-		RunAndReturn(func(ctx context.Context, _ string, _ string, _ sheet.Row) error {
-			active := activeInserts.Add(1)
-			defer activeInserts.Add(-1)
-			setMaximum(&maximumInserts, active)
-
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(5 * time.Millisecond):
-				return nil
-			}
-		}).
-		Times(len(transactions))
+		InsertRows(
+			mock.Anything,
+			"ingest-profile",
+			"jan",
+			mock.MatchedBy(func(rows []sheet.Row) bool {
+				return len(rows) == len(transactions)
+			}),
+		).
+		Return(sheet.NewInsertRowsError(len(transactions), []sheet.RowError{{Index: 3, Err: rowErr}})).
+		Once()
 
-	if err := NewIngest(
+	output, err := NewIngest(
 		validator.NewValidator(),
 		testMaxConcurrentOperations,
 		testSettings("ingest-profile"),
 		noCompanyLookup(t),
 		categorizer,
+		emptyCategorizationCache(t),
 		store,
 		source,
 	).Execute(
 		context.Background(),
 		IngestInput{StartDate: date, EndDate: date},
-	); err != nil {
-		t.Fatalf("Execute() error = %v", err)
+	)
+	if !errors.Is(err, rowErr) {
+		t.Fatalf("Execute() error = %v, want %v", err, rowErr)
 	}
-
-	if maximumInserts.Load() > testMaxConcurrentOperations {
-		t.Fatalf("insert concurrency = %d, limit %d", maximumInserts.Load(), testMaxConcurrentOperations)
+	if message := err.Error(); !strings.Contains(message, "inserted 11 of 12 transactions") ||
+		!strings.Contains(message, transactions[3].ID()) {
+		t.Fatalf("Execute() error = %q, want the failed transaction", message)
 	}
-	if maximumInserts.Load() < 2 {
-		t.Fatalf("expected concurrent execution, got inserts=%d", maximumInserts.Load())
+	if report := output.Reports[0].Months[0]; report.Inserted != 11 || report.Failed != 1 {
+		t.Fatalf("report = %#v, want 11 inserted and 1 failed transactions", report)
 	}
 }
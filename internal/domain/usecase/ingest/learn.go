@@ -0,0 +1,250 @@
+package ingest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+type LearnInput struct {
+	StartDate time.Time `validate:"required"`
+	EndDate   time.Time `validate:"required,gtefield=StartDate"`
+	// DryRun finds the corrections without saving them.
+	DryRun bool
+}
+
+type LearnOutput struct {
+	// Corrections are sorted by profile, in the order of the settings, then by name.
+	Corrections []Correction
+}
+
+// Correction is a transaction name classified by hand in the sheet differently from what the
+// categorization assigns it.
+type Correction struct {
+	IngestProfileID string
+	Name            string
+	Category        entity.Category
+	BudgetGroup     entity.BudgetGroup
+	// AssignedCategory and AssignedBudgetGroup are what the categorization assigned before the
+	// correction.
+	AssignedCategory    entity.Category
+	AssignedBudgetGroup entity.BudgetGroup
+	// Direction is the direction whose categories the correction applies to, in profiles that
+	// include credits.
+	Direction entity.TransactionDirection
+}
+
+type CorrectionLearner interface {
+	Learn(ctx context.Context, input LearnInput) (LearnOutput, error)
+}
+
+// Learn compares the rows of the monthly tables in the range with the categorization and saves
+// the names whose category or budget group was changed by hand as learned corrections, which
+// later runs apply before asking GPT. Category and split rules and the source category mappings
+// still take precedence, so rows they classify are not learned, and neither are the parts of split
+// transactions. The categorization is reproduced without asking GPT or writing the cache, so rows
+// whose name neither a rule, a mapping nor the cache resolves are compared with the fallbacks.
+func (s *Ingest) Learn(ctx context.Context, input LearnInput) (LearnOutput, error) {
+	if err := s.val.Validate(input); err != nil {
+		return LearnOutput{}, fmt.Errorf("invalid learn input: %w", err)
+	}
+	if !input.DryRun && !s.categorizationCache.Persistent() {
+		return LearnOutput{}, errors.New("the categorization cache is not persistent, so corrections cannot be saved")
+	}
+
+	var group errgroup.Group
+	group.SetLimit(s.maxConcurrentOperations)
+
+	months := monthsInRange(input.StartDate, input.EndDate)
+	correctionsByProfile := make([][]Correction, len(s.settings.IngestProfiles))
+	errs := make([]error, len(s.settings.IngestProfiles))
+	for index, ingestProfileSettings := range s.settings.IngestProfiles {
+		group.Go(func() error {
+			corrections, err := s.learnProfile(ctx, ingestProfileSettings, input, months)
+			correctionsByProfile[index] = corrections
+			if err != nil {
+				errs[index] = fmt.Errorf("learn profile %q: %w", ingestProfileSettings.ID, err)
+			}
+
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	output := LearnOutput{Corrections: slices.Concat(correctionsByProfile...)}
+	if err := errors.Join(errs...); err != nil {
+		return output, fmt.Errorf("learn corrections: %w", err)
+	}
+
+	return output, nil
+}
+
+func (s *Ingest) learnProfile(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	input LearnInput,
+	months []time.Time,
+) ([]Correction, error) {
+	rows, err := s.classifiedRows(ctx, settings, months)
+	if err != nil {
+		return nil, err
+	}
+	rows, err = s.withSourceCategories(ctx, settings, input, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := slices.Clone(rows)
+	sources, err := s.categorize(ctx, settings, assigned, false)
+	if err != nil {
+		return nil, fmt.Errorf("categorize transactions: %w", err)
+	}
+
+	// A name corrected in several rows takes the classification of its latest corrected row.
+	latestByKey := make(map[string]entity.Transaction)
+	correctionsByKey := make(map[string]Correction)
+	for index, row := range rows {
+		switch sources[index] {
+		case CategorySourceRule, CategorySourceSplit, CategorySourceSourceCategory:
+			continue
+		}
+		if row.ParentID != "" ||
+			row.Category == assigned[index].Category && row.BudgetGroup == assigned[index].BudgetGroup {
+			continue
+		}
+
+		directionSettings := settings.ForDirection(row.CategoryDirection())
+		name := row.CategorizationName()
+		key := categorizationCacheKey(directionSettings, name)
+		if latest, exists := latestByKey[key]; exists && latest.Date.After(row.Date) {
+			continue
+		}
+		latestByKey[key] = row
+		correctionsByKey[key] = Correction{
+			IngestProfileID:     settings.ID,
+			Name:                name,
+			Category:            row.Category,
+			BudgetGroup:         row.BudgetGroup,
+			AssignedCategory:    assigned[index].Category,
+			AssignedBudgetGroup: assigned[index].BudgetGroup,
+			Direction:           directionSettings.Direction,
+		}
+	}
+
+	corrections := slices.SortedFunc(maps.Values(correctionsByKey), func(a, b Correction) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Direction, b.Direction))
+	})
+	if input.DryRun {
+		return corrections, nil
+	}
+
+	if err := s.learnCorrections(ctx, settings, corrections); err != nil {
+		return nil, fmt.Errorf("save corrections: %w", err)
+	}
+
+	return corrections, nil
+}
+
+// classifiedRows lists the transactions of the profile's monthly tables in the range. Rows with
+// a category or budget group the profile does not configure are left out, since they cannot be
+// learned.
+func (s *Ingest) classifiedRows(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	months []time.Time,
+) ([]entity.Transaction, error) {
+	tables, err := s.sheetProvider.ListTables(ctx, settings.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+
+	tableByTitle := make(map[string]sheet.Table, len(tables))
+	for _, table := range tables {
+		tableByTitle[table.Title] = table
+	}
+
+	var transactions []entity.Transaction
+	for _, month := range months {
+		table, language, exists := transactionTableForMonth(tableByTitle, month, settings.Language)
+		if !exists {
+			continue
+		}
+
+		rows, err := s.existingTransactionRows(ctx, settings.ID, table.ID, language)
+		if err != nil {
+			return nil, fmt.Errorf("table %q: %w", table.Title, err)
+		}
+		for _, row := range rows {
+			classifications := transactionClassifications{
+				Category:    row.transaction.Category,
+				BudgetGroup: row.transaction.BudgetGroup,
+			}
+			if classifications.configured(settings.ForDirection(row.transaction.CategoryDirection())) {
+				transactions = append(transactions, row.transaction)
+			}
+		}
+	}
+
+	return transactions, nil
+}
+
+// withSourceCategories carries the source category of each row over from the open finance
+// transaction it stands for, since the sheet does not store it. Profiles that classify by source
+// category leave out the rows the source does not list in the range, whose categorization cannot
+// be reproduced.
+func (s *Ingest) withSourceCategories(
+	ctx context.Context,
+	settings entity.IngestProfileSettings,
+	input LearnInput,
+	rows []entity.Transaction,
+) ([]entity.Transaction, error) {
+	transactions, err := s.openFinanceAPIProvider.ListTransactionsByIngestProfileID(
+		ctx,
+		settings.ID,
+		input.StartDate,
+		input.EndDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+
+	sourceCategories := make(map[string]string, len(transactions))
+	for _, transaction := range transactions {
+		sourceCategories[transaction.ID()] = transaction.SourceCategory
+	}
+
+	bySourceCategory := classifiesBySourceCategory(settings)
+	kept := make([]entity.Transaction, 0, len(rows))
+	for _, row := range rows {
+		sourceCategory, listed := sourceCategories[row.SourceID()]
+		if !listed && bySourceCategory {
+			continue
+		}
+
+		row.SourceCategory = sourceCategory
+		kept = append(kept, row)
+	}
+
+	return kept, nil
+}
+
+// classifiesBySourceCategory reports whether the source category of a transaction can change its
+// classifications, through the source category mappings or the conditions of a category rule.
+func classifiesBySourceCategory(settings entity.IngestProfileSettings) bool {
+	if len(settings.SourceCategoryMappings) > 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(settings.CategoryRules, func(rule entity.CategoryRule) bool {
+		return len(rule.SourceCategories) > 0
+	})
+}
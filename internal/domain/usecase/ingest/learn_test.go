@@ -0,0 +1,257 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/pkg/validator"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/mockgpt"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore/mockkvstore"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/openfinance/mockopenfinance"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/mocksheet"
+)
+
+func TestLearnFindsCorrectionsMadeInTheSheet(t *testing.T) {
+	tests := []struct {
+		name    string
+		dryRun  bool
+		wantSet bool
+	}{
+		{name: "saves corrections", wantSet: true},
+		{name: "dry run", dryRun: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+			rows := []entity.Transaction{
+				{ExternalID: "1", Name: "Store", Category: entity.DefaultFallbackCategory, Date: date.AddDate(0, 0, -5)},
+				{ExternalID: "2", Name: "Store", Category: "Food", Date: date},
+				{ExternalID: "3", Name: "Market", Category: "Food", Date: date},
+				{ExternalID: "4", Name: "Uber", Category: "Food", Date: date},
+				{ExternalID: "5", Name: "Travel", Category: "Unconfigured", Date: date},
+				{ExternalID: "6", Name: "Bakery", Category: "Food", Date: date},
+				{ExternalID: "7", Name: "Cafe", Category: "Food", Date: date},
+				{ExternalID: "8", Name: "Pharmacy", Category: "Food", Date: date},
+			}
+			records := make([]sheet.Record, 0, len(rows))
+			for _, row := range rows {
+				records = append(records, sheet.Record{
+					ID:  row.ExternalID,
+					Row: transactionToRow(row, entity.LanguageEnglish),
+				})
+			}
+
+			store := mocksheet.NewMockSheet(t)
+			store.EXPECT().
+				ListTables(mock.Anything, "ingest-profile").
+				Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+				Once()
+			store.EXPECT().ListRows(mock.Anything, "ingest-profile", "august").Return(records, nil).Once()
+
+			// Cafe is no longer listed by the source, so its source category is unknown.
+			source := mockopenfinance.NewMockOpenFinance(t)
+			source.EXPECT().
+				ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
+				Return([]entity.Transaction{
+					{ExternalID: "1", Name: "Store"},
+					{ExternalID: "2", Name: "Store"},
+					{ExternalID: "3", Name: "Market"},
+					{ExternalID: "4", Name: "Uber"},
+					{ExternalID: "6", Name: "Bakery", SourceCategory: "Groceries"},
+					{ExternalID: "8", Name: "Pharmacy"},
+				}, nil).
+				Once()
+
+			settings := testIngestProfileSettings("ingest-profile")
+			settings.CategoryRules = []entity.CategoryRule{{Name: "Uber", Category: entity.DefaultFallbackCategory}}
+			settings.SourceCategoryMappings = map[string]entity.Category{"Groceries": "Food"}
+			cacheEntry := func(category entity.Category) string {
+				value, err := json.Marshal(cachedClassification{
+					Fingerprint: classificationsFingerprint(settings),
+					Category:    category,
+				})
+				if err != nil {
+					t.Fatalf("encode cache entry: %v", err)
+				}
+
+				return string(value)
+			}
+
+			// The GPT mock expects no calls, and only a saving run may write the cache.
+			stored := map[string]string{}
+			cache := mockkvstore.NewMockKVStore(t)
+			cache.EXPECT().
+				Get(mock.Anything, mock.Anything).
+				Return(map[string]string{
+					"ingest-profile|store":  cacheEntry(entity.DefaultFallbackCategory),
+					"ingest-profile|bakery": cacheEntry(entity.DefaultFallbackCategory),
+				}, nil).
+				Once()
+			if test.wantSet {
+				cache.EXPECT().Persistent().Return(true).Once()
+				cache.EXPECT().
+					Set(mock.Anything, mock.Anything).
+					RunAndReturn(func(_ context.Context, values map[string]string) error {
+						maps.Copy(stored, values)
+
+						return nil
+					}).
+					Once()
+			}
+
+			output, err := NewIngest(
+				validator.NewValidator(),
+				testMaxConcurrentOperations,
+				entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{settings}},
+				noCompanyLookup(t),
+				mockgpt.NewMockGPT(t),
+				cache,
+				store,
+				source,
+			).Learn(t.Context(), LearnInput{StartDate: date, EndDate: date, DryRun: test.dryRun})
+			if err != nil {
+				t.Fatalf("Learn() error = %v", err)
+			}
+
+			// Nothing resolves Pharmacy, so it was assigned the fallback.
+			want := []Correction{
+				{
+					IngestProfileID:  "ingest-profile",
+					Name:             "Pharmacy",
+					Category:         "Food",
+					AssignedCategory: entity.DefaultFallbackCategory,
+				},
+				{
+					IngestProfileID:  "ingest-profile",
+					Name:             "Store",
+					Category:         "Food",
+					AssignedCategory: entity.DefaultFallbackCategory,
+				},
+			}
+			if !reflect.DeepEqual(output.Corrections, want) {
+				t.Fatalf("corrections = %#v, want %#v", output.Corrections, want)
+			}
+
+			if !test.wantSet {
+				return
+			}
+			var entry cachedClassification
+			if err := json.Unmarshal([]byte(stored["ingest-profile|store"]), &entry); err != nil {
+				t.Fatalf("cached store entry = %q: %v", stored["ingest-profile|store"], err)
+			}
+			if !entry.Learned || entry.Category != "Food" || len(stored) != len(want) {
+				t.Fatalf("stored = %#v, want the learned corrections", stored)
+			}
+		})
+	}
+}
+
+func TestLearnComparesUnresolvedNamesWithTheFallback(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	rows := []entity.Transaction{
+		{ExternalID: "1", Name: "Pharmacy", Category: "Food", Date: date},
+		{ExternalID: "2", Name: "Parking", Category: entity.DefaultFallbackCategory, Date: date},
+	}
+	records := make([]sheet.Record, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, sheet.Record{ID: row.ExternalID, Row: transactionToRow(row, entity.LanguageEnglish)})
+	}
+
+	store := mocksheet.NewMockSheet(t)
+	store.EXPECT().
+		ListTables(mock.Anything, "ingest-profile").
+		Return([]sheet.Table{{ID: "august", Title: "Aug 2026"}}, nil).
+		Once()
+	store.EXPECT().ListRows(mock.Anything, "ingest-profile", "august").Return(records, nil).Once()
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "ingest-profile", date, date).
+		Return(rows, nil).
+		Once()
+
+	stored := map[string]string{}
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().Persistent().Return(true).Once()
+	cache.EXPECT().Get(mock.Anything, mock.Anything).Return(map[string]string{}, nil).Once()
+	cache.EXPECT().
+		Set(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, values map[string]string) error {
+			maps.Copy(stored, values)
+
+			return nil
+		}).
+		Once()
+
+	output, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		testSettings("ingest-profile"),
+		noCompanyLookup(t),
+		mockgpt.NewMockGPT(t),
+		cache,
+		store,
+		source,
+	).Learn(t.Context(), LearnInput{StartDate: date, EndDate: date})
+	if err != nil {
+		t.Fatalf("Learn() error = %v", err)
+	}
+
+	want := []Correction{{
+		IngestProfileID:  "ingest-profile",
+		Name:             "Pharmacy",
+		Category:         "Food",
+		AssignedCategory: entity.DefaultFallbackCategory,
+	}}
+	if !reflect.DeepEqual(output.Corrections, want) {
+		t.Fatalf("corrections = %#v, want %#v", output.Corrections, want)
+	}
+	if _, exists := stored["ingest-profile|pharmacy"]; !exists || len(stored) != 1 {
+		t.Fatalf("stored = %#v, want the pharmacy correction", stored)
+	}
+}
+
+func TestLearnRequiresPersistentCacheUnlessDryRun(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().Persistent().Return(false).Once()
+
+	_, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		testSettings("ingest-profile"),
+		noCompanyLookup(t),
+		mockgpt.NewMockGPT(t),
+		cache,
+		mocksheet.NewMockSheet(t),
+		mockopenfinance.NewMockOpenFinance(t),
+	).Learn(t.Context(), LearnInput{StartDate: date, EndDate: date})
+	if err == nil {
+		t.Fatal("Learn() error = nil, want non-persistent cache error")
+	}
+}
+
+func TestLearnRejectsInvalidInput(t *testing.T) {
+	_, err := NewIngest(
+		validator.NewValidator(),
+		testMaxConcurrentOperations,
+		testSettings("ingest-profile"),
+		noCompanyLookup(t),
+		mockgpt.NewMockGPT(t),
+		mockkvstore.NewMockKVStore(t),
+		mocksheet.NewMockSheet(t),
+		mockopenfinance.NewMockOpenFinance(t),
+	).Learn(t.Context(), LearnInput{})
+	if err == nil {
+		t.Fatal("Learn() error = nil")
+	}
+}
@@ -0,0 +1,153 @@
+package ingest
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+)
+
+// minRefundNameWordLength is how long a word two names share must be for a refund to match a
+// purchase, which keeps abbreviations such as "PAG" from pairing unrelated names.
+const minRefundNameWordLength = 4
+
+const hoursPerDay = 24
+
+// matchRefunds pairs the credit card refunds with the purchases they return and applies the
+// profile's refund mode, marking the purchases it changes as refunded. Netted refunds are counted
+// as filtered in the report of their month. Refunds without a purchase are left as they are.
+func matchRefunds(
+	transactions []entity.Transaction,
+	settings entity.IngestProfileSettings,
+	reports monthReports,
+) []entity.Transaction {
+	if settings.RefundMode == entity.RefundModeOff {
+		return transactions
+	}
+
+	var purchases, refunds []int
+	for index, transaction := range transactions {
+		if transaction.PaymentMethod != entity.PaymentMethodCreditCard || transaction.CardLastDigits == nil {
+			continue
+		}
+		if transaction.Direction == entity.TransactionDirectionCredit {
+			refunds = append(refunds, index)
+		} else {
+			purchases = append(purchases, index)
+		}
+	}
+	slices.SortStableFunc(refunds, func(a, b int) int {
+		return transactions[a].Date.Compare(transactions[b].Date)
+	})
+
+	remaining := make(map[int]int64, len(purchases))
+	for _, index := range purchases {
+		remaining[index] = amountCents(transactions[index].Amount)
+	}
+
+	window := time.Duration(settings.RefundWindowDays) * hoursPerDay * time.Hour
+	netted := make(map[int]struct{})
+	for _, refundIndex := range refunds {
+		refund := transactions[refundIndex]
+		refundCents := amountCents(refund.Amount)
+		purchaseIndex, found := refundPurchase(transactions, purchases, remaining, refund, refundCents, window)
+		if !found {
+			continue
+		}
+		remaining[purchaseIndex] -= refundCents
+
+		purchase := &transactions[purchaseIndex]
+		purchase.Refunded = true
+		switch settings.RefundMode {
+		case entity.RefundModeNet:
+			purchase.Amount = float64(remaining[purchaseIndex]) / 100
+			netted[refundIndex] = struct{}{}
+			reports.month(refund.Date).Filtered[FilterReasonRefund]++
+		case entity.RefundModeMark, entity.RefundModeNegative:
+			transactions[refundIndex].RefundMatchID = purchase.ID()
+			if purchase.RefundMatchID == "" {
+				purchase.RefundMatchID = refund.ID()
+			}
+			if settings.RefundMode == entity.RefundModeNegative {
+				transactions[refundIndex].Amount = -refund.Amount
+			}
+		}
+	}
+	if len(netted) == 0 {
+		return transactions
+	}
+
+	kept := make([]entity.Transaction, 0, len(transactions)-len(netted))
+	for index, transaction := range transactions {
+		if _, exists := netted[index]; !exists {
+			kept = append(kept, transaction)
+		}
+	}
+
+	return kept
+}
+
+// refundPurchase finds the purchase a refund returns among the purchases on the same card dated
+// within the window before it, whose remaining amount covers the refund and whose name resembles
+// it. A purchase of exactly the refunded amount wins over a partial one, then the latest one.
+func refundPurchase(
+	transactions []entity.Transaction,
+	purchases []int,
+	remaining map[int]int64,
+	refund entity.Transaction,
+	refundCents int64,
+	window time.Duration,
+) (int, bool) {
+	best, found := 0, false
+	for _, index := range purchases {
+		purchase := transactions[index]
+		if *purchase.CardLastDigits != *refund.CardLastDigits ||
+			purchase.Date.After(refund.Date) || refund.Date.Sub(purchase.Date) > window ||
+			remaining[index] < refundCents || !similarRefundNames(purchase.Name, refund.Name) {
+			continue
+		}
+
+		if !found {
+			best, found = index, true
+
+			continue
+		}
+		exact, bestExact := remaining[index] == refundCents, remaining[best] == refundCents
+		if exact && !bestExact || exact == bestExact && purchase.Date.After(transactions[best].Date) {
+			best = index
+		}
+	}
+
+	return best, found
+}
+
+// similarRefundNames reports whether a purchase and a refund share a word, ignoring case and
+// punctuation, as card statements usually name a refund after its purchase, such as "ESTORNO
+// AMAZON" for "AMAZON MARKETPLACE".
+func similarRefundNames(purchaseName, refundName string) bool {
+	refundWords := refundNameWords(refundName)
+	for _, word := range refundNameWords(purchaseName) {
+		if slices.Contains(refundWords, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func refundNameWords(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return slices.DeleteFunc(words, func(word string) bool {
+		return len([]rune(word)) < minRefundNameWordLength
+	})
+}
+
+func amountCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,142 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+)
+
+func TestMatchRefunds(t *testing.T) {
+	card, otherCard := "1234", "9876"
+	date := time.Date(2026, time.August, 5, 12, 0, 0, 0, time.UTC)
+	purchase := func(id, name string, amount float64, daysBefore int) entity.Transaction {
+		return entity.Transaction{
+			ExternalID:     id,
+			Name:           name,
+			Amount:         amount,
+			PaymentMethod:  entity.PaymentMethodCreditCard,
+			CardLastDigits: &card,
+			Direction:      entity.TransactionDirectionDebit,
+			Date:           date.AddDate(0, 0, -daysBefore),
+		}
+	}
+	refund := func(id, name string, amount float64) entity.Transaction {
+		transaction := purchase(id, name, amount, 0)
+		transaction.Direction = entity.TransactionDirectionCredit
+
+		return transaction
+	}
+
+	tests := []struct {
+		name         string
+		transactions []entity.Transaction
+		mode         entity.RefundMode
+		want         []entity.Transaction
+		wantNetted   int
+	}{
+		{
+			name: "off",
+			transactions: []entity.Transaction{
+				purchase("purchase", "AMAZON MARKETPLACE", 100, 3),
+				refund("refund", "ESTORNO AMAZON", 100),
+			},
+			want: []entity.Transaction{
+				purchase("purchase", "AMAZON MARKETPLACE", 100, 3),
+				refund("refund", "ESTORNO AMAZON", 100),
+			},
+		},
+		{
+			name: "net partial refund",
+			transactions: []entity.Transaction{
+				purchase("purchase", "AMAZON MARKETPLACE", 100, 3),
+				refund("refund", "ESTORNO AMAZON", 40),
+			},
+			mode:       entity.RefundModeNet,
+			want:       []entity.Transaction{purchase("purchase", "AMAZON MARKETPLACE", 60, 3)},
+			wantNetted: 1,
+		},
+		{
+			name: "mark prefers the exact amount",
+			transactions: []entity.Transaction{
+				purchase("larger", "AMAZON MARKETPLACE", 100, 1),
+				purchase("exact", "AMAZON MARKETPLACE", 40, 3),
+				refund("refund", "ESTORNO AMAZON", 40),
+			},
+			mode: entity.RefundModeMark,
+			want: func() []entity.Transaction {
+				exact := purchase("exact", "AMAZON MARKETPLACE", 40, 3)
+				exact.RefundMatchID = "refund"
+				matched := refund("refund", "ESTORNO AMAZON", 40)
+				matched.RefundMatchID = "exact"
+
+				return []entity.Transaction{purchase("larger", "AMAZON MARKETPLACE", 100, 1), exact, matched}
+			}(),
+		},
+		{
+			name: "negative",
+			transactions: []entity.Transaction{
+				purchase("purchase", "Netflix.com", 55.9, 10),
+				refund("refund", "NETFLIX ESTORNO", 55.9),
+			},
+			mode: entity.RefundModeNegative,
+			want: func() []entity.Transaction {
+				matchedPurchase := purchase("purchase", "Netflix.com", 55.9, 10)
+				matchedPurchase.RefundMatchID = "refund"
+				matched := refund("refund", "NETFLIX ESTORNO", -55.9)
+				matched.RefundMatchID = "purchase"
+
+				return []entity.Transaction{matchedPurchase, matched}
+			}(),
+		},
+		{
+			name: "unmatched refunds",
+			transactions: []entity.Transaction{
+				purchase("other name", "UBER TRIP", 100, 1),
+				purchase("too old", "AMAZON MARKETPLACE", 100, 31),
+				purchase("too small", "AMAZON MARKETPLACE", 10, 1),
+				func() entity.Transaction {
+					transaction := purchase("other card", "AMAZON MARKETPLACE", 100, 1)
+					transaction.CardLastDigits = &otherCard
+
+					return transaction
+				}(),
+				refund("refund", "ESTORNO AMAZON", 40),
+			},
+			mode: entity.RefundModeNet,
+			want: []entity.Transaction{
+				purchase("other name", "UBER TRIP", 100, 1),
+				purchase("too old", "AMAZON MARKETPLACE", 100, 31),
+				purchase("too small", "AMAZON MARKETPLACE", 10, 1),
+				func() entity.Transaction {
+					transaction := purchase("other card", "AMAZON MARKETPLACE", 100, 1)
+					transaction.CardLastDigits = &otherCard
+
+					return transaction
+				}(),
+				refund("refund", "ESTORNO AMAZON", 40),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settings := entity.IngestProfileSettings{RefundMode: test.mode, RefundWindowDays: 30}
+			reports := monthReports{}
+
+			got := matchRefunds(test.transactions, settings, reports)
+			if len(got) != len(test.want) {
+				t.Fatalf("matchRefunds() = %#v, want %#v", got, test.want)
+			}
+			for index := range got {
+				if got[index].ID() != test.want[index].ID() || got[index].Amount != test.want[index].Amount ||
+					got[index].RefundMatchID != test.want[index].RefundMatchID {
+					t.Fatalf("transaction %d = %#v, want %#v", index, got[index], test.want[index])
+				}
+			}
+			if netted := reports.month(date).Filtered[FilterReasonRefund]; netted != test.wantNetted {
+				t.Fatalf("netted refunds = %d, want %d", netted, test.wantNetted)
+			}
+		})
+	}
+}
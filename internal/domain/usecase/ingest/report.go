@@ -0,0 +1,175 @@
+package ingest
+
+import (
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+)
+
+// CategorySource tells how a transaction got its category.
+type CategorySource string
+
+const (
+	// CategorySourceSplit marks a transaction one of the profile's split rules divided into parts,
+	// each with the category the rule gives it.
+	CategorySourceSplit CategorySource = "split"
+	// CategorySourceRule marks a category set by one of the profile's category rules.
+	CategorySourceRule CategorySource = "rule"
+	// CategorySourceSourceCategory marks a category the profile's source category mappings set from
+	// the category the open finance provider assigned.
+	CategorySourceSourceCategory CategorySource = "source_category"
+	// CategorySourceCorrection marks a category learned from a correction made by hand in the sheet.
+	CategorySourceCorrection CategorySource = "correction"
+	// CategorySourceCache marks a category GPT gave the same name in an earlier run.
+	CategorySourceCache CategorySource = "cache"
+	// CategorySourceMapping marks a category that matches the profile's mapping for the name.
+	CategorySourceMapping CategorySource = "mapping"
+	CategorySourceGPT     CategorySource = "gpt"
+	// CategorySourceHeuristic marks a category the offline heuristic categorizer chose.
+	CategorySourceHeuristic CategorySource = "heuristic"
+	// CategorySourceFallback marks the fallback category, whether GPT chose it or gave no valid
+	// answer.
+	CategorySourceFallback CategorySource = "fallback"
+)
+
+// CategoryConfidence tells how far a category can be trusted without checking it by hand.
+type CategoryConfidence string
+
+const (
+	// CategoryConfidenceHigh marks categories the user configured or corrected.
+	CategoryConfidenceHigh CategoryConfidence = "high"
+	// CategoryConfidenceMedium marks categories GPT chose, in this run or an earlier one.
+	CategoryConfidenceMedium CategoryConfidence = "medium"
+	// CategoryConfidenceLow marks offline guesses and fallbacks, which need a review.
+	CategoryConfidenceLow CategoryConfidence = "low"
+)
+
+// Confidence tells how far a category from the source can be trusted.
+func (s CategorySource) Confidence() CategoryConfidence {
+	switch s {
+	case CategorySourceSplit,
+		CategorySourceRule,
+		CategorySourceSourceCategory,
+		CategorySourceCorrection,
+		CategorySourceMapping:
+		return CategoryConfidenceHigh
+	case CategorySourceCache, CategorySourceGPT:
+		return CategoryConfidenceMedium
+	default:
+		return CategoryConfidenceLow
+	}
+}
+
+// ProfileStatus tells how far the ingest run of a profile got.
+type ProfileStatus string
+
+const (
+	ProfileStatusSucceeded ProfileStatus = "succeeded"
+	// ProfileStatusPartial marks a profile where some months failed while others were written.
+	ProfileStatusPartial ProfileStatus = "partial"
+	ProfileStatusFailed  ProfileStatus = "failed"
+)
+
+// ProfileReport summarizes an ingest run for one profile, with one entry per month in the range.
+type ProfileReport struct {
+	IngestProfileID string
+	Status          ProfileStatus
+	// Err is the error that stopped the profile, or the joined errors of its failed months.
+	Err    error
+	Months []MonthReport
+}
+
+// MonthReport counts what happened to the transactions of one month. Fetched transactions are
+// either filtered out or categorized. Categorized ones are then skipped as duplicates, inserted,
+// or used to update their row, and Failed counts the inserts and updates the sheet rejected.
+// Archived counts the rows of vanished transactions that were pruned. A split transaction counts
+// once until it is categorized, and once per part from then on.
+type MonthReport struct {
+	// Month is the first day of the month.
+	Month time.Time
+	// Title is the title of the monthly table. It is empty when the table could not be prepared.
+	Title       string
+	Fetched     int
+	Filtered    map[FilterReason]int
+	Renamed     int
+	Categorized map[CategorySource]int
+	Duplicates  int
+	Inserted    int
+	Updated     int
+	Archived    int
+	Failed      int
+	// Err is the error that stopped the month, which is the profile's error when the profile
+	// stopped before reaching its months. The other months of the profile still run.
+	Err error
+}
+
+// monthReports collects the reports of a profile by month while transactions go through the run.
+type monthReports map[transactionMonth]*MonthReport
+
+func (r monthReports) month(date time.Time) *MonthReport {
+	key := newTransactionMonth(date)
+	report, exists := r[key]
+	if !exists {
+		report = &MonthReport{
+			Filtered:    make(map[FilterReason]int),
+			Categorized: make(map[CategorySource]int),
+		}
+		r[key] = report
+	}
+
+	return report
+}
+
+// profileReport lists the reports of the given months, leaving out transactions dated elsewhere.
+// The profile failed when err is set and no month was written; it is partial when only some
+// months failed.
+func (r monthReports) profileReport(ingestProfileID string, months []time.Time, err error) ProfileReport {
+	report := ProfileReport{
+		IngestProfileID: ingestProfileID,
+		Status:          ProfileStatusSucceeded,
+		Err:             err,
+		Months:          make([]MonthReport, 0, len(months)),
+	}
+	failedMonths := 0
+	for _, month := range months {
+		monthReport := *r.month(month)
+		monthReport.Month = month
+		if monthReport.Err != nil {
+			failedMonths++
+		}
+		report.Months = append(report.Months, monthReport)
+	}
+	if err != nil && failedMonths == 0 {
+		// The profile stopped before reaching its months, so none of them was written.
+		for index := range report.Months {
+			report.Months[index].Err = err
+		}
+		failedMonths = len(report.Months)
+	}
+
+	if err != nil {
+		report.Status = ProfileStatusFailed
+		if failedMonths > 0 && failedMonths < len(months) {
+			report.Status = ProfileStatusPartial
+		}
+	}
+
+	return report
+}
+
+func categorySource(settings entity.IngestProfileSettings, transaction entity.Transaction) CategorySource {
+	if transaction.Category == settings.Fallback {
+		return CategorySourceFallback
+	}
+
+	category, mapped := settings.Mappings[transaction.CategorizationName()]
+	if mapped && category == transaction.Category {
+		return CategorySourceMapping
+	}
+
+	if settings.Categorizer == entity.CategorizerHeuristic {
+		return CategorySourceHeuristic
+	}
+
+	return CategorySourceGPT
+}
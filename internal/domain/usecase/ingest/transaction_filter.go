@@ -2,33 +2,59 @@ package ingest
 
 import (
 	"slices"
-	"strings"
 
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
 )
 
+// FilterReason tells why a transaction was left out of the sheet.
+type FilterReason string
+
 const (
-	investmentCategory         = "Investments"
-	samePersonTransferCategory = "Same person transfer"
-	creditCardBillPayment      = "Pagamento de fatura"
+	FilterReasonNone               FilterReason = ""
+	FilterReasonCredit             FilterReason = "credit"
+	FilterReasonInvestment         FilterReason = entity.FilterReasonInvestment
+	FilterReasonBillPayment        FilterReason = entity.FilterReasonBillPayment
+	FilterReasonSamePersonTransfer FilterReason = entity.FilterReasonSamePersonTransfer
+	// FilterReasonRefund marks a refund netted into the purchase it returns.
+	FilterReasonRefund FilterReason = "refund"
 )
 
+// filterTransactions drops the transactions that do not belong in the profile's sheet, counting them
+// by reason in the report of their month.
 func filterTransactions(
 	transactions []entity.Transaction,
-	ignoreSamePersonTransfers bool,
+	settings entity.IngestProfileSettings,
+	reports monthReports,
 ) []entity.Transaction {
 	return slices.DeleteFunc(transactions, func(transaction entity.Transaction) bool {
-		return shouldIgnoreTransaction(transaction, ignoreSamePersonTransfers)
+		reason := transactionFilterReason(transaction, settings)
+		if reason == FilterReasonNone {
+			return false
+		}
+
+		reports.month(transaction.Date).Filtered[reason]++
+
+		return true
 	})
 }
 
-func shouldIgnoreTransaction(
+// transactionFilterReason tells why the transaction does not belong in the profile's sheet. Credits
+// are left out unless the profile includes them or they are refunds paired with their purchase,
+// and otherwise the first filter rule that matches gives the reason.
+func transactionFilterReason(
 	transaction entity.Transaction,
-	ignoreSamePersonTransfers bool,
-) bool {
-	return transaction.Direction == entity.TransactionDirectionCredit ||
-		transaction.Category == investmentCategory ||
-		strings.Contains(transaction.Name, "Aplicação") ||
-		transaction.Name == creditCardBillPayment ||
-		ignoreSamePersonTransfers && transaction.Category == samePersonTransferCategory
+	settings entity.IngestProfileSettings,
+) FilterReason {
+	if transaction.Direction == entity.TransactionDirectionCredit && !settings.IncludeCredits &&
+		transaction.RefundMatchID == "" {
+		return FilterReasonCredit
+	}
+
+	for _, rule := range settings.FilterRules {
+		if rule.Matches(transaction) {
+			return FilterReason(rule.Reason)
+		}
+	}
+
+	return FilterReasonNone
 }
@@ -2,46 +2,95 @@ package ingest
 
 import (
 	"testing"
+	"time"
 
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
 )
 
-func TestShouldIgnoreTransaction(t *testing.T) {
+func TestTransactionFilterReason(t *testing.T) {
 	tests := []struct {
-		name                      string
-		transaction               entity.Transaction
-		ignoreSamePersonTransfers bool
-		want                      bool
+		name        string
+		transaction entity.Transaction
+		settings    entity.IngestProfileSettings
+		want        FilterReason
 	}{
 		{
 			name:        "incoming credit",
 			transaction: entity.Transaction{Direction: entity.TransactionDirectionCredit},
-			want:        true,
+			want:        FilterReasonCredit,
+		},
+		{
+			name: "matched refund",
+			transaction: entity.Transaction{
+				Direction:     entity.TransactionDirectionCredit,
+				RefundMatchID: "purchase",
+			},
+		},
+		{
+			name:        "included credit",
+			transaction: entity.Transaction{Direction: entity.TransactionDirectionCredit},
+			settings:    entity.IngestProfileSettings{IncludeCredits: true},
 		},
 		{
 			name:        "investment category",
-			transaction: entity.Transaction{Category: investmentCategory},
-			want:        true,
+			transaction: entity.Transaction{SourceCategory: "Investments"},
+			settings:    entity.IngestProfileSettings{FilterRules: entity.DefaultFilterRules(false)},
+			want:        FilterReasonInvestment,
 		},
 		{
 			name:        "investment description",
 			transaction: entity.Transaction{Name: "Aplicação automática"},
-			want:        true,
+			settings:    entity.IngestProfileSettings{FilterRules: entity.DefaultFilterRules(false)},
+			want:        FilterReasonInvestment,
 		},
 		{
 			name:        "credit card bill payment",
-			transaction: entity.Transaction{Name: creditCardBillPayment},
-			want:        true,
+			transaction: entity.Transaction{Name: "Pagamento de fatura"},
+			settings:    entity.IngestProfileSettings{FilterRules: entity.DefaultFilterRules(false)},
+			want:        FilterReasonBillPayment,
 		},
 		{
-			name:                      "configured same-person transfer",
-			transaction:               entity.Transaction{Category: samePersonTransferCategory},
-			ignoreSamePersonTransfers: true,
-			want:                      true,
+			name:        "configured same-person transfer",
+			transaction: entity.Transaction{SourceCategory: "Same person transfer"},
+			settings:    entity.IngestProfileSettings{FilterRules: entity.DefaultFilterRules(true)},
+			want:        FilterReasonSamePersonTransfer,
 		},
 		{
 			name:        "allowed same-person transfer",
-			transaction: entity.Transaction{Category: samePersonTransferCategory},
+			transaction: entity.Transaction{SourceCategory: "Same person transfer"},
+			settings:    entity.IngestProfileSettings{FilterRules: entity.DefaultFilterRules(false)},
+		},
+		{
+			name: "custom rule",
+			transaction: entity.Transaction{
+				Name:          "Transferência para poupança",
+				AccountID:     "savings",
+				PaymentMethod: entity.PaymentMethodPix,
+				Direction:     entity.TransactionDirectionDebit,
+				Amount:        500,
+			},
+			settings: entity.IngestProfileSettings{FilterRules: []entity.FilterRule{
+				{Reason: "savings", AccountIDs: []string{"checking"}},
+				{
+					Reason:         "savings",
+					AccountIDs:     []string{"savings"},
+					PaymentMethods: []entity.PaymentMethod{entity.PaymentMethodPix},
+					Directions:     []entity.TransactionDirection{entity.TransactionDirectionDebit},
+					MinAmount:      new(100.0),
+				},
+			}},
+			want: "savings",
+		},
+		{
+			name:        "custom rule below its minimum amount",
+			transaction: entity.Transaction{AccountID: "savings", Amount: 50},
+			settings: entity.IngestProfileSettings{FilterRules: []entity.FilterRule{
+				{Reason: "savings", AccountIDs: []string{"savings"}, MinAmount: new(100.0)},
+			}},
+		},
+		{
+			name:        "no filter rules",
+			transaction: entity.Transaction{Name: "Pagamento de fatura"},
 		},
 		{
 			name:        "expense",
@@ -51,9 +100,9 @@ func TestShouldIgnoreTransaction(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got := shouldIgnoreTransaction(test.transaction, test.ignoreSamePersonTransfers)
+			got := transactionFilterReason(test.transaction, test.settings)
 			if got != test.want {
-				t.Fatalf("shouldIgnoreTransaction() = %t, want %t", got, test.want)
+				t.Fatalf("transactionFilterReason() = %q, want %q", got, test.want)
 			}
 		})
 	}
@@ -62,12 +111,19 @@ func TestShouldIgnoreTransaction(t *testing.T) {
 func TestFilterTransactions(t *testing.T) {
 	transactions := []entity.Transaction{
 		{Name: "Market"},
-		{Name: "Transfer", Category: samePersonTransferCategory},
+		{Name: "Pagamento de fatura"},
+		{Name: "Transfer", SourceCategory: "Same person transfer"},
 		{Name: "Deposit", Direction: entity.TransactionDirectionCredit},
 	}
 
-	filtered := filterTransactions(transactions, false)
+	reports := monthReports{}
+	settings := entity.IngestProfileSettings{FilterRules: entity.DefaultFilterRules(false)}
+	filtered := filterTransactions(transactions, settings, reports)
 	if len(filtered) != 2 || filtered[0].Name != "Market" || filtered[1].Name != "Transfer" {
 		t.Fatalf("filterTransactions() = %#v", filtered)
 	}
+	got := reports.month(time.Time{}).Filtered
+	if len(got) != 2 || got[FilterReasonCredit] != 1 || got[FilterReasonBillPayment] != 1 {
+		t.Fatalf("filtered counts = %#v", got)
+	}
 }
@@ -2,6 +2,7 @@ package ingest
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
@@ -20,15 +21,6 @@ func transactionTableDefinition(
 	localization := transactionTableLocalizationFor(settings.Language)
 	columns := localization.columns
 
-	categoryOptions := make([]sheet.SelectOption, 0, len(settings.Categories))
-	for _, category := range settings.Categories {
-		categoryOptions = append(
-			categoryOptions,
-			sheet.NewSelectOption(string(category)).
-				Color(settings.ColorsByCategory[category]),
-		)
-	}
-
 	paymentMethodOptions := make([]sheet.SelectOption, 0, len(entity.PaymentMethods))
 	for _, paymentMethod := range entity.PaymentMethods {
 		paymentMethodOptions = append(
@@ -41,22 +33,88 @@ func transactionTableDefinition(
 	definition := sheet.NewTable(title).
 		SetIcon(transactionTableIcon).
 		AddColumn(sheet.NewTitleColumn(columns.name)).
-		AddColumn(sheet.NewSelectColumn(columns.category).Options(categoryOptions...))
+		AddColumn(categoryTableColumn(settings, settings.Language))
 	if budgetGroupColumn, enabled := budgetGroupTableColumn(settings, settings.Language); enabled {
 		definition = definition.AddColumn(budgetGroupColumn)
 	}
+	if needsReviewColumn, enabled := needsReviewTableColumn(settings, settings.Language); enabled {
+		definition = definition.AddColumn(needsReviewColumn)
+	}
 
-	return definition.
-		AddColumn(
-			sheet.NewNumberColumn(columns.amount).
-				Currency(transactionCurrency),
-		).
+	definition = definition.AddColumn(
+		sheet.NewNumberColumn(columns.amount).
+			Currency(transactionCurrency),
+	)
+	if directionColumn, enabled := directionTableColumn(settings, settings.Language); enabled {
+		definition = definition.AddColumn(directionColumn)
+	}
+	definition = definition.
 		AddColumn(
 			sheet.NewSelectColumn(columns.paymentMethod).
 				Options(paymentMethodOptions...),
 		).
 		AddColumn(sheet.NewTextColumn(columns.cardLastDigits)).
-		AddColumn(sheet.NewDateColumn(columns.date))
+		AddColumn(sheet.NewDateColumn(columns.date)).
+		AddColumn(externalIDTableColumn(settings.Language))
+	if parentIDColumn, enabled := parentIDTableColumn(settings, settings.Language); enabled {
+		definition = definition.AddColumn(parentIDColumn)
+	}
+	if refundMatchIDColumn, enabled := refundMatchIDTableColumn(settings, settings.Language); enabled {
+		definition = definition.AddColumn(refundMatchIDColumn)
+	}
+	for _, column := range installmentTableColumns(settings, settings.Language) {
+		definition = definition.AddColumn(column)
+	}
+
+	return definition
+}
+
+// categoryTableColumn offers the profile's categories, along with the income categories when it
+// includes credits.
+func categoryTableColumn(settings entity.IngestProfileSettings, language entity.Language) sheet.SelectColumn {
+	categories := slices.Concat(settings.Categories, settings.IncomeCategories)
+	options := make([]sheet.SelectOption, 0, len(categories))
+	for _, category := range categories {
+		options = append(
+			options,
+			sheet.NewSelectOption(string(category)).
+				Color(settings.ColorsByCategory[category]),
+		)
+	}
+
+	columns := transactionTableLocalizationFor(language).columns
+
+	return sheet.NewSelectColumn(columns.category).Options(options...)
+}
+
+// directionTableColumn tells expenses from income, for profiles that include credits.
+func directionTableColumn(
+	settings entity.IngestProfileSettings,
+	language entity.Language,
+) (sheet.SelectColumn, bool) {
+	if !settings.IncludeCredits {
+		return sheet.SelectColumn{}, false
+	}
+
+	localization := transactionTableLocalizationFor(language)
+	options := make([]sheet.SelectOption, 0, len(entity.TransactionDirections))
+	for _, direction := range entity.TransactionDirections {
+		options = append(
+			options,
+			sheet.NewSelectOption(localization.directionLabels[direction]).
+				Color(entity.TransactionDirectionColors[direction]),
+		)
+	}
+
+	return sheet.NewSelectColumn(localization.columns.direction).Options(options...), true
+}
+
+// externalIDTableColumn stores the provider transaction ID used for deduplication. Tables created
+// before it existed get it on their next ingest.
+func externalIDTableColumn(language entity.Language) sheet.TextColumn {
+	columns := transactionTableLocalizationFor(language).columns
+
+	return sheet.NewTextColumn(columns.externalID).Hidden()
 }
 
 func budgetGroupTableColumn(
@@ -81,6 +139,65 @@ func budgetGroupTableColumn(
 	return sheet.NewSelectColumn(columns.budgetGroup).Options(options...), true
 }
 
+// needsReviewTableColumn flags the transactions to check by hand, for profiles that opt in to it.
+func needsReviewTableColumn(
+	settings entity.IngestProfileSettings,
+	language entity.Language,
+) (sheet.CheckboxColumn, bool) {
+	if !settings.ReviewColumn {
+		return sheet.CheckboxColumn{}, false
+	}
+
+	columns := transactionTableLocalizationFor(language).columns
+
+	return sheet.NewCheckboxColumn(columns.needsReview), true
+}
+
+// parentIDTableColumn links the parts of split transactions, for profiles with split rules.
+func parentIDTableColumn(
+	settings entity.IngestProfileSettings,
+	language entity.Language,
+) (sheet.TextColumn, bool) {
+	if len(settings.SplitRules) == 0 {
+		return sheet.TextColumn{}, false
+	}
+
+	columns := transactionTableLocalizationFor(language).columns
+
+	return sheet.NewTextColumn(columns.parentID), true
+}
+
+// refundMatchIDTableColumn links refunds and their purchases, for profiles that write refunds as
+// rows of their own.
+func refundMatchIDTableColumn(
+	settings entity.IngestProfileSettings,
+	language entity.Language,
+) (sheet.TextColumn, bool) {
+	if settings.RefundMode != entity.RefundModeMark && settings.RefundMode != entity.RefundModeNegative {
+		return sheet.TextColumn{}, false
+	}
+
+	columns := transactionTableLocalizationFor(language).columns
+
+	return sheet.NewTextColumn(columns.refundMatchID), true
+}
+
+// installmentTableColumns place installments within their purchase, for profiles that opt in to
+// them.
+func installmentTableColumns(settings entity.IngestProfileSettings, language entity.Language) []sheet.Column {
+	if !settings.InstallmentColumns {
+		return nil
+	}
+
+	columns := transactionTableLocalizationFor(language).columns
+
+	return []sheet.Column{
+		sheet.NewNumberColumn(columns.installmentNumber),
+		sheet.NewNumberColumn(columns.totalInstallments),
+		sheet.NewNumberColumn(columns.purchaseAmount).Currency(transactionCurrency),
+	}
+}
+
 func transactionToRow(transaction entity.Transaction, language entity.Language) sheet.Row {
 	localization := transactionTableLocalizationFor(language)
 	columns := localization.columns
@@ -100,6 +217,15 @@ func transactionToRow(transaction entity.Transaction, language entity.Language)
 	if transaction.BudgetGroup != "" {
 		row[columns.budgetGroup] = sheet.SelectCell(transaction.BudgetGroup)
 	}
+	if transaction.ExternalID != "" {
+		row[columns.externalID] = sheet.TextCell(transaction.ExternalID)
+	}
+	if transaction.ParentID != "" {
+		row[columns.parentID] = sheet.TextCell(transaction.ParentID)
+	}
+	if transaction.RefundMatchID != "" {
+		row[columns.refundMatchID] = sheet.TextCell(transaction.RefundMatchID)
+	}
 
 	if paymentMethodLabel := localization.paymentMethodLabels[transaction.PaymentMethod]; paymentMethodLabel != "" {
 		row[columns.paymentMethod] = sheet.SelectCell(paymentMethodLabel)
@@ -108,6 +234,51 @@ func transactionToRow(transaction entity.Transaction, language entity.Language)
 	return row
 }
 
+// transactionToProfileRow maps the transaction like transactionToRow, adding the review checkbox,
+// the direction and the installments when the profile has those columns.
+func transactionToProfileRow(
+	transaction entity.Transaction,
+	language entity.Language,
+	settings entity.IngestProfileSettings,
+) sheet.Row {
+	row := transactionToRow(transaction, language)
+	localization := transactionTableLocalizationFor(language)
+	if settings.ReviewColumn {
+		row[localization.columns.needsReview] = sheet.CheckboxCell(transaction.NeedsReview)
+	}
+	if settings.IncludeCredits {
+		label := localization.directionLabels[transaction.CategoryDirection()]
+		row[localization.columns.direction] = sheet.SelectCell(label)
+	}
+	if settings.InstallmentColumns && transaction.TotalInstallments > 0 {
+		row[localization.columns.installmentNumber] = sheet.NumberCell(transaction.InstallmentNumber)
+		row[localization.columns.totalInstallments] = sheet.NumberCell(transaction.TotalInstallments)
+		row[localization.columns.purchaseAmount] = sheet.NumberCell(transaction.PurchaseAmount)
+	}
+
+	return row
+}
+
+// transactionToUpdatedRow maps the transaction like transactionToProfileRow, leaving out the
+// preserved columns so updating the row keeps what users wrote in them. The review checkbox
+// follows the category, so it is kept along with a preserved category.
+func transactionToUpdatedRow(
+	transaction entity.Transaction,
+	language entity.Language,
+	settings entity.IngestProfileSettings,
+) sheet.Row {
+	row := transactionToProfileRow(transaction, language, settings)
+	columns := transactionTableLocalizationFor(language).columns
+	for _, column := range settings.PreservedColumns {
+		delete(row, columns.named(column))
+		if column == entity.TransactionColumnCategory {
+			delete(row, columns.needsReview)
+		}
+	}
+
+	return row
+}
+
 func rowToTransaction(row sheet.Row, language entity.Language) (entity.Transaction, error) {
 	localization := transactionTableLocalizationFor(language)
 	columns := localization.columns
@@ -155,13 +326,65 @@ func rowToTransaction(row sheet.Row, language entity.Language) (entity.Transacti
 		return entity.Transaction{}, err
 	}
 
+	externalID, err := rowCell[sheet.TextCell](row, columns.externalID, sheet.ColumnTypeText)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	needsReview, err := rowCell[sheet.CheckboxCell](row, columns.needsReview, sheet.ColumnTypeCheckbox)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	parentID, err := rowCell[sheet.TextCell](row, columns.parentID, sheet.ColumnTypeText)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	refundMatchID, err := rowCell[sheet.TextCell](row, columns.refundMatchID, sheet.ColumnTypeText)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	installmentNumber, err := rowCell[sheet.NumberCell](row, columns.installmentNumber, sheet.ColumnTypeNumber)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	totalInstallments, err := rowCell[sheet.NumberCell](row, columns.totalInstallments, sheet.ColumnTypeNumber)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	purchaseAmount, err := rowCell[sheet.NumberCell](row, columns.purchaseAmount, sheet.ColumnTypeNumber)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	directionLabel, err := rowCell[sheet.SelectCell](row, columns.direction, sheet.ColumnTypeSelect)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+	direction, exists := localization.directionsByLabel[string(directionLabel)]
+	if !exists && directionLabel != "" {
+		return entity.Transaction{}, fmt.Errorf("column %q has unknown direction %q", columns.direction, directionLabel)
+	}
+
 	transaction := entity.Transaction{
-		Name:          string(name),
-		Category:      entity.Category(category),
-		BudgetGroup:   entity.BudgetGroup(budgetGroup),
-		Amount:        float64(amount),
-		PaymentMethod: paymentMethod,
-		Date:          time.Time(date),
+		ExternalID:        string(externalID),
+		Name:              string(name),
+		Category:          entity.Category(category),
+		BudgetGroup:       entity.BudgetGroup(budgetGroup),
+		Amount:            float64(amount),
+		PaymentMethod:     paymentMethod,
+		Date:              time.Time(date),
+		Direction:         direction,
+		NeedsReview:       bool(needsReview),
+		ParentID:          string(parentID),
+		RefundMatchID:     string(refundMatchID),
+		InstallmentNumber: int(installmentNumber),
+		TotalInstallments: int(totalInstallments),
+		PurchaseAmount:    float64(purchaseAmount),
 	}
 	if cardLastDigits != "" {
 		value := string(cardLastDigits)
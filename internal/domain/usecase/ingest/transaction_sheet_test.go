@@ -34,6 +34,7 @@ func TestTransactionTableDefinition(t *testing.T) {
 				).Definition(),
 				sheet.NewTextColumn("Card Last Digits").Definition(),
 				sheet.NewDateColumn("Date").Definition(),
+				sheet.NewTextColumn("External ID").Hidden().Definition(),
 			},
 		},
 		{
@@ -55,6 +56,7 @@ func TestTransactionTableDefinition(t *testing.T) {
 				).Definition(),
 				sheet.NewTextColumn("Últimos dígitos do cartão").Definition(),
 				sheet.NewDateColumn("Data").Definition(),
+				sheet.NewTextColumn("ID externo").Hidden().Definition(),
 			},
 		},
 	}
@@ -101,7 +103,7 @@ func TestTransactionTableDefinitionIncludesLocalizedBudgetGroup(t *testing.T) {
 			}
 
 			columns := transactionTableDefinition("Transactions", settings).Columns()
-			if len(columns) != 7 || columns[2].Name() != test.columnName ||
+			if len(columns) != 8 || columns[2].Name() != test.columnName ||
 				columns[2].Type() != sheet.ColumnTypeSelect {
 				t.Fatalf("columns = %#v", columns)
 			}
@@ -115,16 +117,274 @@ func TestTransactionTableDefinitionIncludesLocalizedBudgetGroup(t *testing.T) {
 	}
 }
 
+func TestTransactionTableDefinitionIncludesLocalizedNeedsReview(t *testing.T) {
+	tests := []struct {
+		language   entity.Language
+		columnName string
+	}{
+		{language: entity.LanguageEnglish, columnName: "Needs Review"},
+		{language: entity.LanguagePortugueseBrazil, columnName: "Precisa de revisão"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.language), func(t *testing.T) {
+			settings := entity.IngestProfileSettings{
+				Language:         test.language,
+				ReviewColumn:     true,
+				Categories:       []entity.Category{"Food"},
+				ColorsByCategory: map[entity.Category]entity.Color{"Food": entity.Red},
+			}
+
+			columns := transactionTableDefinition("Transactions", settings).Columns()
+			if len(columns) != 8 || columns[2].Name() != test.columnName ||
+				columns[2].Type() != sheet.ColumnTypeCheckbox {
+				t.Fatalf("columns = %#v", columns)
+			}
+		})
+	}
+}
+
+func TestTransactionTableDefinitionIncludesLocalizedParentID(t *testing.T) {
+	tests := []struct {
+		language   entity.Language
+		columnName string
+	}{
+		{language: entity.LanguageEnglish, columnName: "Parent ID"},
+		{language: entity.LanguagePortugueseBrazil, columnName: "ID de origem"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.language), func(t *testing.T) {
+			settings := entity.IngestProfileSettings{
+				Language:         test.language,
+				Categories:       []entity.Category{"Food"},
+				ColorsByCategory: map[entity.Category]entity.Color{"Food": entity.Red},
+				SplitRules:       []entity.SplitRule{{Name: "Market"}},
+			}
+
+			columns := transactionTableDefinition("Transactions", settings).Columns()
+			if len(columns) != 8 || columns[7].Name() != test.columnName ||
+				columns[7].Type() != sheet.ColumnTypeText {
+				t.Fatalf("columns = %#v", columns)
+			}
+		})
+	}
+}
+
+func TestTransactionTableDefinitionIncludesLocalizedRefundLink(t *testing.T) {
+	tests := []struct {
+		language   entity.Language
+		mode       entity.RefundMode
+		columnName string
+	}{
+		{language: entity.LanguageEnglish, mode: entity.RefundModeMark, columnName: "Refund Link"},
+		{language: entity.LanguagePortugueseBrazil, mode: entity.RefundModeNegative, columnName: "Vínculo de estorno"},
+		{language: entity.LanguageEnglish, mode: entity.RefundModeNet},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.language)+"/"+string(test.mode), func(t *testing.T) {
+			settings := entity.IngestProfileSettings{
+				Language:         test.language,
+				Categories:       []entity.Category{"Food"},
+				ColorsByCategory: map[entity.Category]entity.Color{"Food": entity.Red},
+				RefundMode:       test.mode,
+			}
+
+			columns := transactionTableDefinition("Transactions", settings).Columns()
+			if test.columnName == "" {
+				if len(columns) != 7 {
+					t.Fatalf("columns = %#v", columns)
+				}
+
+				return
+			}
+			if len(columns) != 8 || columns[7].Name() != test.columnName ||
+				columns[7].Type() != sheet.ColumnTypeText {
+				t.Fatalf("columns = %#v", columns)
+			}
+		})
+	}
+}
+
+func TestTransactionTableDefinitionIncludesLocalizedInstallmentColumns(t *testing.T) {
+	tests := []struct {
+		language    entity.Language
+		columnNames []string
+	}{
+		{language: entity.LanguageEnglish, columnNames: []string{"Installment", "Installments", "Purchase Amount"}},
+		{
+			language:    entity.LanguagePortugueseBrazil,
+			columnNames: []string{"Parcela", "Total de parcelas", "Valor da compra"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.language), func(t *testing.T) {
+			settings := entity.IngestProfileSettings{
+				Language:           test.language,
+				Categories:         []entity.Category{"Food"},
+				ColorsByCategory:   map[entity.Category]entity.Color{"Food": entity.Red},
+				InstallmentColumns: true,
+			}
+
+			columns := transactionTableDefinition("Transactions", settings).Columns()
+			if len(columns) != 10 {
+				t.Fatalf("columns = %#v", columns)
+			}
+			for index, name := range test.columnNames {
+				column := columns[7+index]
+				if column.Name() != name || column.Type() != sheet.ColumnTypeNumber {
+					t.Fatalf("column %d = %#v, want %q", 7+index, column, name)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionTableIncludesIncomeCategoriesAndLocalizedDirection(t *testing.T) {
+	tests := []struct {
+		language     entity.Language
+		columnName   string
+		expenseLabel string
+		incomeLabel  string
+	}{
+		{
+			language:     entity.LanguageEnglish,
+			columnName:   "Direction",
+			expenseLabel: "Expense",
+			incomeLabel:  "Income",
+		},
+		{
+			language:     entity.LanguagePortugueseBrazil,
+			columnName:   "Direção",
+			expenseLabel: "Despesa",
+			incomeLabel:  "Receita",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.language), func(t *testing.T) {
+			settings := entity.IngestProfileSettings{
+				Language:         test.language,
+				Categories:       []entity.Category{"Food"},
+				IncludeCredits:   true,
+				IncomeCategories: []entity.Category{"Salary"},
+				ColorsByCategory: map[entity.Category]entity.Color{"Food": entity.Red, "Salary": entity.Green},
+			}
+
+			columns := transactionTableDefinition("Transactions", settings).Columns()
+			if len(columns) != 8 || columns[3].Name() != test.columnName ||
+				columns[3].Type() != sheet.ColumnTypeSelect {
+				t.Fatalf("columns = %#v", columns)
+			}
+			categoryOptions := columns[1].SelectOptions()
+			if len(categoryOptions) != 2 || categoryOptions[1].Name() != "Salary" ||
+				categoryOptions[1].Color() != entity.Green {
+				t.Fatalf("category options = %#v", categoryOptions)
+			}
+			directionOptions := columns[3].SelectOptions()
+			if len(directionOptions) != 2 || directionOptions[0].Name() != test.expenseLabel ||
+				directionOptions[1].Name() != test.incomeLabel || directionOptions[1].Color() != entity.Green {
+				t.Fatalf("direction options = %#v", directionOptions)
+			}
+
+			directionColumn := transactionTableLocalizationFor(test.language).columns.direction
+			for _, transaction := range []entity.Transaction{
+				{Name: "Salary", Category: "Salary", Direction: entity.TransactionDirectionCredit},
+				{Name: "Store", Category: "Food"},
+			} {
+				row := transactionToProfileRow(transaction, test.language, settings)
+				got, err := rowToTransaction(row, test.language)
+				if err != nil {
+					t.Fatalf("rowToTransaction() error = %v", err)
+				}
+				if want := transaction.CategoryDirection(); got.Direction != want {
+					t.Fatalf("direction cell = %#v, read direction %q, want %q", row[directionColumn], got.Direction, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionProfileRowsWriteNeedsReview(t *testing.T) {
+	columns := transactionTableLocalizationFor(entity.LanguageEnglish).columns
+	transaction := entity.Transaction{Name: "Store", Category: "Others", NeedsReview: true}
+	tests := []struct {
+		name     string
+		settings entity.IngestProfileSettings
+		row      func(entity.IngestProfileSettings) sheet.Row
+		want     sheet.Cell
+	}{
+		{
+			name:     "inserted row",
+			settings: entity.IngestProfileSettings{ReviewColumn: true},
+			row: func(settings entity.IngestProfileSettings) sheet.Row {
+				return transactionToProfileRow(transaction, entity.LanguageEnglish, settings)
+			},
+			want: sheet.CheckboxCell(true),
+		},
+		{
+			name:     "profile without review column",
+			settings: entity.IngestProfileSettings{},
+			row: func(settings entity.IngestProfileSettings) sheet.Row {
+				return transactionToProfileRow(transaction, entity.LanguageEnglish, settings)
+			},
+		},
+		{
+			name:     "updated row",
+			settings: entity.IngestProfileSettings{ReviewColumn: true},
+			row: func(settings entity.IngestProfileSettings) sheet.Row {
+				return transactionToUpdatedRow(transaction, entity.LanguageEnglish, settings)
+			},
+			want: sheet.CheckboxCell(true),
+		},
+		{
+			name: "updated row with preserved category",
+			settings: entity.IngestProfileSettings{
+				ReviewColumn:     true,
+				PreservedColumns: []entity.TransactionColumn{entity.TransactionColumnCategory},
+			},
+			row: func(settings entity.IngestProfileSettings) sheet.Row {
+				return transactionToUpdatedRow(transaction, entity.LanguageEnglish, settings)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			row := test.row(test.settings)
+			if row[columns.needsReview] != test.want {
+				t.Fatalf("needs review cell = %#v, want %#v", row[columns.needsReview], test.want)
+			}
+
+			if test.want == nil {
+				return
+			}
+			got, err := rowToTransaction(row, entity.LanguageEnglish)
+			if err != nil || !got.NeedsReview {
+				t.Fatalf("rowToTransaction() = %#v, %v, want needs review", got, err)
+			}
+		})
+	}
+}
+
 func TestTransactionRowRoundTrip(t *testing.T) {
 	cardLastDigits := "1234"
 	transaction := entity.Transaction{
-		Name:           "Store",
-		Category:       "Food",
-		BudgetGroup:    "Lifestyle",
-		Amount:         42.5,
-		PaymentMethod:  entity.PaymentMethodCreditCard,
-		CardLastDigits: &cardLastDigits,
-		Date:           time.Date(2026, time.August, 9, 12, 0, 0, 0, time.UTC),
+		ExternalID:        "transaction-id#1",
+		ParentID:          "transaction-id",
+		RefundMatchID:     "refund-id",
+		Name:              "Store PARC 02/03",
+		Category:          "Food",
+		BudgetGroup:       "Lifestyle",
+		Amount:            42.5,
+		PaymentMethod:     entity.PaymentMethodCreditCard,
+		CardLastDigits:    &cardLastDigits,
+		Date:              time.Date(2026, time.August, 9, 12, 0, 0, 0, time.UTC),
+		InstallmentNumber: 2,
+		TotalInstallments: 3,
+		PurchaseAmount:    127.5,
 	}
 
 	tests := []struct {
@@ -149,14 +409,24 @@ func TestTransactionRowRoundTrip(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			row := transactionToRow(transaction, test.language)
-			if row[test.columns.name] != sheet.TitleCell("Store") ||
+			row := transactionToProfileRow(
+				transaction,
+				test.language,
+				entity.IngestProfileSettings{InstallmentColumns: true},
+			)
+			if row[test.columns.name] != sheet.TitleCell("Store PARC 02/03") ||
 				row[test.columns.category] != sheet.SelectCell("Food") ||
 				row[test.columns.budgetGroup] != sheet.SelectCell("Lifestyle") ||
 				row[test.columns.amount] != sheet.NumberCell(42.5) ||
 				row[test.columns.paymentMethod] != sheet.SelectCell(test.paymentMethodLabel) ||
 				row[test.columns.cardLastDigits] != sheet.TextCell("1234") ||
-				row[test.columns.date] != sheet.DateCell(transaction.Date) {
+				row[test.columns.date] != sheet.DateCell(transaction.Date) ||
+				row[test.columns.externalID] != sheet.TextCell("transaction-id#1") ||
+				row[test.columns.parentID] != sheet.TextCell("transaction-id") ||
+				row[test.columns.refundMatchID] != sheet.TextCell("refund-id") ||
+				row[test.columns.installmentNumber] != sheet.NumberCell(2) ||
+				row[test.columns.totalInstallments] != sheet.NumberCell(3) ||
+				row[test.columns.purchaseAmount] != sheet.NumberCell(127.5) {
 				t.Fatalf("row = %#v", row)
 			}
 
@@ -199,6 +469,9 @@ func TestTransactionToRowOmitsEmptyPaymentMethod(t *testing.T) {
 	if budgetGroup, exists := row[columns.budgetGroup]; exists {
 		t.Fatalf("budget group cell = %#v, want omitted", budgetGroup)
 	}
+	if externalID, exists := row[columns.externalID]; exists {
+		t.Fatalf("external ID cell = %#v, want omitted", externalID)
+	}
 
 	transaction, err := rowToTransaction(row, entity.LanguageEnglish)
 	if err != nil {
@@ -10,32 +10,72 @@ import (
 const monthsPerYear = 12
 
 type transactionTableColumns struct {
-	name           string
-	category       string
-	budgetGroup    string
-	amount         string
-	paymentMethod  string
-	cardLastDigits string
-	date           string
+	name              string
+	category          string
+	budgetGroup       string
+	amount            string
+	paymentMethod     string
+	cardLastDigits    string
+	date              string
+	externalID        string
+	needsReview       string
+	parentID          string
+	direction         string
+	refundMatchID     string
+	installmentNumber string
+	totalInstallments string
+	purchaseAmount    string
+}
+
+// named returns the localized name of a transaction column.
+func (columns transactionTableColumns) named(column entity.TransactionColumn) string {
+	switch column {
+	case entity.TransactionColumnName:
+		return columns.name
+	case entity.TransactionColumnCategory:
+		return columns.category
+	case entity.TransactionColumnBudgetGroup:
+		return columns.budgetGroup
+	case entity.TransactionColumnAmount:
+		return columns.amount
+	case entity.TransactionColumnPaymentMethod:
+		return columns.paymentMethod
+	case entity.TransactionColumnCardLastDigits:
+		return columns.cardLastDigits
+	case entity.TransactionColumnDate:
+		return columns.date
+	default:
+		return ""
+	}
 }
 
 type transactionTableLocalization struct {
 	columns               transactionTableColumns
 	paymentMethodLabels   map[entity.PaymentMethod]string
 	paymentMethodsByLabel map[string]entity.PaymentMethod
+	directionLabels       map[entity.TransactionDirection]string
+	directionsByLabel     map[string]entity.TransactionDirection
 	monthNames            [monthsPerYear]string
 }
 
 var transactionTableLocalizations = map[entity.Language]transactionTableLocalization{
 	entity.LanguageEnglish: newTransactionTableLocalization(
 		transactionTableColumns{
-			name:           "Name",
-			category:       "Category",
-			budgetGroup:    "Budget Group",
-			amount:         "Amount",
-			paymentMethod:  "Payment Method",
-			cardLastDigits: "Card Last Digits",
-			date:           "Date",
+			name:              "Name",
+			category:          "Category",
+			budgetGroup:       "Budget Group",
+			amount:            "Amount",
+			paymentMethod:     "Payment Method",
+			cardLastDigits:    "Card Last Digits",
+			date:              "Date",
+			externalID:        "External ID",
+			needsReview:       "Needs Review",
+			parentID:          "Parent ID",
+			direction:         "Direction",
+			refundMatchID:     "Refund Link",
+			installmentNumber: "Installment",
+			totalInstallments: "Installments",
+			purchaseAmount:    "Purchase Amount",
 		},
 		map[entity.PaymentMethod]string{
 			entity.PaymentMethodBoleto:     "BOLETO",
@@ -43,17 +83,29 @@ var transactionTableLocalizations = map[entity.Language]transactionTableLocaliza
 			entity.PaymentMethodTed:        "TED",
 			entity.PaymentMethodCreditCard: "CREDIT CARD",
 		},
+		map[entity.TransactionDirection]string{
+			entity.TransactionDirectionDebit:  "Expense",
+			entity.TransactionDirectionCredit: "Income",
+		},
 		[monthsPerYear]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
 	),
 	entity.LanguagePortugueseBrazil: newTransactionTableLocalization(
 		transactionTableColumns{
-			name:           "Nome",
-			category:       "Categoria",
-			budgetGroup:    "Grupo do orçamento",
-			amount:         "Valor",
-			paymentMethod:  "Forma de pagamento",
-			cardLastDigits: "Últimos dígitos do cartão",
-			date:           "Data",
+			name:              "Nome",
+			category:          "Categoria",
+			budgetGroup:       "Grupo do orçamento",
+			amount:            "Valor",
+			paymentMethod:     "Forma de pagamento",
+			cardLastDigits:    "Últimos dígitos do cartão",
+			date:              "Data",
+			externalID:        "ID externo",
+			needsReview:       "Precisa de revisão",
+			parentID:          "ID de origem",
+			direction:         "Direção",
+			refundMatchID:     "Vínculo de estorno",
+			installmentNumber: "Parcela",
+			totalInstallments: "Total de parcelas",
+			purchaseAmount:    "Valor da compra",
 		},
 		map[entity.PaymentMethod]string{
 			entity.PaymentMethodBoleto:     "BOLETO",
@@ -61,6 +113,10 @@ var transactionTableLocalizations = map[entity.Language]transactionTableLocaliza
 			entity.PaymentMethodTed:        "TED",
 			entity.PaymentMethodCreditCard: "CARTÃO DE CRÉDITO",
 		},
+		map[entity.TransactionDirection]string{
+			entity.TransactionDirectionDebit:  "Despesa",
+			entity.TransactionDirectionCredit: "Receita",
+		},
 		[monthsPerYear]string{"Jan", "Fev", "Mar", "Abr", "Mai", "Jun", "Jul", "Ago", "Set", "Out", "Nov", "Dez"},
 	),
 }
@@ -68,17 +124,24 @@ var transactionTableLocalizations = map[entity.Language]transactionTableLocaliza
 func newTransactionTableLocalization(
 	columns transactionTableColumns,
 	paymentMethodLabels map[entity.PaymentMethod]string,
+	directionLabels map[entity.TransactionDirection]string,
 	monthNames [monthsPerYear]string,
 ) transactionTableLocalization {
 	paymentMethodsByLabel := make(map[string]entity.PaymentMethod, len(paymentMethodLabels))
 	for paymentMethod, label := range paymentMethodLabels {
 		paymentMethodsByLabel[label] = paymentMethod
 	}
+	directionsByLabel := make(map[string]entity.TransactionDirection, len(directionLabels))
+	for direction, label := range directionLabels {
+		directionsByLabel[label] = direction
+	}
 
 	return transactionTableLocalization{
 		columns:               columns,
 		paymentMethodLabels:   paymentMethodLabels,
 		paymentMethodsByLabel: paymentMethodsByLabel,
+		directionLabels:       directionLabels,
+		directionsByLabel:     directionsByLabel,
 		monthNames:            monthNames,
 	}
 }
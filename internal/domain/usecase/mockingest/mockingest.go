@@ -39,20 +39,29 @@ func (_m *MockIngest) EXPECT() *MockIngest_Expecter {
 }
 
 // Execute provides a mock function for the type MockIngest
-func (_mock *MockIngest) Execute(ctx context.Context, input ingest.IngestInput) error {
+func (_mock *MockIngest) Execute(ctx context.Context, input ingest.IngestInput) (ingest.IngestOutput, error) {
 	ret := _mock.Called(ctx, input)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Execute")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, ingest.IngestInput) error); ok {
+	var r0 ingest.IngestOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ingest.IngestInput) (ingest.IngestOutput, error)); ok {
+		return returnFunc(ctx, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ingest.IngestInput) ingest.IngestOutput); ok {
 		r0 = returnFunc(ctx, input)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(ingest.IngestOutput)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, ingest.IngestInput) error); ok {
+		r1 = returnFunc(ctx, input)
+	} else {
+		r1 = ret.Error(1)
 	}
-	return r0
+	return r0, r1
 }
 
 // MockIngest_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
@@ -85,12 +94,12 @@ func (_c *MockIngest_Execute_Call) Run(run func(ctx context.Context, input inges
 	return _c
 }
 
-func (_c *MockIngest_Execute_Call) Return(err error) *MockIngest_Execute_Call {
-	_c.Call.Return(err)
+func (_c *MockIngest_Execute_Call) Return(ingestOutput ingest.IngestOutput, err error) *MockIngest_Execute_Call {
+	_c.Call.Return(ingestOutput, err)
 	return _c
 }
 
-func (_c *MockIngest_Execute_Call) RunAndReturn(run func(ctx context.Context, input ingest.IngestInput) error) *MockIngest_Execute_Call {
+func (_c *MockIngest_Execute_Call) RunAndReturn(run func(ctx context.Context, input ingest.IngestInput) (ingest.IngestOutput, error)) *MockIngest_Execute_Call {
 	_c.Call.Return(run)
 	return _c
 }
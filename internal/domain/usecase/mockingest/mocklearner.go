@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mockingest
+
+import (
+	"context"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/ingest"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockLearner creates a new instance of MockLearner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLearner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLearner {
+	mock := &MockLearner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLearner is an autogenerated mock type for the CorrectionLearner type
+type MockLearner struct {
+	mock.Mock
+}
+
+type MockLearner_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLearner) EXPECT() *MockLearner_Expecter {
+	return &MockLearner_Expecter{mock: &_m.Mock}
+}
+
+// Learn provides a mock function for the type MockLearner
+func (_mock *MockLearner) Learn(ctx context.Context, input ingest.LearnInput) (ingest.LearnOutput, error) {
+	ret := _mock.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Learn")
+	}
+
+	var r0 ingest.LearnOutput
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ingest.LearnInput) (ingest.LearnOutput, error)); ok {
+		return returnFunc(ctx, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ingest.LearnInput) ingest.LearnOutput); ok {
+		r0 = returnFunc(ctx, input)
+	} else {
+		r0 = ret.Get(0).(ingest.LearnOutput)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, ingest.LearnInput) error); ok {
+		r1 = returnFunc(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLearner_Learn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Learn'
+type MockLearner_Learn_Call struct {
+	*mock.Call
+}
+
+// Learn is a helper method to define mock.On call
+//   - ctx context.Context
+//   - input ingest.LearnInput
+func (_e *MockLearner_Expecter) Learn(ctx interface{}, input interface{}) *MockLearner_Learn_Call {
+	return &MockLearner_Learn_Call{Call: _e.mock.On("Learn", ctx, input)}
+}
+
+func (_c *MockLearner_Learn_Call) Run(run func(ctx context.Context, input ingest.LearnInput)) *MockLearner_Learn_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 ingest.LearnInput
+		if args[1] != nil {
+			arg1 = args[1].(ingest.LearnInput)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLearner_Learn_Call) Return(learnOutput ingest.LearnOutput, err error) *MockLearner_Learn_Call {
+	_c.Call.Return(learnOutput, err)
+	return _c
+}
+
+func (_c *MockLearner_Learn_Call) RunAndReturn(run func(ctx context.Context, input ingest.LearnInput) (ingest.LearnOutput, error)) *MockLearner_Learn_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,184 @@
+package anthropicapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+)
+
+const testConnectionID = "ingest-profile"
+
+type capturedRequest struct {
+	path    string
+	apiKey  string
+	version string
+	body    messagesRequest
+}
+
+func newTestServer(t *testing.T, statusCode int, response string) (*Client, <-chan capturedRequest) {
+	t.Helper()
+
+	requestChannel := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var body messagesRequest
+		if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		requestChannel <- capturedRequest{
+			path:    request.URL.Path,
+			apiKey:  request.Header.Get("x-api-key"),
+			version: request.Header.Get("anthropic-version"),
+			body:    body,
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(statusCode)
+		_, _ = fmt.Fprint(writer, response)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(&config.Env{
+		IngestProfilesFileData: config.IngestProfilesFileData{IngestProfiles: []entity.IngestProfile{
+			{ID: testConnectionID, LLM: &entity.LLMConnection{
+				Provider:    entity.LLMProviderAnthropic,
+				Model:       "claude-sonnet-4-5",
+				Temperature: new(0.0),
+				BaseURL:     server.URL + "/",
+				APIKey:      "anthropic-key",
+			}},
+			{ID: "openai-profile"},
+		}},
+	})
+
+	return client, requestChannel
+}
+
+func TestCreateChatCompletionSendsMessage(t *testing.T) {
+	client, requests := newTestServer(t, http.StatusOK, `{
+		"content":[{"type":"text","text":"hello "},{"type":"text","text":"there"}]
+	}`)
+
+	content, err := client.CreateChatCompletion(
+		t.Context(),
+		testConnectionID,
+		"hello",
+		gpt.WithSystemMessage("be brief"),
+		gpt.WithJSONResponse(),
+	)
+	if err != nil || content != "hello there" {
+		t.Fatalf("CreateChatCompletion() = %q, %v", content, err)
+	}
+
+	request := <-requests
+	if request.path != "/v1/messages" || request.apiKey != "anthropic-key" || request.version != apiVersion {
+		t.Fatalf("request = %#v", request)
+	}
+	body := request.body
+	if body.Model != "claude-sonnet-4-5" || body.MaxTokens != maxTokens ||
+		body.Temperature == nil || *body.Temperature != 0 {
+		t.Fatalf("request body = %#v", body)
+	}
+	if body.System != "be brief\n\n"+jsonResponseInstruction || len(body.Tools) != 0 || body.ToolChoice != nil {
+		t.Fatalf("request body = %#v", body)
+	}
+	if len(body.Messages) != 1 || body.Messages[0] != (messagesMessage{Role: "user", Content: "hello"}) {
+		t.Fatalf("messages = %#v", body.Messages)
+	}
+}
+
+func TestCreateChatCompletionForcesSchemaTool(t *testing.T) {
+	client, requests := newTestServer(t, http.StatusOK, `{
+		"content":[
+			{"type":"text","text":"Recording the answer."},
+			{"type":"tool_use","name":"answer","input":{"Store":"Food"}}
+		]
+	}`)
+
+	content, err := client.CreateChatCompletion(
+		t.Context(),
+		testConnectionID,
+		"hello",
+		gpt.WithJSONSchema("answer", map[string]any{"type": "object"}),
+	)
+	if err != nil || content != `{"Store":"Food"}` {
+		t.Fatalf("CreateChatCompletion() = %q, %v", content, err)
+	}
+
+	body := (<-requests).body
+	if len(body.Tools) != 1 || body.Tools[0].Name != "answer" || body.Tools[0].InputSchema["type"] != "object" {
+		t.Fatalf("tools = %#v", body.Tools)
+	}
+	if body.ToolChoice == nil || *body.ToolChoice != (messagesToolUsage{Type: "tool", Name: "answer"}) {
+		t.Fatalf("tool choice = %#v", body.ToolChoice)
+	}
+}
+
+func TestCreateChatCompletionSendsSystemOnlyCompletionAsUserMessage(t *testing.T) {
+	client, requests := newTestServer(t, http.StatusOK, `{"content":[{"type":"text","text":"done"}]}`)
+
+	if _, err := client.CreateChatCompletion(
+		t.Context(),
+		testConnectionID,
+		"",
+		gpt.WithSystemMessage("hello"),
+	); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	body := (<-requests).body
+	if body.System != "" || len(body.Messages) != 1 || body.Messages[0].Content != "hello" {
+		t.Fatalf("request body = %#v", body)
+	}
+}
+
+func TestCreateChatCompletionRejectsInvalidRequestsAndResponses(t *testing.T) {
+	tests := []struct {
+		name         string
+		connectionID string
+		message      string
+		options      []gpt.ChatCompletionOption
+		statusCode   int
+		response     string
+	}{
+		{name: "unknown connection", connectionID: "openai-profile", message: "hello"},
+		{name: "empty messages", connectionID: testConnectionID},
+		{
+			name:         "API error",
+			connectionID: testConnectionID,
+			message:      "hello",
+			statusCode:   http.StatusBadRequest,
+			response:     `{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`,
+		},
+		{
+			name:         "empty content",
+			connectionID: testConnectionID,
+			message:      "hello",
+			statusCode:   http.StatusOK,
+			response:     `{"content":[]}`,
+		},
+		{
+			name:         "missing tool use",
+			connectionID: testConnectionID,
+			message:      "hello",
+			options:      []gpt.ChatCompletionOption{gpt.WithJSONSchema("answer", map[string]any{})},
+			statusCode:   http.StatusOK,
+			response:     `{"content":[{"type":"text","text":"{}"}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, _ := newTestServer(t, test.statusCode, test.response)
+
+			_, err := client.CreateChatCompletion(t.Context(), test.connectionID, test.message, test.options...)
+			if err == nil {
+				t.Fatal("CreateChatCompletion() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package anthropicapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+)
+
+const (
+	// maxTokens bounds each response while leaving room for the answers of a full categorization
+	// chunk.
+	maxTokens = 16384
+
+	jsonResponseInstruction = "Respond with a single JSON object and nothing else."
+)
+
+type messagesRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Messages    []messagesMessage  `json:"messages"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Tools       []messagesTool     `json:"tools,omitempty"`
+	ToolChoice  *messagesToolUsage `json:"tool_choice,omitempty"`
+}
+
+type messagesMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type messagesTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type messagesToolUsage struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type messagesResponse struct {
+	Content []messagesContentBlock `json:"content"`
+}
+
+type messagesContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// CreateChatCompletion sends the message to the Messages API. A JSON schema is enforced by forcing the
+// model to call a tool whose input follows it, and the tool input is returned as the content.
+func (c *Client) CreateChatCompletion(
+	ctx context.Context,
+	connectionID string,
+	message string,
+	options ...gpt.ChatCompletionOption,
+) (string, error) {
+	conn, ok := c.conns[connectionID]
+	if !ok {
+		return "", errors.New("anthropic connection not found for ingest profile " + connectionID)
+	}
+
+	completionOptions := gpt.NewChatCompletionOptions(options...)
+
+	// The Messages API requires a user turn, so a system-only completion sends it as the user message.
+	system := completionOptions.SystemMessage
+	if message == "" {
+		system, message = "", system
+	}
+	if message == "" {
+		return "", errors.New("chat completion requires at least one message")
+	}
+
+	requestData := messagesRequest{
+		Model:       conn.model,
+		MaxTokens:   maxTokens,
+		System:      system,
+		Messages:    []messagesMessage{{Role: "user", Content: message}},
+		Temperature: conn.temperature,
+	}
+	switch {
+	case completionOptions.JSONSchema != nil:
+		requestData.Tools = []messagesTool{{
+			Name:        completionOptions.JSONSchema.Name,
+			Description: "Records the answer.",
+			InputSchema: completionOptions.JSONSchema.Schema,
+		}}
+		requestData.ToolChoice = &messagesToolUsage{Type: "tool", Name: completionOptions.JSONSchema.Name}
+	case completionOptions.JSONResponse:
+		requestData.System = strings.TrimSpace(requestData.System + "\n\n" + jsonResponseInstruction)
+	}
+
+	res, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("x-api-key", conn.apiKey).
+		SetBody(requestData).
+		Post(conn.baseURL + "/v1/messages")
+	if err != nil {
+		return "", fmt.Errorf("create message: %w", err)
+	}
+
+	body := res.Body()
+	if res.IsError() {
+		return "", fmt.Errorf("create message failed with response %s", body)
+	}
+
+	data := messagesResponse{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("unmarshal message: %w", err)
+	}
+
+	if completionOptions.JSONSchema != nil {
+		for _, block := range data.Content {
+			if block.Type == "tool_use" && block.Name == completionOptions.JSONSchema.Name {
+				return string(block.Input), nil
+			}
+		}
+
+		return "", errors.New("message returned no tool use")
+	}
+
+	var content strings.Builder
+	for _, block := range data.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	if content.Len() == 0 {
+		return "", errors.New("message returned empty content")
+	}
+
+	return content.String(), nil
+}
@@ -0,0 +1,120 @@
+package geminiapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+)
+
+const jsonMIMEType = "application/json"
+
+type generateContentRequest struct {
+	SystemInstruction *content          `json:"systemInstruction,omitempty"`
+	Contents          []content         `json:"contents"`
+	GenerationConfig  *generationConfig `json:"generationConfig,omitempty"`
+}
+
+type content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []part `json:"parts"`
+}
+
+type part struct {
+	Text    string `json:"text"`
+	Thought bool   `json:"thought,omitempty"`
+}
+
+type generationConfig struct {
+	Temperature        *float64       `json:"temperature,omitempty"`
+	ResponseMIMEType   string         `json:"responseMimeType,omitempty"`
+	ResponseJSONSchema map[string]any `json:"responseJsonSchema,omitempty"`
+}
+
+type generateContentResponse struct {
+	Candidates []struct {
+		Content content `json:"content"`
+	} `json:"candidates"`
+}
+
+// CreateChatCompletion generates content with the profile's Gemini model. A JSON schema is passed as
+// the response schema, which Gemini enforces while decoding.
+func (c *Client) CreateChatCompletion(
+	ctx context.Context,
+	connectionID string,
+	message string,
+	options ...gpt.ChatCompletionOption,
+) (string, error) {
+	conn, ok := c.conns[connectionID]
+	if !ok {
+		return "", errors.New("gemini connection not found for ingest profile " + connectionID)
+	}
+
+	completionOptions := gpt.NewChatCompletionOptions(options...)
+
+	// Gemini requires user contents, so a system-only completion sends them as the user message.
+	system := completionOptions.SystemMessage
+	if message == "" {
+		system, message = "", system
+	}
+	if message == "" {
+		return "", errors.New("chat completion requires at least one message")
+	}
+
+	requestData := generateContentRequest{
+		Contents: []content{{Role: "user", Parts: []part{{Text: message}}}},
+	}
+	if system != "" {
+		requestData.SystemInstruction = &content{Parts: []part{{Text: system}}}
+	}
+
+	generation := generationConfig{Temperature: conn.temperature}
+	switch {
+	case completionOptions.JSONSchema != nil:
+		generation.ResponseMIMEType = jsonMIMEType
+		generation.ResponseJSONSchema = completionOptions.JSONSchema.Schema
+	case completionOptions.JSONResponse:
+		generation.ResponseMIMEType = jsonMIMEType
+	}
+	if generation.Temperature != nil || generation.ResponseMIMEType != "" {
+		requestData.GenerationConfig = &generation
+	}
+
+	res, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("x-goog-api-key", conn.apiKey).
+		SetBody(requestData).
+		Post(conn.baseURL + "/v1beta/models/" + url.PathEscape(conn.model) + ":generateContent")
+	if err != nil {
+		return "", fmt.Errorf("generate content: %w", err)
+	}
+
+	body := res.Body()
+	if res.IsError() {
+		return "", fmt.Errorf("generate content failed with response %s", body)
+	}
+
+	data := generateContentResponse{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("unmarshal generated content: %w", err)
+	}
+	if len(data.Candidates) == 0 {
+		return "", errors.New("generated content returned no candidates")
+	}
+
+	var text strings.Builder
+	for _, part := range data.Candidates[0].Content.Parts {
+		if !part.Thought {
+			text.WriteString(part.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", errors.New("generated content returned empty content")
+	}
+
+	return text.String(), nil
+}
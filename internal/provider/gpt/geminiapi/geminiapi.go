@@ -0,0 +1,66 @@
+package geminiapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+)
+
+const (
+	defaultBaseURL = "https://generativelanguage.googleapis.com"
+	retryCount     = 2
+)
+
+type conn struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature *float64
+}
+
+// Client serves the profiles using the Google Gemini API.
+type Client struct {
+	client *resty.Client
+	conns  map[string]conn
+}
+
+func NewClient(env *config.Env) *Client {
+	client := resty.New().
+		SetRetryCount(retryCount).
+		AddRetryCondition(func(res *resty.Response, err error) bool {
+			return err == nil && (res.StatusCode() == http.StatusTooManyRequests ||
+				res.StatusCode() >= http.StatusInternalServerError)
+		})
+
+	conns := map[string]conn{}
+	for _, ingestProfile := range env.IngestProfiles {
+		connection := ingestProfile.LLMConnectionOrDefault()
+		if connection.Provider != entity.LLMProviderGemini {
+			continue
+		}
+
+		baseURL := defaultBaseURL
+		if connection.BaseURL != "" {
+			baseURL = strings.TrimSuffix(connection.BaseURL, "/")
+		}
+
+		conns[ingestProfile.ID] = conn{
+			apiKey:      connection.APIKey,
+			baseURL:     baseURL,
+			model:       connection.Model,
+			temperature: connection.Temperature,
+		}
+	}
+
+	return &Client{
+		client: client,
+		conns:  conns,
+	}
+}
+
+var _ gpt.Provider = (*Client)(nil)
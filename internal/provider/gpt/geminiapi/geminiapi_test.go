@@ -0,0 +1,166 @@
+package geminiapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+)
+
+const testConnectionID = "ingest-profile"
+
+type capturedRequest struct {
+	path   string
+	apiKey string
+	body   generateContentRequest
+}
+
+func newTestServer(t *testing.T, statusCode int, response string) (*Client, <-chan capturedRequest) {
+	t.Helper()
+
+	requestChannel := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var body generateContentRequest
+		if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		requestChannel <- capturedRequest{
+			path:   request.URL.Path,
+			apiKey: request.Header.Get("x-goog-api-key"),
+			body:   body,
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(statusCode)
+		_, _ = fmt.Fprint(writer, response)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(&config.Env{
+		IngestProfilesFileData: config.IngestProfilesFileData{IngestProfiles: []entity.IngestProfile{
+			{ID: testConnectionID, LLM: &entity.LLMConnection{
+				Provider:    entity.LLMProviderGemini,
+				Model:       "gemini-2.5-flash",
+				Temperature: new(0.3),
+				BaseURL:     server.URL,
+				APIKey:      "gemini-key",
+			}},
+			{ID: "openai-profile"},
+		}},
+	})
+
+	return client, requestChannel
+}
+
+func TestCreateChatCompletionRequestsSchemaResponse(t *testing.T) {
+	client, requests := newTestServer(t, http.StatusOK, `{
+		"candidates":[{"content":{"role":"model","parts":[
+			{"text":"thinking","thought":true},
+			{"text":"{\"Store\":"},
+			{"text":"\"Food\"}"}
+		]}}]
+	}`)
+
+	content, err := client.CreateChatCompletion(
+		t.Context(),
+		testConnectionID,
+		"hello",
+		gpt.WithSystemMessage("classify"),
+		gpt.WithJSONSchema("answer", map[string]any{"type": "object"}),
+	)
+	if err != nil || content != `{"Store":"Food"}` {
+		t.Fatalf("CreateChatCompletion() = %q, %v", content, err)
+	}
+
+	request := <-requests
+	if request.path != "/v1beta/models/gemini-2.5-flash:generateContent" || request.apiKey != "gemini-key" {
+		t.Fatalf("request = %#v", request)
+	}
+	body := request.body
+	if body.SystemInstruction == nil || len(body.SystemInstruction.Parts) != 1 ||
+		body.SystemInstruction.Parts[0].Text != "classify" {
+		t.Fatalf("system instruction = %#v", body.SystemInstruction)
+	}
+	if len(body.Contents) != 1 || body.Contents[0].Role != "user" || body.Contents[0].Parts[0].Text != "hello" {
+		t.Fatalf("contents = %#v", body.Contents)
+	}
+	generation := body.GenerationConfig
+	if generation == nil || generation.Temperature == nil || *generation.Temperature != 0.3 ||
+		generation.ResponseMIMEType != jsonMIMEType || generation.ResponseJSONSchema["type"] != "object" {
+		t.Fatalf("generation config = %#v", generation)
+	}
+}
+
+func TestCreateChatCompletionSendsSystemOnlyCompletionAsUserMessage(t *testing.T) {
+	client, requests := newTestServer(t, http.StatusOK, `{
+		"candidates":[{"content":{"parts":[{"text":"done"}]}}]
+	}`)
+
+	content, err := client.CreateChatCompletion(
+		t.Context(),
+		testConnectionID,
+		"",
+		gpt.WithSystemMessage("hello"),
+		gpt.WithJSONResponse(),
+	)
+	if err != nil || content != "done" {
+		t.Fatalf("CreateChatCompletion() = %q, %v", content, err)
+	}
+
+	body := (<-requests).body
+	if body.SystemInstruction != nil || len(body.Contents) != 1 || body.Contents[0].Parts[0].Text != "hello" {
+		t.Fatalf("request body = %#v", body)
+	}
+	if body.GenerationConfig == nil || body.GenerationConfig.ResponseMIMEType != jsonMIMEType ||
+		body.GenerationConfig.ResponseJSONSchema != nil {
+		t.Fatalf("generation config = %#v", body.GenerationConfig)
+	}
+}
+
+func TestCreateChatCompletionRejectsInvalidRequestsAndResponses(t *testing.T) {
+	tests := []struct {
+		name         string
+		connectionID string
+		message      string
+		statusCode   int
+		response     string
+	}{
+		{name: "unknown connection", connectionID: "openai-profile", message: "hello"},
+		{name: "empty messages", connectionID: testConnectionID},
+		{
+			name:         "API error",
+			connectionID: testConnectionID,
+			message:      "hello",
+			statusCode:   http.StatusBadRequest,
+			response:     `{"error":{"code":400,"message":"bad","status":"INVALID_ARGUMENT"}}`,
+		},
+		{
+			name:         "no candidates",
+			connectionID: testConnectionID,
+			message:      "hello",
+			statusCode:   http.StatusOK,
+			response:     `{"candidates":[],"promptFeedback":{"blockReason":"SAFETY"}}`,
+		},
+		{
+			name:         "empty content",
+			connectionID: testConnectionID,
+			message:      "hello",
+			statusCode:   http.StatusOK,
+			response:     `{"candidates":[{"content":{"parts":[{"text":"hidden","thought":true}]}}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, _ := newTestServer(t, test.statusCode, test.response)
+
+			if _, err := client.CreateChatCompletion(t.Context(), test.connectionID, test.message); err == nil {
+				t.Fatal("CreateChatCompletion() error = nil")
+			}
+		})
+	}
+}
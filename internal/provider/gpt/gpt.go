@@ -5,10 +5,33 @@ import "context"
 type ChatCompletionOptions struct {
 	SystemMessage string
 	JSONResponse  bool
+	// JSONSchema restricts the response to JSON that follows the schema. It takes precedence over
+	// JSONResponse.
+	JSONSchema *JSONSchema
+}
+
+// JSONSchema describes the JSON a response must follow. Providers enforce it strictly, so every
+// object must list all its properties as required and disallow additional ones.
+type JSONSchema struct {
+	// Name identifies the schema with letters, digits, underscores and dashes only.
+	Name   string
+	Schema map[string]any
 }
 
 type ChatCompletionOption func(*ChatCompletionOptions)
 
+// NewChatCompletionOptions applies the options in order, ignoring nil ones.
+func NewChatCompletionOptions(options ...ChatCompletionOption) ChatCompletionOptions {
+	completionOptions := ChatCompletionOptions{}
+	for _, completionOption := range options {
+		if completionOption != nil {
+			completionOption(&completionOptions)
+		}
+	}
+
+	return completionOptions
+}
+
 func WithSystemMessage(message string) ChatCompletionOption {
 	return func(options *ChatCompletionOptions) {
 		options.SystemMessage = message
@@ -21,9 +44,18 @@ func WithJSONResponse() ChatCompletionOption {
 	}
 }
 
+func WithJSONSchema(name string, schema map[string]any) ChatCompletionOption {
+	return func(options *ChatCompletionOptions) {
+		options.JSONSchema = &JSONSchema{Name: name, Schema: schema}
+	}
+}
+
+// Provider completes chats with the LLM backend configured for the connection, which is the ID of an
+// ingest profile.
 type Provider interface {
 	CreateChatCompletion(
 		ctx context.Context,
+		connectionID string,
 		message string,
 		options ...ChatCompletionOption,
 	) (string, error)
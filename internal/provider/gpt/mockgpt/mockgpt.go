@@ -39,12 +39,12 @@ func (_m *MockGPT) EXPECT() *MockGPT_Expecter {
 }
 
 // CreateChatCompletion provides a mock function for the type MockGPT
-func (_mock *MockGPT) CreateChatCompletion(ctx context.Context, message string, options ...gpt.ChatCompletionOption) (string, error) {
+func (_mock *MockGPT) CreateChatCompletion(ctx context.Context, connectionID string, message string, options ...gpt.ChatCompletionOption) (string, error) {
 	var tmpRet mock.Arguments
 	if len(options) > 0 {
-		tmpRet = _mock.Called(ctx, message, options)
+		tmpRet = _mock.Called(ctx, connectionID, message, options)
 	} else {
-		tmpRet = _mock.Called(ctx, message)
+		tmpRet = _mock.Called(ctx, connectionID, message)
 	}
 	ret := tmpRet
 
@@ -54,16 +54,16 @@ func (_mock *MockGPT) CreateChatCompletion(ctx context.Context, message string,
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...gpt.ChatCompletionOption) (string, error)); ok {
-		return returnFunc(ctx, message, options...)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, ...gpt.ChatCompletionOption) (string, error)); ok {
+		return returnFunc(ctx, connectionID, message, options...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...gpt.ChatCompletionOption) string); ok {
-		r0 = returnFunc(ctx, message, options...)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, ...gpt.ChatCompletionOption) string); ok {
+		r0 = returnFunc(ctx, connectionID, message, options...)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...gpt.ChatCompletionOption) error); ok {
-		r1 = returnFunc(ctx, message, options...)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, ...gpt.ChatCompletionOption) error); ok {
+		r1 = returnFunc(ctx, connectionID, message, options...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -77,14 +77,15 @@ type MockGPT_CreateChatCompletion_Call struct {
 
 // CreateChatCompletion is a helper method to define mock.On call
 //   - ctx context.Context
+//   - connectionID string
 //   - message string
 //   - options ...gpt.ChatCompletionOption
-func (_e *MockGPT_Expecter) CreateChatCompletion(ctx interface{}, message interface{}, options ...interface{}) *MockGPT_CreateChatCompletion_Call {
+func (_e *MockGPT_Expecter) CreateChatCompletion(ctx interface{}, connectionID interface{}, message interface{}, options ...interface{}) *MockGPT_CreateChatCompletion_Call {
 	return &MockGPT_CreateChatCompletion_Call{Call: _e.mock.On("CreateChatCompletion",
-		append([]interface{}{ctx, message}, options...)...)}
+		append([]interface{}{ctx, connectionID, message}, options...)...)}
 }
 
-func (_c *MockGPT_CreateChatCompletion_Call) Run(run func(ctx context.Context, message string, options ...gpt.ChatCompletionOption)) *MockGPT_CreateChatCompletion_Call {
+func (_c *MockGPT_CreateChatCompletion_Call) Run(run func(ctx context.Context, connectionID string, message string, options ...gpt.ChatCompletionOption)) *MockGPT_CreateChatCompletion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -94,16 +95,21 @@ func (_c *MockGPT_CreateChatCompletion_Call) Run(run func(ctx context.Context, m
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 []gpt.ChatCompletionOption
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 []gpt.ChatCompletionOption
 		var variadicArgs []gpt.ChatCompletionOption
-		if len(args) > 2 {
-			variadicArgs = args[2].([]gpt.ChatCompletionOption)
+		if len(args) > 3 {
+			variadicArgs = args[3].([]gpt.ChatCompletionOption)
 		}
-		arg2 = variadicArgs
+		arg3 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2...,
+			arg2,
+			arg3...,
 		)
 	})
 	return _c
@@ -114,7 +120,7 @@ func (_c *MockGPT_CreateChatCompletion_Call) Return(s string, err error) *MockGP
 	return _c
 }
 
-func (_c *MockGPT_CreateChatCompletion_Call) RunAndReturn(run func(ctx context.Context, message string, options ...gpt.ChatCompletionOption) (string, error)) *MockGPT_CreateChatCompletion_Call {
+func (_c *MockGPT_CreateChatCompletion_Call) RunAndReturn(run func(ctx context.Context, connectionID string, message string, options ...gpt.ChatCompletionOption) (string, error)) *MockGPT_CreateChatCompletion_Call {
 	_c.Call.Return(run)
 	return _c
 }
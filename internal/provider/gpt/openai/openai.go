@@ -10,31 +10,93 @@ import (
 	"github.com/openai/openai-go/v3/shared"
 
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
 )
 
+const (
+	defaultModel           = openai.ChatModelGPT5_6Luna
+	defaultReasoningEffort = openai.ReasoningEffortMedium
+
+	// defaultOllamaBaseURL is the OpenAI-compatible endpoint of a local Ollama server.
+	defaultOllamaBaseURL = "http://localhost:11434/v1/"
+	// ollamaAPIKey fills the authorization header, which Ollama ignores.
+	ollamaAPIKey = "ollama"
+)
+
+type conn struct {
+	client          openai.Client
+	model           openai.ChatModel
+	reasoningEffort openai.ReasoningEffort
+	temperature     *float64
+}
+
+// OpenAIClient serves the profiles using OpenAI and those using an OpenAI-compatible endpoint such as
+// Ollama.
 type OpenAIClient struct {
-	client openai.Client
+	conns map[string]conn
 }
 
 func NewOpenAIClient(env *config.Env) *OpenAIClient {
-	return &OpenAIClient{
-		client: openai.NewClient(option.WithAPIKey(env.OpenAIToken)),
+	conns := map[string]conn{}
+	for _, ingestProfile := range env.IngestProfiles {
+		if !ingestProfile.UsesLLM() {
+			continue
+		}
+
+		connection := ingestProfile.LLMConnectionOrDefault()
+
+		var apiKey, baseURL string
+		switch connection.Provider {
+		case entity.LLMProviderOpenAI:
+			apiKey, baseURL = env.OpenAIToken, connection.BaseURL
+			if connection.Model == "" {
+				connection.Model = defaultModel
+			}
+			if connection.ReasoningEffort == "" {
+				connection.ReasoningEffort = entity.ReasoningEffort(defaultReasoningEffort)
+			}
+		case entity.LLMProviderOllama:
+			apiKey, baseURL = ollamaAPIKey, defaultOllamaBaseURL
+			if connection.BaseURL != "" {
+				baseURL = connection.BaseURL
+			}
+		default:
+			continue
+		}
+		if connection.APIKey != "" {
+			apiKey = connection.APIKey
+		}
+
+		requestOptions := []option.RequestOption{option.WithAPIKey(apiKey)}
+		if baseURL != "" {
+			requestOptions = append(requestOptions, option.WithBaseURL(baseURL))
+		}
+
+		conns[ingestProfile.ID] = conn{
+			client:          openai.NewClient(requestOptions...),
+			model:           connection.Model,
+			reasoningEffort: openai.ReasoningEffort(connection.ReasoningEffort),
+			temperature:     connection.Temperature,
+		}
 	}
+
+	return &OpenAIClient{conns: conns}
 }
 
 func (o *OpenAIClient) CreateChatCompletion(
 	ctx context.Context,
+	connectionID string,
 	message string,
 	options ...gpt.ChatCompletionOption,
 ) (string, error) {
-	completionOptions := gpt.ChatCompletionOptions{}
-	for _, completionOption := range options {
-		if completionOption != nil {
-			completionOption(&completionOptions)
-		}
+	conn, ok := o.conns[connectionID]
+	if !ok {
+		return "", errors.New("OpenAI connection not found for ingest profile " + connectionID)
 	}
 
+	completionOptions := gpt.NewChatCompletionOptions(options...)
+
 	messages := make([]openai.ChatCompletionMessageParamUnion, 0)
 	if completionOptions.SystemMessage != "" {
 		messages = append(messages, openai.SystemMessage(completionOptions.SystemMessage))
@@ -47,17 +109,31 @@ func (o *OpenAIClient) CreateChatCompletion(
 	}
 
 	params := openai.ChatCompletionNewParams{
-		Model:           openai.ChatModelGPT5_6Luna,
-		ReasoningEffort: openai.ReasoningEffortMedium,
+		Model:           conn.model,
+		ReasoningEffort: conn.reasoningEffort,
 		Messages:        messages,
 	}
-	if completionOptions.JSONResponse {
+	if conn.temperature != nil {
+		params.Temperature = openai.Float(*conn.temperature)
+	}
+	switch {
+	case completionOptions.JSONSchema != nil:
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:   completionOptions.JSONSchema.Name,
+					Schema: completionOptions.JSONSchema.Schema,
+					Strict: openai.Bool(true),
+				},
+			},
+		}
+	case completionOptions.JSONResponse:
 		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONObject: new(shared.NewResponseFormatJSONObjectParam()),
 		}
 	}
 
-	response, err := o.client.Chat.Completions.New(ctx, params)
+	response, err := conn.client.Chat.Completions.New(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("create chat completion: %w", err)
 	}
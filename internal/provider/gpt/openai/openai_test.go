@@ -11,25 +11,41 @@ import (
 	openaisdk "github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
 
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
 )
 
+const testConnectionID = "ingest-profile"
+
 type chatCompletionRequest struct {
-	Messages []struct {
+	Model           string   `json:"model"`
+	ReasoningEffort string   `json:"reasoning_effort"`
+	Temperature     *float64 `json:"temperature"`
+	Messages        []struct {
 		Role    string `json:"role"`
 		Content string `json:"content"`
 	} `json:"messages"`
 	ResponseFormat *struct {
-		Type string `json:"type"`
+		Type       string `json:"type"`
+		JSONSchema *struct {
+			Name   string         `json:"name"`
+			Strict bool           `json:"strict"`
+			Schema map[string]any `json:"schema"`
+		} `json:"json_schema"`
 	} `json:"response_format"`
 }
 
 func newTestClient(serverURL string) *OpenAIClient {
-	return &OpenAIClient{client: openaisdk.NewClient(
-		option.WithAPIKey("test"),
-		option.WithBaseURL(serverURL+"/"),
-		option.WithMaxRetries(0),
-	)}
+	return &OpenAIClient{conns: map[string]conn{testConnectionID: {
+		client: openaisdk.NewClient(
+			option.WithAPIKey("test"),
+			option.WithBaseURL(serverURL+"/"),
+			option.WithMaxRetries(0),
+		),
+		model:           defaultModel,
+		reasoningEffort: defaultReasoningEffort,
+	}}}
 }
 
 func writeCompletion(writer http.ResponseWriter, content string) {
@@ -73,6 +89,17 @@ func TestCreateChatCompletionBuildsGenericRequest(t *testing.T) {
 			wantContents:       []string{"return JSON", `{"input":"value"}`},
 			wantResponseFormat: "json_object",
 		},
+		{
+			name:    "JSON schema takes precedence over JSON response",
+			message: "hello",
+			options: []gpt.ChatCompletionOption{
+				gpt.WithJSONResponse(),
+				gpt.WithJSONSchema("answer", map[string]any{"type": "object"}),
+			},
+			wantRoles:          []string{"user"},
+			wantContents:       []string{"hello"},
+			wantResponseFormat: "json_schema",
+		},
 		{
 			name:    "last system message wins",
 			message: "hello",
@@ -120,6 +147,7 @@ func TestCreateChatCompletionBuildsGenericRequest(t *testing.T) {
 
 			content, err := newTestClient(server.URL).CreateChatCompletion(
 				t.Context(),
+				testConnectionID,
 				test.message,
 				test.options...,
 			)
@@ -148,6 +176,17 @@ func TestCreateChatCompletionBuildsGenericRequest(t *testing.T) {
 				completionRequest.ResponseFormat.Type != test.wantResponseFormat {
 				t.Fatalf("response format = %#v", completionRequest.ResponseFormat)
 			}
+
+			jsonSchema := completionRequest.ResponseFormat != nil && completionRequest.ResponseFormat.JSONSchema != nil
+			if jsonSchema != (test.wantResponseFormat == "json_schema") {
+				t.Fatalf("response format = %#v", completionRequest.ResponseFormat)
+			}
+			if jsonSchema {
+				schema := completionRequest.ResponseFormat.JSONSchema
+				if schema.Name != "answer" || !schema.Strict || schema.Schema["type"] != "object" {
+					t.Fatalf("JSON schema = %#v", schema)
+				}
+			}
 		})
 	}
 }
@@ -162,6 +201,7 @@ func TestCreateChatCompletionRejectsEmptyMessages(t *testing.T) {
 
 	_, err := newTestClient(server.URL).CreateChatCompletion(
 		t.Context(),
+		testConnectionID,
 		"",
 		gpt.WithSystemMessage(""),
 	)
@@ -221,10 +261,109 @@ func TestCreateChatCompletionRejectsInvalidResponses(t *testing.T) {
 			}))
 			t.Cleanup(server.Close)
 
-			_, err := newTestClient(server.URL).CreateChatCompletion(t.Context(), "hello")
+			_, err := newTestClient(server.URL).CreateChatCompletion(t.Context(), testConnectionID, "hello")
 			if err == nil {
 				t.Fatal("CreateChatCompletion() error = nil")
 			}
 		})
 	}
 }
+
+func TestNewOpenAIClientConfiguresConnectionsPerProfile(t *testing.T) {
+	type capturedRequest struct {
+		authorization string
+		body          chatCompletionRequest
+	}
+	requestChannel := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var completionRequest chatCompletionRequest
+		if err := json.NewDecoder(request.Body).Decode(&completionRequest); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		requestChannel <- capturedRequest{
+			authorization: request.Header.Get("Authorization"),
+			body:          completionRequest,
+		}
+		writeCompletion(writer, "completed")
+	}))
+	t.Cleanup(server.Close)
+
+	env := &config.Env{
+		EnvFileData: config.EnvFileData{OpenAIToken: "environment-token"},
+		IngestProfilesFileData: config.IngestProfilesFileData{IngestProfiles: []entity.IngestProfile{
+			{ID: "default-profile"},
+			{ID: "openai-profile", LLM: &entity.LLMConnection{
+				Model:           "gpt-5-mini",
+				ReasoningEffort: entity.ReasoningEffortLow,
+				Temperature:     new(0.2),
+				BaseURL:         server.URL + "/v1",
+				APIKey:          "profile-key",
+			}},
+			{ID: "ollama-profile", LLM: &entity.LLMConnection{
+				Provider: entity.LLMProviderOllama,
+				Model:    "llama3.1",
+				BaseURL:  server.URL + "/v1",
+			}},
+			{ID: "anthropic-profile", LLM: &entity.LLMConnection{
+				Provider: entity.LLMProviderAnthropic,
+				Model:    "claude-sonnet-4-5",
+				APIKey:   "anthropic-key",
+			}},
+		}},
+	}
+
+	client := NewOpenAIClient(env)
+	if len(client.conns) != 3 {
+		t.Fatalf("connections = %#v", client.conns)
+	}
+	defaultConn := client.conns["default-profile"]
+	if defaultConn.model != defaultModel || defaultConn.reasoningEffort != defaultReasoningEffort ||
+		defaultConn.temperature != nil {
+		t.Fatalf("default connection = %#v", defaultConn)
+	}
+
+	tests := []struct {
+		connectionID        string
+		wantAuthorization   string
+		wantModel           string
+		wantReasoningEffort string
+		wantTemperature     *float64
+	}{
+		{
+			connectionID:        "openai-profile",
+			wantAuthorization:   "Bearer profile-key",
+			wantModel:           "gpt-5-mini",
+			wantReasoningEffort: "low",
+			wantTemperature:     new(0.2),
+		},
+		{
+			connectionID:      "ollama-profile",
+			wantAuthorization: "Bearer " + ollamaAPIKey,
+			wantModel:         "llama3.1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.connectionID, func(t *testing.T) {
+			content, err := client.CreateChatCompletion(t.Context(), test.connectionID, "hello")
+			if err != nil || content != "completed" {
+				t.Fatalf("CreateChatCompletion() = %q, %v", content, err)
+			}
+
+			request := <-requestChannel
+			if request.authorization != test.wantAuthorization {
+				t.Fatalf("authorization = %q, want %q", request.authorization, test.wantAuthorization)
+			}
+			if request.body.Model != test.wantModel || request.body.ReasoningEffort != test.wantReasoningEffort {
+				t.Fatalf("request = %#v", request.body)
+			}
+			if (request.body.Temperature == nil) != (test.wantTemperature == nil) ||
+				request.body.Temperature != nil && *request.body.Temperature != *test.wantTemperature {
+				t.Fatalf("temperature = %v, want %v", request.body.Temperature, test.wantTemperature)
+			}
+		})
+	}
+
+	if _, err := client.CreateChatCompletion(t.Context(), "anthropic-profile", "hello"); err == nil {
+		t.Fatal("CreateChatCompletion(anthropic-profile) error = nil, want missing connection error")
+	}
+}
@@ -0,0 +1,31 @@
+package gpt
+
+import (
+	"context"
+	"errors"
+)
+
+// Router dispatches each chat completion to the provider configured for its connection.
+type Router struct {
+	providers map[string]Provider
+}
+
+func NewRouter(providers map[string]Provider) *Router {
+	return &Router{providers: providers}
+}
+
+func (r *Router) CreateChatCompletion(
+	ctx context.Context,
+	connectionID string,
+	message string,
+	options ...ChatCompletionOption,
+) (string, error) {
+	provider, ok := r.providers[connectionID]
+	if !ok || provider == nil {
+		return "", errors.New("gpt provider not found for ingest profile " + connectionID)
+	}
+
+	return provider.CreateChatCompletion(ctx, connectionID, message, options...)
+}
+
+var _ Provider = (*Router)(nil)
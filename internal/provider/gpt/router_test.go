@@ -0,0 +1,50 @@
+package gpt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/mockgpt"
+)
+
+func TestRouterDispatchesByConnection(t *testing.T) {
+	openAI := mockgpt.NewMockGPT(t)
+	ollama := mockgpt.NewMockGPT(t)
+	router := gpt.NewRouter(map[string]gpt.Provider{
+		"openai-profile": openAI,
+		"ollama-profile": ollama,
+	})
+
+	openAI.EXPECT().
+		CreateChatCompletion(t.Context(), "openai-profile", "hello").
+		Return("from OpenAI", nil).
+		Once()
+	ollama.EXPECT().
+		CreateChatCompletion(t.Context(), "ollama-profile", "hello", mock.Anything).
+		Return("from Ollama", nil).
+		Once()
+
+	content, err := router.CreateChatCompletion(t.Context(), "openai-profile", "hello")
+	if err != nil || content != "from OpenAI" {
+		t.Fatalf("CreateChatCompletion(openai) = %q, %v", content, err)
+	}
+	content, err = router.CreateChatCompletion(
+		t.Context(),
+		"ollama-profile",
+		"hello",
+		gpt.WithSystemMessage("system"),
+	)
+	if err != nil || content != "from Ollama" {
+		t.Fatalf("CreateChatCompletion(ollama) = %q, %v", content, err)
+	}
+}
+
+func TestRouterRejectsUnknownConnection(t *testing.T) {
+	router := gpt.NewRouter(map[string]gpt.Provider{})
+
+	if _, err := router.CreateChatCompletion(t.Context(), "missing", "hello"); err == nil {
+		t.Fatal("CreateChatCompletion() error = nil, want missing provider error")
+	}
+}
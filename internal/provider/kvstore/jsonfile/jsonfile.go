@@ -0,0 +1,139 @@
+package jsonfile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore"
+)
+
+const (
+	directoryPerm = 0o750
+	filePerm      = 0o600
+)
+
+// Client keeps every value in one JSON object on disk, loaded on first use. Without a path it
+// stores nothing, so every Get misses.
+type Client struct {
+	path string
+
+	values map[string]string
+	mutex  sync.Mutex
+}
+
+func NewClient(env *config.Env) *Client {
+	return &Client{path: env.CategorizationCachePath}
+}
+
+func (c *Client) Get(_ context.Context, keys []string) (map[string]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if value, exists := c.values[key]; exists {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (c *Client) Set(_ context.Context, values map[string]string) error {
+	if c.path == "" || len(values) == 0 {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	maps.Copy(c.values, values)
+
+	return c.save()
+}
+
+func (c *Client) Persistent() bool {
+	return c.path != ""
+}
+
+func (c *Client) load() error {
+	if c.values != nil {
+		return nil
+	}
+	c.values = map[string]string{}
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read key-value store %q: %w", c.path, err)
+	}
+	if err := json.Unmarshal(data, &c.values); err != nil {
+		c.values = nil
+
+		return fmt.Errorf("decode key-value store %q: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// save writes the values to a temporary file renamed over the store, so an interrupted run never
+// leaves it half written.
+func (c *Client) save() error {
+	data, err := json.MarshalIndent(c.values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode key-value store: %w", err)
+	}
+
+	directory := filepath.Dir(c.path)
+	if err := os.MkdirAll(directory, directoryPerm); err != nil {
+		return fmt.Errorf("create key-value store directory %q: %w", directory, err)
+	}
+
+	file, err := os.CreateTemp(directory, filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create key-value store file: %w", err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("write key-value store file: %w", err)
+	}
+	if err := file.Chmod(filePerm); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("set key-value store file permissions: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close key-value store file: %w", err)
+	}
+	if err := os.Rename(file.Name(), c.path); err != nil {
+		return fmt.Errorf("replace key-value store %q: %w", c.path, err)
+	}
+
+	return nil
+}
+
+var _ kvstore.Provider = (*Client)(nil)
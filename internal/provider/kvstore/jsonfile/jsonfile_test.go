@@ -0,0 +1,69 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+)
+
+func testClient(path string) *Client {
+	return NewClient(&config.Env{EnvFileData: config.EnvFileData{CategorizationCachePath: path}})
+}
+
+func TestValuesPersistAcrossClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "store.json")
+
+	client := testClient(path)
+	if !client.Persistent() {
+		t.Fatal("Persistent() = false, want true with a path")
+	}
+	values, err := client.Get(t.Context(), []string{"a"})
+	if err != nil || len(values) != 0 {
+		t.Fatalf("Get() before set = %#v, %v", values, err)
+	}
+	if err := client.Set(t.Context(), map[string]string{"a": "1", "b": "2"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := client.Set(t.Context(), map[string]string{"b": "3"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	values, err = testClient(path).Get(t.Context(), []string{"a", "b", "missing"})
+	if err != nil || !reflect.DeepEqual(values, map[string]string{"a": "1", "b": "3"}) {
+		t.Fatalf("Get() = %#v, %v", values, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("store directory = %v, %v, want only the store file", entries, err)
+	}
+}
+
+func TestClientWithoutPathStoresNothing(t *testing.T) {
+	client := testClient("")
+	if client.Persistent() {
+		t.Fatal("Persistent() = true, want false without a path")
+	}
+	if err := client.Set(t.Context(), map[string]string{"a": "1"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	values, err := client.Get(t.Context(), []string{"a"})
+	if err != nil || len(values) != 0 {
+		t.Fatalf("Get() = %#v, %v", values, err)
+	}
+}
+
+func TestGetRejectsCorruptStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	if err := os.WriteFile(path, []byte("not JSON"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := testClient(path).Get(t.Context(), []string{"a"}); err == nil {
+		t.Fatal("Get() error = nil")
+	}
+}
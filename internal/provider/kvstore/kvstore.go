@@ -0,0 +1,13 @@
+package kvstore
+
+import "context"
+
+// Provider persists string values by key across runs.
+type Provider interface {
+	// Get returns the stored values of the keys that exist, leaving missing keys out.
+	Get(ctx context.Context, keys []string) (map[string]string, error)
+	// Set stores the values, replacing those already stored under the same keys.
+	Set(ctx context.Context, values map[string]string) error
+	// Persistent reports whether the values set outlive the run.
+	Persistent() bool
+}
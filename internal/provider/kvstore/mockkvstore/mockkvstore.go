@@ -0,0 +1,207 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mockkvstore
+
+import (
+	"context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockKVStore creates a new instance of MockKVStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKVStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKVStore {
+	mock := &MockKVStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockKVStore is an autogenerated mock type for the Provider type
+type MockKVStore struct {
+	mock.Mock
+}
+
+type MockKVStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKVStore) EXPECT() *MockKVStore_Expecter {
+	return &MockKVStore_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function for the type MockKVStore
+func (_mock *MockKVStore) Get(ctx context.Context, keys []string) (map[string]string, error) {
+	ret := _mock.Called(ctx, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) (map[string]string, error)); ok {
+		return returnFunc(ctx, keys)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) map[string]string); ok {
+		r0 = returnFunc(ctx, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockKVStore_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockKVStore_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []string
+func (_e *MockKVStore_Expecter) Get(ctx interface{}, keys interface{}) *MockKVStore_Get_Call {
+	return &MockKVStore_Get_Call{Call: _e.mock.On("Get", ctx, keys)}
+}
+
+func (_c *MockKVStore_Get_Call) Run(run func(ctx context.Context, keys []string)) *MockKVStore_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKVStore_Get_Call) Return(stringToString map[string]string, err error) *MockKVStore_Get_Call {
+	_c.Call.Return(stringToString, err)
+	return _c
+}
+
+func (_c *MockKVStore_Get_Call) RunAndReturn(run func(ctx context.Context, keys []string) (map[string]string, error)) *MockKVStore_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Persistent provides a mock function for the type MockKVStore
+func (_mock *MockKVStore) Persistent() bool {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Persistent")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockKVStore_Persistent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Persistent'
+type MockKVStore_Persistent_Call struct {
+	*mock.Call
+}
+
+// Persistent is a helper method to define mock.On call
+func (_e *MockKVStore_Expecter) Persistent() *MockKVStore_Persistent_Call {
+	return &MockKVStore_Persistent_Call{Call: _e.mock.On("Persistent")}
+}
+
+func (_c *MockKVStore_Persistent_Call) Run(run func()) *MockKVStore_Persistent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockKVStore_Persistent_Call) Return(b bool) *MockKVStore_Persistent_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockKVStore_Persistent_Call) RunAndReturn(run func() bool) *MockKVStore_Persistent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function for the type MockKVStore
+func (_mock *MockKVStore) Set(ctx context.Context, values map[string]string) error {
+	ret := _mock.Called(ctx, values)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, map[string]string) error); ok {
+		r0 = returnFunc(ctx, values)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockKVStore_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type MockKVStore_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - ctx context.Context
+//   - values map[string]string
+func (_e *MockKVStore_Expecter) Set(ctx interface{}, values interface{}) *MockKVStore_Set_Call {
+	return &MockKVStore_Set_Call{Call: _e.mock.On("Set", ctx, values)}
+}
+
+func (_c *MockKVStore_Set_Call) Run(run func(ctx context.Context, values map[string]string)) *MockKVStore_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 map[string]string
+		if args[1] != nil {
+			arg1 = args[1].(map[string]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockKVStore_Set_Call) Return(err error) *MockKVStore_Set_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockKVStore_Set_Call) RunAndReturn(run func(ctx context.Context, values map[string]string) error) *MockKVStore_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
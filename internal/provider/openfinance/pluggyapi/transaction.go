@@ -22,6 +22,8 @@ type listTransactionsResponse struct {
 }
 
 type listTransactionsResponseResult struct {
+	ID                      string              `json:"id"`
+	AccountID               string              `json:"accountId"`
 	Description             string              `json:"description"`
 	Amount                  float64             `json:"amount"`
 	AmountInAccountCurrency *float64            `json:"amountInAccountCurrency"`
@@ -33,7 +35,10 @@ type listTransactionsResponseResult struct {
 }
 
 type creditCardMetadata struct {
-	CardNumber *string `json:"cardNumber,omitempty"`
+	CardNumber        *string  `json:"cardNumber,omitempty"`
+	InstallmentNumber *int     `json:"installmentNumber,omitempty"`
+	TotalInstallments *int     `json:"totalInstallments,omitempty"`
+	TotalAmount       *float64 `json:"totalAmount,omitempty"`
 }
 
 type paymentData struct {
@@ -186,6 +191,8 @@ func (c *Client) fetchAllAccountTransactions(
 
 func transactionInput(result listTransactionsResponseResult) entity.TransactionInput {
 	input := entity.TransactionInput{
+		ExternalID:              result.ID,
+		AccountID:               result.AccountID,
 		AccountType:             entity.AccountTypeCreditCard,
 		Description:             result.Description,
 		Amount:                  result.Amount,
@@ -200,6 +207,9 @@ func transactionInput(result listTransactionsResponseResult) entity.TransactionI
 
 	if result.CreditCardMetadata != nil {
 		input.CardLastDigits = result.CreditCardMetadata.CardNumber
+		input.InstallmentNumber = result.CreditCardMetadata.InstallmentNumber
+		input.TotalInstallments = result.CreditCardMetadata.TotalInstallments
+		input.TotalAmount = result.CreditCardMetadata.TotalAmount
 	}
 
 	if result.PaymentData == nil {
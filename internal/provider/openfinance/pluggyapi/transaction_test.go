@@ -30,11 +30,18 @@ func TestListTransactionsByIngestProfileIDPaginatesAndMaps(t *testing.T) {
                 "totalPages": 2,
                 "page": 1,
                 "results": [{
+                    "id": "0b5a7c1e-card",
+                    "accountId": "account",
                     "description": "Store",
                     "amount": -10.5,
                     "date": "2026-08-09T12:00:00Z",
                     "type": "DEBIT",
-                    "creditCardMetadata": {"cardNumber": "1234"}
+                    "creditCardMetadata": {
+                        "cardNumber": "1234",
+                        "installmentNumber": 2,
+                        "totalInstallments": 3,
+                        "totalAmount": 31.5
+                    }
                 }]
             }`)
 		case "2":
@@ -42,6 +49,8 @@ func TestListTransactionsByIngestProfileIDPaginatesAndMaps(t *testing.T) {
                 "totalPages": 2,
                 "page": 2,
                 "results": [{
+                    "id": "9f3d2a40-pix",
+                    "accountId": "account",
                     "amount": -20,
                     "date": "2026-08-10T12:00:00Z",
                     "type": "DEBIT",
@@ -82,12 +91,19 @@ func TestListTransactionsByIngestProfileIDPaginatesAndMaps(t *testing.T) {
 	if len(transactions) != 2 {
 		t.Fatalf("transactions = %#v", transactions)
 	}
-	if transactions[0].Name != "Store" ||
+	if transactions[0].ExternalID != "0b5a7c1e-card" ||
+		transactions[0].AccountID != "account" ||
+		transactions[0].Name != "Store" ||
 		transactions[0].PaymentMethod != entity.PaymentMethodCreditCard ||
-		transactions[0].Amount != 10.5 {
+		transactions[0].Amount != 10.5 ||
+		transactions[0].InstallmentNumber != 2 ||
+		transactions[0].TotalInstallments != 3 ||
+		transactions[0].PurchaseAmount != 31.5 {
 		t.Fatalf("credit card transaction = %#v", transactions[0])
 	}
-	if transactions[1].Name != "12.345.678/0001-95" ||
+	if transactions[1].ExternalID != "9f3d2a40-pix" ||
+		transactions[1].AccountID != "account" ||
+		transactions[1].Name != "12.345.678/0001-95" ||
 		transactions[1].PaymentMethod != entity.PaymentMethodPix ||
 		transactions[1].Amount != 20 {
 		t.Fatalf("bank transaction = %#v", transactions[1])
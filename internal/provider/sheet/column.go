@@ -46,6 +46,14 @@ func NewTextColumn(name string) TextColumn {
 	return TextColumn{column: newColumn(name, ColumnTypeText)}
 }
 
+// Hidden marks a bookkeeping column that readers do not need to see. Providers that cannot hide
+// columns still show it.
+func (c TextColumn) Hidden() TextColumn {
+	c.definition.hidden = true
+
+	return c
+}
+
 type NumberColumn struct {
 	column
 }
@@ -86,11 +94,20 @@ func NewDateColumn(name string) DateColumn {
 	return DateColumn{column: newColumn(name, ColumnTypeDate)}
 }
 
+type CheckboxColumn struct {
+	column
+}
+
+func NewCheckboxColumn(name string) CheckboxColumn {
+	return CheckboxColumn{column: newColumn(name, ColumnTypeCheckbox)}
+}
+
 type ColumnDefinition struct {
 	name          string
 	columnType    ColumnType
 	currency      Currency
 	selectOptions []SelectOptionDefinition
+	hidden        bool
 }
 
 func (d ColumnDefinition) Name() string {
@@ -105,6 +122,10 @@ func (d ColumnDefinition) Currency() Currency {
 	return d.currency
 }
 
+func (d ColumnDefinition) Hidden() bool {
+	return d.hidden
+}
+
 func (d ColumnDefinition) SelectOptions() []SelectOptionDefinition {
 	return slices.Clone(d.selectOptions)
 }
@@ -132,11 +153,12 @@ func (d ColumnDefinition) Validate() error {
 type ColumnType string
 
 const (
-	ColumnTypeTitle  ColumnType = "title"
-	ColumnTypeText   ColumnType = "text"
-	ColumnTypeNumber ColumnType = "number"
-	ColumnTypeSelect ColumnType = "select"
-	ColumnTypeDate   ColumnType = "date"
+	ColumnTypeTitle    ColumnType = "title"
+	ColumnTypeText     ColumnType = "text"
+	ColumnTypeNumber   ColumnType = "number"
+	ColumnTypeSelect   ColumnType = "select"
+	ColumnTypeDate     ColumnType = "date"
+	ColumnTypeCheckbox ColumnType = "checkbox"
 )
 
 func (t ColumnType) isValid() bool {
@@ -145,7 +167,8 @@ func (t ColumnType) isValid() bool {
 		ColumnTypeText,
 		ColumnTypeNumber,
 		ColumnTypeSelect,
-		ColumnTypeDate:
+		ColumnTypeDate,
+		ColumnTypeCheckbox:
 		return true
 	default:
 		return false
@@ -19,6 +19,7 @@ func TestColumnTypesAreDerivedFromCells(t *testing.T) {
 		{name: "number", definition: NewNumberColumn("Amount").Definition(), columnType: ColumnTypeNumber},
 		{name: "select", definition: NewSelectColumn("Category").Definition(), columnType: ColumnTypeSelect},
 		{name: "date", definition: NewDateColumn("Date").Definition(), columnType: ColumnTypeDate},
+		{name: "checkbox", definition: NewCheckboxColumn("Reviewed").Definition(), columnType: ColumnTypeCheckbox},
 	}
 
 	for _, test := range tests {
@@ -26,7 +27,8 @@ func TestColumnTypesAreDerivedFromCells(t *testing.T) {
 			if test.definition.Name() == "" || test.definition.Type() != test.columnType {
 				t.Fatalf("column definition = %#v", test.definition)
 			}
-			if test.definition.Currency() != "" || len(test.definition.SelectOptions()) != 0 {
+			if test.definition.Currency() != "" || len(test.definition.SelectOptions()) != 0 ||
+				test.definition.Hidden() {
 				t.Fatalf("unexpected optional configuration: %#v", test.definition)
 			}
 		})
@@ -39,12 +41,14 @@ func TestColumnMethodSetsExposeOnlyCompatibleConfiguration(t *testing.T) {
 		column       any
 		wantCurrency bool
 		wantOptions  bool
+		wantHidden   bool
 	}{
 		{name: "title", column: NewTitleColumn("Title")},
-		{name: "text", column: NewTextColumn("Text")},
+		{name: "text", column: NewTextColumn("Text"), wantHidden: true},
 		{name: "number", column: NewNumberColumn("Number"), wantCurrency: true},
 		{name: "select", column: NewSelectColumn("Select"), wantOptions: true},
 		{name: "date", column: NewDateColumn("Date")},
+		{name: "checkbox", column: NewCheckboxColumn("Reviewed")},
 	}
 
 	for _, test := range tests {
@@ -52,13 +56,16 @@ func TestColumnMethodSetsExposeOnlyCompatibleConfiguration(t *testing.T) {
 			columnType := reflect.TypeOf(test.column)
 			_, hasCurrency := columnType.MethodByName("Currency")
 			_, hasOptions := columnType.MethodByName("Options")
-			if hasCurrency != test.wantCurrency || hasOptions != test.wantOptions {
+			_, hasHidden := columnType.MethodByName("Hidden")
+			if hasCurrency != test.wantCurrency || hasOptions != test.wantOptions || hasHidden != test.wantHidden {
 				t.Fatalf(
-					"method set Currency=%t Options=%t, want Currency=%t Options=%t",
+					"method set Currency=%t Options=%t Hidden=%t, want Currency=%t Options=%t Hidden=%t",
 					hasCurrency,
 					hasOptions,
+					hasHidden,
 					test.wantCurrency,
 					test.wantOptions,
+					test.wantHidden,
 				)
 			}
 		})
@@ -74,6 +81,10 @@ func TestColumnChainsUseLastValue(t *testing.T) {
 		t.Fatalf("currency = %q, want BRL", number.Currency())
 	}
 
+	if text := NewTextColumn("External ID").Hidden().Definition(); !text.Hidden() {
+		t.Fatalf("text column = %#v, want hidden", text)
+	}
+
 	first := NewSelectOption("First").Color(entity.Blue)
 	second := NewSelectOption("Second").Color(entity.Red)
 	selectColumn := NewSelectColumn("Category").
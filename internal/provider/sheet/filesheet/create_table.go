@@ -0,0 +1,94 @@
+package filesheet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// CreateTable writes an empty data file with the header row and its schema sidecar. Files have no
+// icon or option colors, so those parts of the definition are ignored.
+func (c *Client) CreateTable(
+	_ context.Context,
+	connectionID string,
+	definition sheet.TableDefinition,
+) (sheet.Table, error) {
+	conn, file, err := c.connection(connectionID)
+	if err != nil {
+		return sheet.Table{}, err
+	}
+
+	if err := definition.Validate(); err != nil {
+		return sheet.Table{}, fmt.Errorf("invalid table definition: %w", err)
+	}
+
+	columns := definition.Columns()
+	schema := tableSchema{
+		Title:   definition.Title(),
+		Format:  conn.format,
+		Columns: make([]schemaColumn, 0, len(columns)),
+	}
+	for _, column := range columns {
+		schemaColumn, err := newSchemaColumn(column)
+		if err != nil {
+			return sheet.Table{}, fmt.Errorf("invalid table definition: column %q: %w", column.Name(), err)
+		}
+		schema.Columns = append(schema.Columns, schemaColumn)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := os.MkdirAll(conn.directory, directoryPerm); err != nil {
+		return sheet.Table{}, fmt.Errorf("failed to create directory %s: %w", conn.directory, err)
+	}
+
+	id := tableID(definition.Title())
+	if _, err := os.Stat(conn.schemaPath(id)); err == nil {
+		return sheet.Table{}, fmt.Errorf("table %q already exists", id)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return sheet.Table{}, fmt.Errorf("failed to check table %q: %w", id, err)
+	}
+
+	if err := file.write(conn.dataPath(id), schema, nil); err != nil {
+		return sheet.Table{}, fmt.Errorf("failed to create table %q: %w", id, err)
+	}
+	if err := conn.writeSchema(id, schema); err != nil {
+		return sheet.Table{}, err
+	}
+
+	return sheet.Table{ID: id, Title: schema.Title}, nil
+}
+
+func newSchemaColumn(definition sheet.ColumnDefinition) (schemaColumn, error) {
+	switch definition.Currency() {
+	case "", sheet.Currency("BRL"):
+	default:
+		return schemaColumn{}, fmt.Errorf("unsupported currency %q", definition.Currency())
+	}
+
+	column := schemaColumn{
+		Name:     definition.Name(),
+		Type:     definition.Type(),
+		Currency: definition.Currency(),
+		Hidden:   definition.Hidden(),
+	}
+	for _, option := range definition.SelectOptions() {
+		column.Options = appendOption(column.Options, option.Name())
+	}
+
+	return column, nil
+}
+
+func appendOption(options []string, option string) []string {
+	for _, existing := range options {
+		if existing == option {
+			return options
+		}
+	}
+
+	return append(options, option)
+}
@@ -0,0 +1,96 @@
+package filesheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// csvFile stores dates as RFC 3339 text, keeping their original offset.
+type csvFile struct{}
+
+func (csvFile) read(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return records, nil
+}
+
+func (csvFile) write(path string, schema tableSchema, records [][]any) error {
+	return writeFileAtomically(path, func(file io.Writer) error {
+		writer := csv.NewWriter(file)
+		if err := writer.Write(csvRecord(schema.header())); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		for _, record := range records {
+			if err := writer.Write(csvRecord(record)); err != nil {
+				return fmt.Errorf("failed to write record: %w", err)
+			}
+		}
+		writer.Flush()
+
+		return writer.Error()
+	})
+}
+
+func (csvFile) append(path string, _ tableSchema, records [][]any) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, filePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	writer := csv.NewWriter(file)
+	for _, record := range records {
+		if err := writer.Write(csvRecord(record)); err != nil {
+			_ = file.Close()
+
+			return fmt.Errorf("failed to append to %s: %w", path, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to append to %s: %w", path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func csvRecord(values []any) []string {
+	record := make([]string, len(values))
+	for index, value := range values {
+		switch value := value.(type) {
+		case nil:
+		case string:
+			record[index] = value
+		case float64:
+			record[index] = strconv.FormatFloat(value, 'f', -1, 64)
+		case time.Time:
+			record[index] = value.Format(time.RFC3339)
+		default:
+			record[index] = fmt.Sprint(value)
+		}
+	}
+
+	return record
+}
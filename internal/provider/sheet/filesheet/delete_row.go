@@ -0,0 +1,46 @@
+package filesheet
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// DeleteRow rewrites the data file without the record with the given data row number, so the
+// records after it move up one number.
+func (c *Client) DeleteRow(_ context.Context, connectionID, tableID, rowID string) error {
+	conn, file, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	rowNumber, err := strconv.Atoi(rowID)
+	if err != nil || rowNumber < 1 {
+		return fmt.Errorf("invalid row id %q", rowID)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	schema, err := conn.readSchema(tableID)
+	if err != nil {
+		return err
+	}
+
+	path := conn.dataPath(tableID)
+	records, err := file.read(path)
+	if err != nil {
+		return fmt.Errorf("failed to delete row %q of table %q: %w", rowID, tableID, err)
+	}
+	if rowNumber >= len(records) {
+		return fmt.Errorf("row %q not found in table %q", rowID, tableID)
+	}
+
+	stored := slices.Delete(storedRecords(schema, records, len(schema.Columns)), rowNumber-1, rowNumber)
+	if err := file.write(path, schema, stored); err != nil {
+		return fmt.Errorf("failed to delete row %q of table %q: %w", rowID, tableID, err)
+	}
+
+	return nil
+}
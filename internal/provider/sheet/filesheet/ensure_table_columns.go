@@ -0,0 +1,149 @@
+package filesheet
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// EnsureTableColumns records new select options in the schema and appends missing columns,
+// rewriting the data file so existing rows keep their values under the wider header.
+func (c *Client) EnsureTableColumns(
+	_ context.Context,
+	connectionID string,
+	tableID string,
+	columns ...sheet.Column,
+) error {
+	conn, file, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+	definitions, err := ensureColumnDefinitions(columns)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	schema, err := conn.readSchema(tableID)
+	if err != nil {
+		return err
+	}
+
+	updated := schema
+	updated.Columns = slices.Clone(schema.Columns)
+	changed := false
+	for _, definition := range definitions {
+		column, err := newSchemaColumn(definition)
+		if err != nil {
+			return fmt.Errorf("invalid columns: column %q: %w", definition.Name(), err)
+		}
+
+		index, exists := updated.columnIndex(column.Name)
+		if !exists {
+			updated.Columns = append(updated.Columns, column)
+			changed = true
+
+			continue
+		}
+
+		existing := updated.Columns[index]
+		if existing.Type != column.Type {
+			return fmt.Errorf("column %q has type %q, want %q", column.Name, existing.Type, column.Type)
+		}
+
+		options := slices.Clone(existing.Options)
+		for _, option := range column.Options {
+			options = appendOption(options, option)
+		}
+		if len(options) != len(existing.Options) {
+			updated.Columns[index].Options = options
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if len(updated.Columns) > len(schema.Columns) {
+		if err := rewriteData(file, conn.dataPath(tableID), schema, updated); err != nil {
+			return fmt.Errorf("failed to add columns to table %q: %w", tableID, err)
+		}
+	}
+
+	return conn.writeSchema(tableID, updated)
+}
+
+func ensureColumnDefinitions(columns []sheet.Column) ([]sheet.ColumnDefinition, error) {
+	definitions := make([]sheet.ColumnDefinition, 0, len(columns))
+	for columnIndex, column := range columns {
+		if column == nil {
+			return nil, fmt.Errorf("invalid columns: column %d is nil", columnIndex)
+		}
+
+		definition := column.Definition()
+		if err := definition.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid columns: column %d: %w", columnIndex, err)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+// rewriteData copies every stored row under the updated header. Values keep their original type
+// when they parse as one, so XLSX numbers and dates stay typed.
+func rewriteData(file tableFile, path string, schema, updated tableSchema) error {
+	records, err := file.read(path)
+	if err != nil {
+		return err
+	}
+
+	return file.write(path, updated, storedRecords(schema, records, len(updated.Columns)))
+}
+
+// storedRecords converts the data records below the header back to stored values, padded to the
+// given number of columns.
+func storedRecords(schema tableSchema, records [][]string, columnCount int) [][]any {
+	stored := make([][]any, 0, max(len(records)-1, 0))
+	for _, record := range records[min(1, len(records)):] {
+		values := make([]any, columnCount)
+		for index, column := range schema.Columns {
+			if index >= len(record) {
+				break
+			}
+
+			values[index] = storedValue(column.Type, record[index])
+		}
+		stored = append(stored, values)
+	}
+
+	return stored
+}
+
+func storedValue(columnType sheet.ColumnType, value string) any {
+	if value == "" {
+		return nil
+	}
+
+	cell, ok, err := mapValueToCell(columnType, value)
+	if err != nil || !ok {
+		return value
+	}
+
+	switch cell := cell.(type) {
+	case sheet.NumberCell:
+		return float64(cell)
+	case sheet.DateCell:
+		return time.Time(cell)
+	case sheet.CheckboxCell:
+		return bool(cell)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,206 @@
+package filesheet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+const (
+	schemaFileSuffix = ".schema.json"
+	directoryPerm    = 0o750
+	filePerm         = 0o600
+)
+
+type conn struct {
+	directory string
+	format    entity.FileFormat
+}
+
+// Client stores every table as a data file plus a schema sidecar holding the column types, which
+// plain CSV and XLSX cells cannot express.
+type Client struct {
+	conns map[string]conn
+	files map[entity.FileFormat]tableFile
+
+	mutex sync.Mutex
+}
+
+func NewClient(env *config.Env) *Client {
+	conns := map[string]conn{}
+	for _, ingestProfile := range env.IngestProfiles {
+		if ingestProfile.SheetProviderOrDefault() != entity.SheetProviderFile || ingestProfile.File == nil {
+			continue
+		}
+
+		conns[ingestProfile.ID] = conn{
+			directory: ingestProfile.File.Directory,
+			format:    ingestProfile.File.FormatOrDefault(),
+		}
+	}
+
+	return &Client{
+		conns: conns,
+		files: map[entity.FileFormat]tableFile{
+			entity.FileFormatCSV:  csvFile{},
+			entity.FileFormatXLSX: xlsxFile{},
+		},
+	}
+}
+
+// tableFile reads and writes the records of a table, header included. Records hold strings,
+// float64 numbers, time.Time dates or nil for empty cells.
+type tableFile interface {
+	read(path string) ([][]string, error)
+	write(path string, schema tableSchema, records [][]any) error
+	append(path string, schema tableSchema, records [][]any) error
+}
+
+type tableSchema struct {
+	Title   string            `json:"title"`
+	Format  entity.FileFormat `json:"format"`
+	Columns []schemaColumn    `json:"columns"`
+}
+
+type schemaColumn struct {
+	Name     string           `json:"name"`
+	Type     sheet.ColumnType `json:"type"`
+	Currency sheet.Currency   `json:"currency,omitempty"`
+	Options  []string         `json:"options,omitempty"`
+	Hidden   bool             `json:"hidden,omitempty"`
+}
+
+func (s tableSchema) columnIndex(name string) (int, bool) {
+	for index, column := range s.Columns {
+		if column.Name == name {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
+func (s tableSchema) header() []any {
+	header := make([]any, 0, len(s.Columns))
+	for _, column := range s.Columns {
+		header = append(header, column.Name)
+	}
+
+	return header
+}
+
+func (c *Client) connection(connectionID string) (conn, tableFile, error) {
+	conn, ok := c.conns[connectionID]
+	if !ok {
+		return conn, nil, errors.New("connection not found for ingest profile " + connectionID)
+	}
+
+	file, ok := c.files[conn.format]
+	if !ok {
+		return conn, nil, fmt.Errorf("unsupported file format %q", conn.format)
+	}
+
+	return conn, file, nil
+}
+
+func (c conn) schemaPath(tableID string) string {
+	return filepath.Join(c.directory, tableID+schemaFileSuffix)
+}
+
+func (c conn) dataPath(tableID string) string {
+	return filepath.Join(c.directory, tableID+"."+string(c.format))
+}
+
+// tableID derives a file name from a table title, replacing characters that are not allowed in
+// file names on common file systems.
+func tableID(title string) string {
+	id := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '-'
+		default:
+			return r
+		}
+	}, strings.TrimSpace(title))
+	id = strings.TrimLeft(id, ".")
+	if id == "" {
+		return "table"
+	}
+
+	return id
+}
+
+func (c conn) readSchema(tableID string) (tableSchema, error) {
+	if tableID == "" || tableID != filepath.Base(tableID) {
+		return tableSchema{}, fmt.Errorf("invalid table id %q", tableID)
+	}
+
+	data, err := os.ReadFile(c.schemaPath(tableID))
+	if err != nil {
+		return tableSchema{}, fmt.Errorf("failed to read schema of table %q: %w", tableID, err)
+	}
+
+	var schema tableSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return tableSchema{}, fmt.Errorf("failed to unmarshal schema of table %q: %w", tableID, err)
+	}
+
+	return schema, nil
+}
+
+func (c conn) writeSchema(tableID string, schema tableSchema) error {
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema of table %q: %w", tableID, err)
+	}
+
+	return writeFileAtomically(c.schemaPath(tableID), func(writer io.Writer) error {
+		_, err := writer.Write(data)
+
+		return err
+	})
+}
+
+// writeFileAtomically writes to a temporary file in the same directory and renames it over path, so
+// readers never observe a partially written table.
+func writeFileAtomically(path string, write func(io.Writer) error) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+	}
+	tempPath := file.Name()
+	defer func() {
+		// The temporary file no longer exists after a successful rename.
+		_ = os.Remove(tempPath)
+	}()
+
+	if err := write(file); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Chmod(filePerm); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to set permissions of %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
+
+var _ sheet.Provider = (*Client)(nil)
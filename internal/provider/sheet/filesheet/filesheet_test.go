@@ -0,0 +1,377 @@
+package filesheet
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+func TestTablesRoundTripThroughEveryFormat(t *testing.T) {
+	for _, format := range []entity.FileFormat{entity.FileFormatCSV, entity.FileFormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			directory := filepath.Join(t.TempDir(), "tables")
+			client := testClient(directory, format)
+
+			tables, err := client.ListTables(t.Context(), "connection")
+			if err != nil || len(tables) != 0 {
+				t.Fatalf("ListTables() before create = %#v, %v", tables, err)
+			}
+
+			table, err := client.CreateTable(t.Context(), "connection", testDefinition("August/2026"))
+			if err != nil {
+				t.Fatalf("CreateTable() error = %v", err)
+			}
+			if table != (sheet.Table{ID: "August-2026", Title: "August/2026"}) {
+				t.Fatalf("table = %#v", table)
+			}
+			if _, err := os.Stat(filepath.Join(directory, "August-2026."+string(format))); err != nil {
+				t.Fatalf("data file: %v", err)
+			}
+
+			tables, err = client.ListTables(t.Context(), "connection")
+			if err != nil || !reflect.DeepEqual(tables, []sheet.Table{table}) {
+				t.Fatalf("ListTables() = %#v, %v", tables, err)
+			}
+
+			rows := []sheet.Row{
+				{
+					"Name":     sheet.TitleCell("Store, Inc"),
+					"Category": sheet.SelectCell("Food"),
+					"Amount":   sheet.NumberCell(10.5),
+					"Notes":    sheet.TextCell("0042"),
+					"Date":     sheet.DateCell(time.Date(2026, time.August, 2, 15, 30, 0, 0, time.UTC)),
+				},
+				{
+					"Name":   sheet.TitleCell("Market"),
+					"Amount": sheet.NumberCell(-3),
+				},
+			}
+			for _, row := range rows {
+				if err := client.InsertRow(t.Context(), "connection", table.ID, row); err != nil {
+					t.Fatalf("InsertRow() error = %v", err)
+				}
+			}
+
+			got, err := client.ListRows(t.Context(), "connection", table.ID)
+			if err != nil {
+				t.Fatalf("ListRows() error = %v", err)
+			}
+			want := []sheet.Record{{ID: "1", Row: rows[0]}, {ID: "2", Row: sheet.Row{
+				"Name":   sheet.TitleCell("Market"),
+				"Amount": sheet.NumberCell(-3),
+				"Notes":  sheet.TextCell(""),
+			}}}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("records = %#v, want %#v", got, want)
+			}
+
+			err = client.EnsureTableColumns(
+				t.Context(),
+				"connection",
+				table.ID,
+				sheet.NewSelectColumn("Category").Options(sheet.NewSelectOption("Home")),
+				sheet.NewCheckboxColumn("Reviewed"),
+				sheet.NewSelectColumn("Budget group").Options(sheet.NewSelectOption("Needs")),
+			)
+			if err != nil {
+				t.Fatalf("EnsureTableColumns() error = %v", err)
+			}
+
+			row := sheet.Row{
+				"Name":         sheet.TitleCell("Rent"),
+				"Reviewed":     sheet.CheckboxCell(true),
+				"Budget group": sheet.SelectCell("Needs"),
+			}
+			if err := client.InsertRow(t.Context(), "connection", table.ID, row); err != nil {
+				t.Fatalf("InsertRow() after ensure error = %v", err)
+			}
+			update := sheet.Row{"Amount": sheet.NumberCell(-4), "Budget group": sheet.SelectCell("Needs")}
+			if err := client.UpdateRow(t.Context(), "connection", table.ID, "2", update); err != nil {
+				t.Fatalf("UpdateRow() error = %v", err)
+			}
+			got, err = client.ListRows(t.Context(), "connection", table.ID)
+			if err != nil {
+				t.Fatalf("ListRows() after ensure error = %v", err)
+			}
+			if len(got) != 3 || !reflect.DeepEqual(got[0].Row, rows[0]) ||
+				got[1].Row["Name"] != sheet.TitleCell("Market") || got[1].Row["Amount"] != sheet.NumberCell(-4) ||
+				got[1].Row["Budget group"] != sheet.SelectCell("Needs") ||
+				got[2].Row["Budget group"] != sheet.SelectCell("Needs") ||
+				got[2].Row["Reviewed"] != sheet.CheckboxCell(true) {
+				t.Fatalf("records after ensure = %#v", got)
+			}
+
+			if err := client.DeleteRow(t.Context(), "connection", table.ID, "1"); err != nil {
+				t.Fatalf("DeleteRow() error = %v", err)
+			}
+			remaining, err := client.ListRows(t.Context(), "connection", table.ID)
+			if err != nil {
+				t.Fatalf("ListRows() after delete error = %v", err)
+			}
+			want = []sheet.Record{{ID: "1", Row: got[1].Row}, {ID: "2", Row: got[2].Row}}
+			if !reflect.DeepEqual(remaining, want) {
+				t.Fatalf("records after delete = %#v, want %#v", remaining, want)
+			}
+
+			schema, err := client.conns["connection"].readSchema(table.ID)
+			if err != nil {
+				t.Fatalf("readSchema() error = %v", err)
+			}
+			if !reflect.DeepEqual(schema.Columns[1].Options, []string{"Food", "Home"}) ||
+				schema.Columns[len(schema.Columns)-1].Name != "Budget group" {
+				t.Fatalf("schema = %#v", schema)
+			}
+		})
+	}
+}
+
+func TestCreateTableRejectsExistingTable(t *testing.T) {
+	client := testClient(t.TempDir(), entity.FileFormatCSV)
+	if _, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026")); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	if _, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026")); err == nil {
+		t.Fatal("CreateTable() error = nil, want existing table error")
+	}
+}
+
+func TestListTablesSkipsOtherFormatsAndOrphanedSchemas(t *testing.T) {
+	directory := t.TempDir()
+	if _, err := testClient(directory, entity.FileFormatXLSX).
+		CreateTable(t.Context(), "connection", testDefinition("Jan 2026")); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	client := testClient(directory, entity.FileFormatCSV)
+	if _, err := client.CreateTable(t.Context(), "connection", testDefinition("Feb 2026")); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if _, err := client.CreateTable(t.Context(), "connection", testDefinition("Mar 2026")); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if err := os.Remove(filepath.Join(directory, "Mar 2026.csv")); err != nil {
+		t.Fatalf("remove data file: %v", err)
+	}
+
+	tables, err := client.ListTables(t.Context(), "connection")
+	if err != nil {
+		t.Fatalf("ListTables() error = %v", err)
+	}
+	if want := []sheet.Table{{ID: "Feb 2026", Title: "Feb 2026"}}; !reflect.DeepEqual(tables, want) {
+		t.Fatalf("tables = %#v, want %#v", tables, want)
+	}
+}
+
+func TestListRowsSkipsMalformedRecords(t *testing.T) {
+	directory := t.TempDir()
+	client := testClient(directory, entity.FileFormatCSV)
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	data := "Name,Category,Amount,Notes,Date\n" +
+		"Store,Food,10,,2026-01-02T10:00:00-03:00\n" +
+		",,,,\n" +
+		"Broken,Food,ten,,\n" +
+		"Late,,,,not a date\n"
+	if err := os.WriteFile(filepath.Join(directory, "Jan 2026.csv"), []byte(data), filePerm); err != nil {
+		t.Fatalf("write data: %v", err)
+	}
+
+	records, err := client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "1" || records[0].Row["Name"] != sheet.TitleCell("Store") ||
+		records[0].Row["Amount"] != sheet.NumberCell(10) {
+		t.Fatalf("records = %#v, want only the Store row", records)
+	}
+	wantDate := time.Date(2026, time.January, 2, 13, 0, 0, 0, time.UTC)
+	if date, ok := records[0].Row["Date"].(sheet.DateCell); !ok || !time.Time(date).Equal(wantDate) {
+		t.Fatalf("date = %#v, want %s", records[0].Row["Date"], wantDate)
+	}
+
+	if err := client.UpdateRow(t.Context(), "connection", table.ID, "4", sheet.Row{
+		"Date": sheet.DateCell(wantDate),
+	}); err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	records, err = client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil || len(records) != 2 || records[1].ID != "4" || records[1].Row["Name"] != sheet.TitleCell("Late") {
+		t.Fatalf("records after update = %#v, %v", records, err)
+	}
+}
+
+func TestInsertRowRejectsInvalidCells(t *testing.T) {
+	client := testClient(t.TempDir(), entity.FileFormatCSV)
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	tests := map[string]sheet.Row{
+		"unknown column":  {"Unknown": sheet.TextCell("value")},
+		"mismatched type": {"Amount": sheet.TextCell("10")},
+		"nil cell":        {"Name": nil},
+	}
+	for name, row := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := client.InsertRow(t.Context(), "connection", table.ID, row); err == nil {
+				t.Fatal("InsertRow() error = nil, want invalid row error")
+			}
+		})
+	}
+}
+
+func TestInsertRowsAppendsValidRowsInOrder(t *testing.T) {
+	for _, format := range []entity.FileFormat{entity.FileFormatCSV, entity.FileFormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			client := testClient(t.TempDir(), format)
+			table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+			if err != nil {
+				t.Fatalf("CreateTable() error = %v", err)
+			}
+			if err := client.InsertRow(t.Context(), "connection", table.ID, sheet.Row{
+				"Name": sheet.TitleCell("First"),
+			}); err != nil {
+				t.Fatalf("InsertRow() error = %v", err)
+			}
+
+			err = client.InsertRows(t.Context(), "connection", table.ID, []sheet.Row{
+				{"Name": sheet.TitleCell("Store"), "Amount": sheet.NumberCell(10)},
+				{"Unknown": sheet.TextCell("value")},
+				{"Name": sheet.TitleCell("Market")},
+			})
+			if failed := sheet.FailedRows(err, 3); len(failed) != 1 || failed[0].Index != 1 {
+				t.Fatalf("InsertRows() error = %v, want only row 1 to fail", err)
+			}
+
+			records, err := client.ListRows(t.Context(), "connection", table.ID)
+			if err != nil {
+				t.Fatalf("ListRows() error = %v", err)
+			}
+			var names []sheet.Cell
+			for _, record := range records {
+				names = append(names, record.Row["Name"])
+			}
+			want := []sheet.Cell{sheet.TitleCell("First"), sheet.TitleCell("Store"), sheet.TitleCell("Market")}
+			if !reflect.DeepEqual(names, want) || records[1].Row["Amount"] != sheet.NumberCell(10) {
+				t.Fatalf("records = %#v", records)
+			}
+		})
+	}
+}
+
+func TestEnsureTableColumnsRejectsIncompatibleColumn(t *testing.T) {
+	client := testClient(t.TempDir(), entity.FileFormatCSV)
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	err = client.EnsureTableColumns(t.Context(), "connection", table.ID, sheet.NewSelectColumn("Amount"))
+	if err == nil {
+		t.Fatal("EnsureTableColumns() error = nil, want incompatible type error")
+	}
+}
+
+func TestXLSXHidesHiddenColumns(t *testing.T) {
+	directory := t.TempDir()
+	client := testClient(directory, entity.FileFormatXLSX)
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	err = client.EnsureTableColumns(t.Context(), "connection", table.ID, sheet.NewTextColumn("External ID").Hidden())
+	if err != nil {
+		t.Fatalf("EnsureTableColumns() error = %v", err)
+	}
+
+	file, err := excelize.OpenFile(filepath.Join(directory, table.ID+".xlsx"))
+	if err != nil {
+		t.Fatalf("open workbook: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	for column, want := range map[string]bool{"E": true, "F": false} {
+		visible, err := file.GetColVisible(file.GetSheetName(0), column)
+		if err != nil || visible != want {
+			t.Fatalf("column %s visible = %t, %v, want %t", column, visible, err, want)
+		}
+	}
+}
+
+func TestOperationsRejectMissingConnectionAndInvalidTableID(t *testing.T) {
+	client := testClient(t.TempDir(), entity.FileFormatCSV)
+	if _, err := client.ListTables(t.Context(), "missing"); err == nil ||
+		!strings.Contains(err.Error(), "connection not found") {
+		t.Fatalf("ListTables() error = %v, want missing connection", err)
+	}
+	if _, err := client.ListRows(t.Context(), "connection", "../outside"); err == nil {
+		t.Fatal("ListRows() error = nil, want invalid table id")
+	}
+
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	for _, rowID := range []string{"", "0", "1", "row"} {
+		if err := client.UpdateRow(t.Context(), "connection", table.ID, rowID, sheet.Row{}); err == nil {
+			t.Fatalf("UpdateRow(%q) error = nil, want invalid or missing row", rowID)
+		}
+		if err := client.DeleteRow(t.Context(), "connection", table.ID, rowID); err == nil {
+			t.Fatalf("DeleteRow(%q) error = nil, want invalid or missing row", rowID)
+		}
+	}
+}
+
+func TestNewClientOnlyConfiguresFileProfiles(t *testing.T) {
+	client := NewClient(&config.Env{IngestProfilesFileData: config.IngestProfilesFileData{
+		IngestProfiles: []entity.IngestProfile{
+			{ID: "notion"},
+			{
+				ID:            "file",
+				SheetProvider: entity.SheetProviderFile,
+				File:          &entity.FileConnection{Directory: "tables"},
+			},
+		},
+	}})
+
+	want := map[string]conn{"file": {directory: "tables", format: entity.FileFormatCSV}}
+	if !reflect.DeepEqual(client.conns, want) {
+		t.Fatalf("conns = %#v, want %#v", client.conns, want)
+	}
+}
+
+func testDefinition(title string) sheet.TableDefinition {
+	return sheet.NewTable(title).
+		SetIcon("💸").
+		AddColumn(sheet.NewTitleColumn("Name")).
+		AddColumn(sheet.NewSelectColumn("Category").Options(sheet.NewSelectOption("Food").Color("red"))).
+		AddColumn(sheet.NewNumberColumn("Amount").Currency(sheet.Currency("BRL"))).
+		AddColumn(sheet.NewTextColumn("Notes")).
+		AddColumn(sheet.NewDateColumn("Date"))
+}
+
+func testClient(directory string, format entity.FileFormat) *Client {
+	return NewClient(&config.Env{IngestProfilesFileData: config.IngestProfilesFileData{
+		IngestProfiles: []entity.IngestProfile{{
+			ID:            "connection",
+			SheetProvider: entity.SheetProviderFile,
+			File:          &entity.FileConnection{Directory: directory, Format: format},
+		}},
+	}})
+}
@@ -0,0 +1,90 @@
+package filesheet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+func (c *Client) InsertRow(
+	_ context.Context,
+	connectionID, tableID string,
+	row sheet.Row,
+) error {
+	conn, file, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	schema, err := conn.readSchema(tableID)
+	if err != nil {
+		return err
+	}
+
+	record, err := rowRecord(schema, row)
+	if err != nil {
+		return fmt.Errorf("invalid row: %w", err)
+	}
+
+	if err := file.append(conn.dataPath(tableID), schema, [][]any{record}); err != nil {
+		return fmt.Errorf("failed to insert row into table %q: %w", tableID, err)
+	}
+
+	return nil
+}
+
+// rowRecord orders the row cells by the table columns, leaving absent cells empty.
+func rowRecord(schema tableSchema, row sheet.Row) ([]any, error) {
+	record := make([]any, len(schema.Columns))
+	for name, cell := range row {
+		index, exists := schema.columnIndex(name)
+		if !exists {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+
+		value, err := cellValue(schema.Columns[index].Type, cell)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", name, err)
+		}
+		record[index] = value
+	}
+
+	return record, nil
+}
+
+func cellValue(columnType sheet.ColumnType, cell sheet.Cell) (any, error) {
+	if cell == nil {
+		return nil, errors.New("cell is nil")
+	}
+
+	switch value := cell.(type) {
+	case sheet.TitleCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeTitle, string(value))
+	case sheet.TextCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeText, string(value))
+	case sheet.NumberCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeNumber, float64(value))
+	case sheet.SelectCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeSelect, string(value))
+	case sheet.DateCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeDate, time.Time(value))
+	case sheet.CheckboxCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeCheckbox, bool(value))
+	default:
+		return nil, fmt.Errorf("unsupported cell type %T", cell)
+	}
+}
+
+func cellValueOfType(columnType, cellType sheet.ColumnType, value any) (any, error) {
+	if columnType != cellType {
+		return nil, fmt.Errorf("cell type %q does not match column type %q", cellType, columnType)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,57 @@
+package filesheet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// InsertRows appends every valid row with a single write to the data file. Invalid rows are
+// reported and skipped.
+func (c *Client) InsertRows(
+	_ context.Context,
+	connectionID, tableID string,
+	rows []sheet.Row,
+) error {
+	conn, file, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	schema, err := conn.readSchema(tableID)
+	if err != nil {
+		return err
+	}
+
+	var rowErrors []sheet.RowError
+	records := make([][]any, 0, len(rows))
+	indexes := make([]int, 0, len(rows))
+	for index, row := range rows {
+		record, err := rowRecord(schema, row)
+		if err != nil {
+			rowErrors = append(rowErrors, sheet.RowError{Index: index, Err: fmt.Errorf("invalid row: %w", err)})
+
+			continue
+		}
+		records = append(records, record)
+		indexes = append(indexes, index)
+	}
+
+	if len(records) > 0 {
+		if err := file.append(conn.dataPath(tableID), schema, records); err != nil {
+			err = fmt.Errorf("failed to insert rows into table %q: %w", tableID, err)
+			for _, index := range indexes {
+				rowErrors = append(rowErrors, sheet.RowError{Index: index, Err: err})
+			}
+		}
+	}
+
+	return sheet.NewInsertRowsError(len(rows), rowErrors)
+}
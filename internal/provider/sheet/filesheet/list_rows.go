@@ -0,0 +1,151 @@
+package filesheet
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+func (c *Client) ListRows(
+	_ context.Context,
+	connectionID, tableID string,
+) ([]sheet.Record, error) {
+	conn, file, err := c.connection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	schema, err := conn.readSchema(tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := file.read(conn.dataPath(tableID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rows of table %q: %w", tableID, err)
+	}
+
+	return processRows(schema, records), nil
+}
+
+// processRows skips the header and any row that does not match the column types, so a malformed
+// line edited by hand does not block the whole table. Each record is identified by its data row
+// number, counted from 1 below the header.
+func processRows(schema tableSchema, records [][]string) []sheet.Record {
+	if len(records) == 0 {
+		return nil
+	}
+
+	rows := make([]sheet.Record, 0, len(records)-1)
+	for index, record := range records {
+		if index == 0 || isEmptyRecord(record) {
+			continue
+		}
+
+		row, err := mapRecordToRow(schema, record)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, sheet.Record{ID: strconv.Itoa(index), Row: row})
+	}
+
+	return rows
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, value := range record {
+		if value != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mapRecordToRow(schema tableSchema, record []string) (sheet.Row, error) {
+	row := make(sheet.Row, len(schema.Columns))
+	for index, column := range schema.Columns {
+		var value string
+		if index < len(record) {
+			value = record[index]
+		}
+
+		cell, ok, err := mapValueToCell(column.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", column.Name, err)
+		}
+		if ok {
+			row[column.Name] = cell
+		}
+	}
+
+	return row, nil
+}
+
+func mapValueToCell(columnType sheet.ColumnType, value string) (sheet.Cell, bool, error) {
+	switch columnType {
+	case sheet.ColumnTypeTitle:
+		return sheet.TitleCell(value), true, nil
+	case sheet.ColumnTypeText:
+		return sheet.TextCell(value), true, nil
+	case sheet.ColumnTypeSelect:
+		if value == "" {
+			return nil, false, nil
+		}
+
+		return sheet.SelectCell(value), true, nil
+	case sheet.ColumnTypeNumber:
+		if value == "" {
+			return nil, false, nil
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse number %s: %w", value, err)
+		}
+
+		return sheet.NumberCell(number), true, nil
+	case sheet.ColumnTypeDate:
+		if value == "" {
+			return nil, false, nil
+		}
+		date, err := parseDate(value)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return sheet.DateCell(date), true, nil
+	case sheet.ColumnTypeCheckbox:
+		if value == "" {
+			return nil, false, nil
+		}
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse checkbox %s: %w", value, err)
+		}
+
+		return sheet.CheckboxCell(checked), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// parseDate accepts the RFC 3339 text written to CSV files and the serial numbers of XLSX date
+// cells.
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse date %s", value)
+	}
+
+	return serialToTime(serial), nil
+}
@@ -0,0 +1,54 @@
+package filesheet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// ListTables returns the tables of the configured format that have both a schema and a data file.
+// A missing directory has no tables yet; CreateTable creates it.
+func (c *Client) ListTables(
+	_ context.Context,
+	connectionID string,
+) ([]sheet.Table, error) {
+	conn, _, err := c.connection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries, err := os.ReadDir(conn.directory)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	var tables []sheet.Table
+	for _, entry := range entries {
+		id, isSchema := strings.CutSuffix(entry.Name(), schemaFileSuffix)
+		if !isSchema || entry.IsDir() {
+			continue
+		}
+
+		schema, err := conn.readSchema(id)
+		if err != nil || schema.Format != conn.format {
+			continue
+		}
+		if _, err := os.Stat(conn.dataPath(id)); err != nil {
+			continue
+		}
+
+		tables = append(tables, sheet.Table{ID: id, Title: schema.Title})
+	}
+
+	return tables, nil
+}
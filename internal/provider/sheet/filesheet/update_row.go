@@ -0,0 +1,62 @@
+package filesheet
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// UpdateRow rewrites the data file with the row cells set on the record with the given data row
+// number. The other cells of the record keep their stored values.
+func (c *Client) UpdateRow(
+	_ context.Context,
+	connectionID, tableID, rowID string,
+	row sheet.Row,
+) error {
+	conn, file, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	rowNumber, err := strconv.Atoi(rowID)
+	if err != nil || rowNumber < 1 {
+		return fmt.Errorf("invalid row id %q", rowID)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	schema, err := conn.readSchema(tableID)
+	if err != nil {
+		return err
+	}
+
+	updates, err := rowRecord(schema, row)
+	if err != nil {
+		return fmt.Errorf("invalid row: %w", err)
+	}
+
+	path := conn.dataPath(tableID)
+	records, err := file.read(path)
+	if err != nil {
+		return fmt.Errorf("failed to update row %q of table %q: %w", rowID, tableID, err)
+	}
+	if rowNumber >= len(records) {
+		return fmt.Errorf("row %q not found in table %q", rowID, tableID)
+	}
+
+	stored := storedRecords(schema, records, len(schema.Columns))
+	for index, value := range updates {
+		if value != nil {
+			stored[rowNumber-1][index] = value
+		}
+	}
+
+	if err := file.write(path, schema, stored); err != nil {
+		return fmt.Errorf("failed to update row %q of table %q: %w", rowID, tableID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package filesheet
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+const (
+	brlCurrencyFormat = `"R$" #,##0.00`
+	dateTimeFormat    = "yyyy-mm-dd hh:mm"
+	hoursInDay        = 24
+)
+
+// serialEpoch is the zero day of spreadsheet serial dates. XLSX date cells carry no time zone, so
+// dates are stored and read back in UTC.
+var serialEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxFile keeps each table in the first worksheet of its workbook, with typed number and date
+// cells.
+type xlsxFile struct{}
+
+func (xlsxFile) read(path string) ([][]string, error) {
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	records, err := file.GetRows(file.GetSheetName(0), excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return records, nil
+}
+
+func (xlsxFile) write(path string, schema tableSchema, records [][]any) error {
+	file := excelize.NewFile()
+	defer func() {
+		_ = file.Close()
+	}()
+
+	worksheet := file.GetSheetName(0)
+	if err := formatWorksheet(file, worksheet, schema); err != nil {
+		return err
+	}
+	header := schema.header()
+	if err := file.SetSheetRow(worksheet, "A1", &header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for index, record := range records {
+		if err := setRecord(file, worksheet, index+2, record); err != nil {
+			return err
+		}
+	}
+
+	return saveWorkbook(file, path)
+}
+
+func (xlsxFile) append(path string, _ tableSchema, records [][]any) error {
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	worksheet := file.GetSheetName(0)
+	rows, err := file.GetRows(worksheet)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for index, record := range records {
+		if err := setRecord(file, worksheet, len(rows)+index+1, record); err != nil {
+			return err
+		}
+	}
+
+	return saveWorkbook(file, path)
+}
+
+func formatWorksheet(file *excelize.File, worksheet string, schema tableSchema) error {
+	if err := file.SetPanes(worksheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return fmt.Errorf("failed to freeze header: %w", err)
+	}
+
+	for index, column := range schema.Columns {
+		columnName, err := excelize.ColumnNumberToName(index + 1)
+		if err != nil {
+			return fmt.Errorf("failed to name column %d: %w", index, err)
+		}
+		if column.Hidden {
+			if err := file.SetColVisible(worksheet, columnName, false); err != nil {
+				return fmt.Errorf("failed to hide column %q: %w", column.Name, err)
+			}
+		}
+
+		var format string
+		switch {
+		case column.Type == sheet.ColumnTypeNumber && column.Currency != "":
+			format = brlCurrencyFormat
+		case column.Type == sheet.ColumnTypeDate:
+			format = dateTimeFormat
+		default:
+			continue
+		}
+
+		style, err := file.NewStyle(&excelize.Style{CustomNumFmt: &format})
+		if err != nil {
+			return fmt.Errorf("failed to create style for column %q: %w", column.Name, err)
+		}
+		if err := file.SetColStyle(worksheet, columnName, style); err != nil {
+			return fmt.Errorf("failed to format column %q: %w", column.Name, err)
+		}
+	}
+
+	bold, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+	if err := file.SetRowStyle(worksheet, 1, 1, bold); err != nil {
+		return fmt.Errorf("failed to format header: %w", err)
+	}
+
+	return nil
+}
+
+func setRecord(file *excelize.File, worksheet string, row int, record []any) error {
+	values := make([]any, len(record))
+	for index, value := range record {
+		if date, ok := value.(time.Time); ok {
+			values[index] = timeToSerial(date)
+
+			continue
+		}
+		values[index] = value
+	}
+
+	if err := file.SetSheetRow(worksheet, fmt.Sprintf("A%d", row), &values); err != nil {
+		return fmt.Errorf("failed to write row %d: %w", row, err)
+	}
+
+	return nil
+}
+
+func saveWorkbook(file *excelize.File, path string) error {
+	return writeFileAtomically(path, func(writer io.Writer) error {
+		return file.Write(writer)
+	})
+}
+
+func timeToSerial(value time.Time) float64 {
+	return value.UTC().Sub(serialEpoch).Hours() / hoursInDay
+}
+
+func serialToTime(serial float64) time.Time {
+	duration := time.Duration(math.Round(serial * hoursInDay * float64(time.Hour)))
+
+	return serialEpoch.Add(duration).Round(time.Second)
+}
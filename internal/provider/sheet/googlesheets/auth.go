@@ -0,0 +1,172 @@
+package googlesheets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	spreadsheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+	jwtBearerGrant    = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	assertionLifetime = time.Hour
+	tokenExpiryMargin = time.Minute
+)
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type assertionHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+type assertionClaims struct {
+	Issuer    string `json:"iss"`
+	Scope     string `json:"scope"`
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// tokenSource exchanges a signed service-account assertion for an access token and caches it
+// until shortly before it expires.
+type tokenSource struct {
+	client      *resty.Client
+	tokenURL    string
+	clientEmail string
+	privateKey  *rsa.PrivateKey
+	now         func() time.Time
+
+	mutex       sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func newTokenSource(
+	client *resty.Client,
+	tokenURL, clientEmail, privateKeyPEM string,
+) (*tokenSource, error) {
+	privateKey, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tokenSource{
+		client:      client,
+		tokenURL:    tokenURL,
+		clientEmail: clientEmail,
+		privateKey:  privateKey,
+		now:         time.Now,
+	}, nil
+}
+
+func parsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+func (s *tokenSource) token(ctx context.Context) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	if s.accessToken != "" && now.Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := s.assertion(now)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"grant_type": jwtBearerGrant,
+			"assertion":  assertion,
+		}).
+		Post(s.tokenURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to request access token: %w", err)
+	}
+
+	body := res.Body()
+	if res.IsError() {
+		return "", fmt.Errorf("error response while requesting access token: %s", body)
+	}
+
+	data := tokenResponse{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("failed to unmarshal access token: %w", err)
+	}
+	if data.AccessToken == "" {
+		return "", errors.New("access token is empty")
+	}
+
+	s.accessToken = data.AccessToken
+	s.expiresAt = now.Add(time.Duration(data.ExpiresIn)*time.Second - tokenExpiryMargin)
+
+	return s.accessToken, nil
+}
+
+func (s *tokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(assertionHeader{Algorithm: "RS256", Type: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal assertion header: %w", err)
+	}
+
+	claims, err := json.Marshal(assertionClaims{
+		Issuer:    s.clientEmail,
+		Scope:     spreadsheetsScope,
+		Audience:  s.tokenURL,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(assertionLifetime).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal assertion claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign assertion: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
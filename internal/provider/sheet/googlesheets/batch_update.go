@@ -0,0 +1,337 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+const (
+	brlCurrencyPattern = `"R$" #,##0.00`
+	dateTimePattern    = "yyyy-mm-dd hh:mm"
+	hoursInDay         = 24
+)
+
+// serialEpoch is the zero day of spreadsheet serial dates. Sheets date cells carry no time zone,
+// so dates are stored and read back in UTC.
+var serialEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+type batchUpdateReq struct {
+	Requests []batchUpdateReqItem `json:"requests"`
+}
+
+type batchUpdateReqItem struct {
+	AddSheet                  *addSheetReq                  `json:"addSheet,omitempty"`
+	AppendDimension           *appendDimensionReq           `json:"appendDimension,omitempty"`
+	UpdateCells               *updateCellsReq               `json:"updateCells,omitempty"`
+	RepeatCell                *repeatCellReq                `json:"repeatCell,omitempty"`
+	SetDataValidation         *setDataValidationReq         `json:"setDataValidation,omitempty"`
+	UpdateDimensionProperties *updateDimensionPropertiesReq `json:"updateDimensionProperties,omitempty"`
+	DeleteDimension           *deleteDimensionReq           `json:"deleteDimension,omitempty"`
+	CreateDeveloperMetadata   *createDeveloperMetadataReq   `json:"createDeveloperMetadata,omitempty"`
+	UpdateDeveloperMetadata   *updateDeveloperMetadataReq   `json:"updateDeveloperMetadata,omitempty"`
+}
+
+type addSheetReq struct {
+	Properties spreadsheetSheetProperties `json:"properties"`
+}
+
+type appendDimensionReq struct {
+	SheetID   int64  `json:"sheetId"`
+	Dimension string `json:"dimension"`
+	Length    int    `json:"length"`
+}
+
+type updateCellsReq struct {
+	Start  gridCoordinate `json:"start"`
+	Rows   []rowData      `json:"rows"`
+	Fields string         `json:"fields"`
+}
+
+type gridCoordinate struct {
+	SheetID     int64 `json:"sheetId"`
+	RowIndex    int   `json:"rowIndex"`
+	ColumnIndex int   `json:"columnIndex"`
+}
+
+type rowData struct {
+	Values []cellData `json:"values"`
+}
+
+type cellData struct {
+	UserEnteredValue  *extendedValue `json:"userEnteredValue,omitempty"`
+	UserEnteredFormat *cellFormat    `json:"userEnteredFormat,omitempty"`
+}
+
+type extendedValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type cellFormat struct {
+	NumberFormat *numberFormat `json:"numberFormat,omitempty"`
+	TextFormat   *textFormat   `json:"textFormat,omitempty"`
+}
+
+type numberFormat struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+}
+
+type textFormat struct {
+	Bold bool `json:"bold"`
+}
+
+type repeatCellReq struct {
+	Range  gridRange `json:"range"`
+	Cell   cellData  `json:"cell"`
+	Fields string    `json:"fields"`
+}
+
+type updateDimensionPropertiesReq struct {
+	Range      dimensionRange      `json:"range"`
+	Properties dimensionProperties `json:"properties"`
+	Fields     string              `json:"fields"`
+}
+
+type deleteDimensionReq struct {
+	Range dimensionRange `json:"range"`
+}
+
+type dimensionRange struct {
+	SheetID    int64  `json:"sheetId"`
+	Dimension  string `json:"dimension"`
+	StartIndex int    `json:"startIndex"`
+	EndIndex   int    `json:"endIndex"`
+}
+
+type dimensionProperties struct {
+	HiddenByUser bool `json:"hiddenByUser"`
+}
+
+type gridRange struct {
+	SheetID          int64 `json:"sheetId"`
+	StartRowIndex    int   `json:"startRowIndex"`
+	StartColumnIndex int   `json:"startColumnIndex"`
+	EndColumnIndex   int   `json:"endColumnIndex"`
+}
+
+type setDataValidationReq struct {
+	Range gridRange          `json:"range"`
+	Rule  dataValidationRule `json:"rule"`
+}
+
+type dataValidationRule struct {
+	Condition    booleanCondition `json:"condition"`
+	Strict       bool             `json:"strict"`
+	ShowCustomUI bool             `json:"showCustomUi"`
+}
+
+type booleanCondition struct {
+	Type   string           `json:"type"`
+	Values []conditionValue `json:"values,omitempty"`
+}
+
+type conditionValue struct {
+	UserEnteredValue string `json:"userEnteredValue"`
+}
+
+type createDeveloperMetadataReq struct {
+	DeveloperMetadata developerMetadata `json:"developerMetadata"`
+}
+
+type updateDeveloperMetadataReq struct {
+	DataFilters       []dataFilter      `json:"dataFilters"`
+	DeveloperMetadata developerMetadata `json:"developerMetadata"`
+	Fields            string            `json:"fields"`
+}
+
+type dataFilter struct {
+	DeveloperMetadataLookup developerMetadataLookup `json:"developerMetadataLookup"`
+}
+
+type developerMetadataLookup struct {
+	MetadataID int64 `json:"metadataId"`
+}
+
+type batchUpdateResp struct {
+	Replies []batchUpdateRespReply `json:"replies"`
+}
+
+type batchUpdateRespReply struct {
+	AddSheet                *addSheetResp                `json:"addSheet,omitempty"`
+	CreateDeveloperMetadata *createDeveloperMetadataResp `json:"createDeveloperMetadata,omitempty"`
+}
+
+type createDeveloperMetadataResp struct {
+	DeveloperMetadata developerMetadata `json:"developerMetadata"`
+}
+
+type addSheetResp struct {
+	Properties spreadsheetSheetProperties `json:"properties"`
+}
+
+func (c *Client) batchUpdate(
+	ctx context.Context,
+	conn conn,
+	requestData batchUpdateReq,
+) (batchUpdateResp, error) {
+	request, err := c.request(ctx, conn)
+	if err != nil {
+		return batchUpdateResp{}, err
+	}
+
+	res, err := request.
+		SetBody(requestData).
+		Post(spreadsheetPath(conn.spreadsheetID) + ":batchUpdate")
+	if err != nil {
+		return batchUpdateResp{}, fmt.Errorf("failed to update spreadsheet: %w", err)
+	}
+	if res.IsError() {
+		return batchUpdateResp{}, fmt.Errorf("failed to update spreadsheet with response: %s", res.Body())
+	}
+
+	data := batchUpdateResp{}
+	if err := json.Unmarshal(res.Body(), &data); err != nil {
+		return batchUpdateResp{}, fmt.Errorf("failed to unmarshal spreadsheet update: %w", err)
+	}
+
+	return data, nil
+}
+
+// columnRequests returns the header cell, the value validation, the display format and the
+// visibility of a column placed at the given index.
+func columnRequests(sheetID int64, index int, column schemaColumn) []batchUpdateReqItem {
+	requests := []batchUpdateReqItem{{UpdateCells: &updateCellsReq{
+		Start: gridCoordinate{SheetID: sheetID, ColumnIndex: index},
+		Rows: []rowData{{Values: []cellData{{
+			UserEnteredValue:  &extendedValue{StringValue: column.Name},
+			UserEnteredFormat: &cellFormat{TextFormat: &textFormat{Bold: true}},
+		}}}},
+		Fields: "userEnteredValue,userEnteredFormat.textFormat.bold",
+	}}}
+
+	switch column.Type {
+	case sheet.ColumnTypeSelect:
+		if request, ok := selectValidationRequest(sheetID, index, column.Options); ok {
+			requests = append(requests, request)
+		}
+	case sheet.ColumnTypeNumber:
+		if column.Currency != "" {
+			requests = append(requests, formatRequest(sheetID, index, numberFormat{
+				Type:    "CURRENCY",
+				Pattern: brlCurrencyPattern,
+			}))
+		}
+	case sheet.ColumnTypeDate:
+		requests = append(requests, formatRequest(sheetID, index, numberFormat{
+			Type:    "DATE_TIME",
+			Pattern: dateTimePattern,
+		}))
+	case sheet.ColumnTypeCheckbox:
+		requests = append(requests, checkboxValidationRequest(sheetID, index))
+	}
+
+	if column.Hidden {
+		requests = append(requests, batchUpdateReqItem{UpdateDimensionProperties: &updateDimensionPropertiesReq{
+			Range: dimensionRange{
+				SheetID:    sheetID,
+				Dimension:  "COLUMNS",
+				StartIndex: index,
+				EndIndex:   index + 1,
+			},
+			Properties: dimensionProperties{HiddenByUser: true},
+			Fields:     "hiddenByUser",
+		}})
+	}
+
+	return requests
+}
+
+func selectValidationRequest(sheetID int64, index int, options []string) (batchUpdateReqItem, bool) {
+	if len(options) == 0 {
+		return batchUpdateReqItem{}, false
+	}
+
+	values := make([]conditionValue, 0, len(options))
+	for _, option := range options {
+		values = append(values, conditionValue{UserEnteredValue: option})
+	}
+
+	return batchUpdateReqItem{SetDataValidation: &setDataValidationReq{
+		Range: dataRange(sheetID, index),
+		Rule: dataValidationRule{
+			Condition:    booleanCondition{Type: "ONE_OF_LIST", Values: values},
+			ShowCustomUI: true,
+		},
+	}}, true
+}
+
+// checkboxValidationRequest renders the cells of a column as checkboxes.
+func checkboxValidationRequest(sheetID int64, index int) batchUpdateReqItem {
+	return batchUpdateReqItem{SetDataValidation: &setDataValidationReq{
+		Range: dataRange(sheetID, index),
+		Rule:  dataValidationRule{Condition: booleanCondition{Type: "BOOLEAN"}},
+	}}
+}
+
+func formatRequest(sheetID int64, index int, format numberFormat) batchUpdateReqItem {
+	return batchUpdateReqItem{RepeatCell: &repeatCellReq{
+		Range:  dataRange(sheetID, index),
+		Cell:   cellData{UserEnteredFormat: &cellFormat{NumberFormat: &format}},
+		Fields: "userEnteredFormat.numberFormat",
+	}}
+}
+
+// dataRange covers every row of a column below the header.
+func dataRange(sheetID int64, index int) gridRange {
+	return gridRange{
+		SheetID:          sheetID,
+		StartRowIndex:    1,
+		StartColumnIndex: index,
+		EndColumnIndex:   index + 1,
+	}
+}
+
+func newSchemaColumn(definition sheet.ColumnDefinition) (schemaColumn, error) {
+	switch definition.Currency() {
+	case "", sheet.Currency("BRL"):
+	default:
+		return schemaColumn{}, fmt.Errorf("unsupported currency %q", definition.Currency())
+	}
+
+	column := schemaColumn{
+		Name:     definition.Name(),
+		Type:     definition.Type(),
+		Currency: definition.Currency(),
+		Hidden:   definition.Hidden(),
+	}
+	for _, option := range definition.SelectOptions() {
+		column.Options = appendOption(column.Options, option.Name())
+	}
+
+	return column, nil
+}
+
+func appendOption(options []string, option string) []string {
+	for _, existing := range options {
+		if existing == option {
+			return options
+		}
+	}
+
+	return append(options, option)
+}
+
+func timeToSerial(value time.Time) float64 {
+	return value.UTC().Sub(serialEpoch).Hours() / hoursInDay
+}
+
+func serialToTime(serial float64) time.Time {
+	duration := time.Duration(math.Round(serial * hoursInDay * float64(time.Hour)))
+
+	return serialEpoch.Add(duration).Round(time.Second)
+}
@@ -0,0 +1,92 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// CreateTable adds a tab to the spreadsheet with a bold, frozen header row. Sheets tabs have no
+// icon, so the definition icon is ignored.
+func (c *Client) CreateTable(
+	ctx context.Context,
+	connectionID string,
+	definition sheet.TableDefinition,
+) (sheet.Table, error) {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return sheet.Table{}, err
+	}
+
+	if err := definition.Validate(); err != nil {
+		return sheet.Table{}, fmt.Errorf("invalid table definition: %w", err)
+	}
+
+	columns := definition.Columns()
+	schema := tableSchema{Columns: make([]schemaColumn, 0, len(columns))}
+	for _, column := range columns {
+		schemaColumn, err := newSchemaColumn(column)
+		if err != nil {
+			return sheet.Table{}, fmt.Errorf("invalid table definition: column %q: %w", column.Name(), err)
+		}
+		schema.Columns = append(schema.Columns, schemaColumn)
+	}
+
+	metadataValue, err := encodeSchema(schema)
+	if err != nil {
+		return sheet.Table{}, err
+	}
+
+	addSheetData, err := c.batchUpdate(ctx, conn, batchUpdateReq{Requests: []batchUpdateReqItem{{
+		AddSheet: &addSheetReq{Properties: spreadsheetSheetProperties{
+			Title: definition.Title(),
+			GridProperties: &gridProperties{
+				ColumnCount:    max(len(columns), 1),
+				FrozenRowCount: 1,
+			},
+		}},
+	}}})
+	if err != nil {
+		return sheet.Table{}, fmt.Errorf("failed to create table %q: %w", definition.Title(), err)
+	}
+	if len(addSheetData.Replies) == 0 || addSheetData.Replies[0].AddSheet == nil {
+		return sheet.Table{}, errors.New("created table is missing from response")
+	}
+	properties := addSheetData.Replies[0].AddSheet.Properties
+
+	requests := make([]batchUpdateReqItem, 0, len(columns)+1)
+	for index, column := range schema.Columns {
+		requests = append(requests, columnRequests(properties.SheetID, index, column)...)
+	}
+	requests = append(requests, batchUpdateReqItem{CreateDeveloperMetadata: &createDeveloperMetadataReq{
+		DeveloperMetadata: developerMetadata{
+			MetadataKey:   schemaMetadataKey,
+			MetadataValue: metadataValue,
+			Location:      &developerMetadataLocation{SheetID: properties.SheetID},
+			Visibility:    "DOCUMENT",
+		},
+	}})
+
+	setupData, err := c.batchUpdate(ctx, conn, batchUpdateReq{Requests: requests})
+	if err != nil {
+		return sheet.Table{}, fmt.Errorf("failed to set up columns of table %q: %w", properties.Title, err)
+	}
+
+	for _, reply := range setupData.Replies {
+		if reply.CreateDeveloperMetadata == nil {
+			continue
+		}
+
+		c.storeTableLayout(conn, tableLayout{
+			sheetID:     properties.SheetID,
+			title:       properties.Title,
+			columnCount: max(len(columns), 1),
+			metadataID:  reply.CreateDeveloperMetadata.DeveloperMetadata.MetadataID,
+			schema:      schema,
+		})
+	}
+
+	return sheet.Table{ID: formatSheetID(properties.SheetID), Title: properties.Title}, nil
+}
@@ -0,0 +1,85 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// DeleteRow deletes the sheet row with the given number, shifting the rows below it up. The rows
+// deleted since the table was listed are taken into account, so rows can be deleted in any order.
+func (c *Client) DeleteRow(ctx context.Context, connectionID, tableID, rowID string) error {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	rowNumber, err := c.currentRowNumber(conn, tableID, rowID)
+	if err != nil {
+		return err
+	}
+
+	layout, err := c.cachedTableLayout(ctx, conn, tableID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.batchUpdate(ctx, conn, batchUpdateReq{Requests: []batchUpdateReqItem{{
+		DeleteDimension: &deleteDimensionReq{Range: dimensionRange{
+			SheetID:    layout.sheetID,
+			Dimension:  "ROWS",
+			StartIndex: rowNumber - 1,
+			EndIndex:   rowNumber,
+		}},
+	}}}); err != nil {
+		return fmt.Errorf("failed to delete row %q: %w", rowID, err)
+	}
+	c.recordDeletedRow(conn, tableID, rowID)
+
+	return nil
+}
+
+// currentRowNumber returns the number the row listed with the given ID has now, after the rows
+// above it that were deleted since the listing.
+func (c *Client) currentRowNumber(conn conn, tableID, rowID string) (int, error) {
+	listedNumber, err := strconv.Atoi(rowID)
+	if err != nil || listedNumber < firstDataRow {
+		return 0, fmt.Errorf("invalid row id %q", rowID)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	deleted := c.deletedRows[layoutKey(conn, tableID)]
+	if slices.Contains(deleted, listedNumber) {
+		return 0, fmt.Errorf("row %q was deleted", rowID)
+	}
+
+	rowNumber := listedNumber
+	for _, deletedNumber := range deleted {
+		if deletedNumber < listedNumber {
+			rowNumber--
+		}
+	}
+
+	return rowNumber, nil
+}
+
+func (c *Client) recordDeletedRow(conn conn, tableID, rowID string) {
+	listedNumber, _ := strconv.Atoi(rowID)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := layoutKey(conn, tableID)
+	c.deletedRows[key] = append(c.deletedRows[key], listedNumber)
+}
+
+// forgetDeletedRows starts counting deletions anew once the rows of the table are listed again.
+func (c *Client) forgetDeletedRows(conn conn, tableID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.deletedRows, layoutKey(conn, tableID))
+}
@@ -0,0 +1,122 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+func (c *Client) EnsureTableColumns(
+	ctx context.Context,
+	connectionID string,
+	tableID string,
+	columns ...sheet.Column,
+) error {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+	definitions, err := ensureColumnDefinitions(columns)
+	if err != nil {
+		return err
+	}
+
+	layout, err := c.tableLayout(ctx, conn, tableID)
+	if err != nil {
+		return err
+	}
+
+	schema := tableSchema{Columns: slices.Clone(layout.schema.Columns)}
+	var requests []batchUpdateReqItem
+	for _, definition := range definitions {
+		column, err := newSchemaColumn(definition)
+		if err != nil {
+			return fmt.Errorf("invalid columns: column %q: %w", definition.Name(), err)
+		}
+
+		index, exists := schema.columnIndex(column.Name)
+		if !exists {
+			index = len(schema.Columns)
+			schema.Columns = append(schema.Columns, column)
+			requests = append(requests, columnRequests(layout.sheetID, index, column)...)
+
+			continue
+		}
+
+		existing := schema.Columns[index]
+		if existing.Type != column.Type {
+			return fmt.Errorf("column %q has type %q, want %q", column.Name, existing.Type, column.Type)
+		}
+		if column.Type != sheet.ColumnTypeSelect {
+			continue
+		}
+
+		options := slices.Clone(existing.Options)
+		for _, option := range column.Options {
+			options = appendOption(options, option)
+		}
+		if len(options) == len(existing.Options) {
+			continue
+		}
+
+		schema.Columns[index].Options = options
+		if request, ok := selectValidationRequest(layout.sheetID, index, options); ok {
+			requests = append(requests, request)
+		}
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	metadataValue, err := encodeSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	columnCount := layout.columnCount
+	if missing := len(schema.Columns) - columnCount; missing > 0 {
+		requests = append([]batchUpdateReqItem{{AppendDimension: &appendDimensionReq{
+			SheetID:   layout.sheetID,
+			Dimension: "COLUMNS",
+			Length:    missing,
+		}}}, requests...)
+		columnCount = len(schema.Columns)
+	}
+	requests = append(requests, batchUpdateReqItem{UpdateDeveloperMetadata: &updateDeveloperMetadataReq{
+		DataFilters: []dataFilter{{
+			DeveloperMetadataLookup: developerMetadataLookup{MetadataID: layout.metadataID},
+		}},
+		DeveloperMetadata: developerMetadata{MetadataValue: metadataValue},
+		Fields:            "metadataValue",
+	}})
+
+	if _, err := c.batchUpdate(ctx, conn, batchUpdateReq{Requests: requests}); err != nil {
+		return fmt.Errorf("failed to update columns of table %q: %w", layout.title, err)
+	}
+
+	layout.columnCount = columnCount
+	layout.schema = schema
+	c.storeTableLayout(conn, layout)
+
+	return nil
+}
+
+func ensureColumnDefinitions(columns []sheet.Column) ([]sheet.ColumnDefinition, error) {
+	definitions := make([]sheet.ColumnDefinition, 0, len(columns))
+	for columnIndex, column := range columns {
+		if column == nil {
+			return nil, fmt.Errorf("invalid columns: column %d is nil", columnIndex)
+		}
+
+		definition := column.Definition()
+		if err := definition.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid columns: column %d: %w", columnIndex, err)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
@@ -0,0 +1,134 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+const (
+	baseURL  = "https://sheets.googleapis.com"
+	tokenURL = "https://oauth2.googleapis.com/token"
+
+	lettersInAlphabet = 26
+)
+
+type conn struct {
+	spreadsheetID string
+	tokens        *tokenSource
+}
+
+type Client struct {
+	client *resty.Client
+	conns  map[string]conn
+
+	mutex   sync.Mutex
+	layouts map[string]tableLayout
+	// deletedRows holds, by table, the listed numbers of the rows deleted since the table was
+	// last listed, so the record IDs ListRows returned stay valid while the rows below shift up.
+	deletedRows map[string][]int
+}
+
+func NewClient(env *config.Env) (*Client, error) {
+	client := resty.New().SetBaseURL(baseURL)
+
+	conns := map[string]conn{}
+	for _, ingestProfile := range env.IngestProfiles {
+		if ingestProfile.SheetProviderOrDefault() != entity.SheetProviderGoogleSheets ||
+			ingestProfile.GoogleSheets == nil {
+			continue
+		}
+
+		tokens, err := newTokenSource(
+			client,
+			tokenURL,
+			ingestProfile.GoogleSheets.ClientEmail,
+			ingestProfile.GoogleSheets.PrivateKey,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("configure Google Sheets ingest profile %q: %w", ingestProfile.ID, err)
+		}
+
+		conns[ingestProfile.ID] = conn{
+			spreadsheetID: ingestProfile.GoogleSheets.SpreadsheetID,
+			tokens:        tokens,
+		}
+	}
+
+	return &Client{
+		client:      client,
+		conns:       conns,
+		layouts:     map[string]tableLayout{},
+		deletedRows: map[string][]int{},
+	}, nil
+}
+
+func (c *Client) connection(connectionID string) (conn, error) {
+	conn, ok := c.conns[connectionID]
+	if !ok {
+		return conn, errors.New("connection not found for ingest profile " + connectionID)
+	}
+
+	return conn, nil
+}
+
+func (c *Client) request(ctx context.Context, conn conn) (*resty.Request, error) {
+	accessToken, err := conn.tokens.token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	return c.client.R().
+		SetContext(ctx).
+		SetAuthToken(accessToken), nil
+}
+
+func spreadsheetPath(spreadsheetID string) string {
+	return "/v4/spreadsheets/" + url.PathEscape(spreadsheetID)
+}
+
+func valuesPath(spreadsheetID, a1Range string) string {
+	return spreadsheetPath(spreadsheetID) + "/values/" + url.PathEscape(a1Range)
+}
+
+// sheetRange builds an A1 range for the given sheet, quoting the title so names with spaces and
+// apostrophes are accepted.
+func sheetRange(title, cells string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'!" + cells
+}
+
+// columnLetter converts a zero-based column index to its A1 letter, e.g. 0 to A and 26 to AA.
+func columnLetter(index int) string {
+	letters := ""
+	for index >= 0 {
+		letters = string(rune('A'+index%lettersInAlphabet)) + letters
+		index = index/lettersInAlphabet - 1
+	}
+
+	return letters
+}
+
+func formatSheetID(sheetID int64) string {
+	return strconv.FormatInt(sheetID, 10)
+}
+
+func parseSheetID(tableID string) (int64, error) {
+	sheetID, err := strconv.ParseInt(tableID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid table id %q: %w", tableID, err)
+	}
+
+	return sheetID, nil
+}
+
+var _ sheet.Provider = (*Client)(nil)
@@ -0,0 +1,723 @@
+package googlesheets
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+const testSchema = `{"columns":[` +
+	`{"name":"Name","type":"title"},` +
+	`{"name":"Category","type":"select","options":["Food"]},` +
+	`{"name":"Amount","type":"number","currency":"BRL"},` +
+	`{"name":"Notes","type":"text"},` +
+	`{"name":"Date","type":"date"}]}`
+
+func TestTokenSourceSignsAssertionAndCachesToken(t *testing.T) {
+	privateKey, privateKeyPEM := testPrivateKey(t)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		if err := request.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		if request.Form.Get("grant_type") != jwtBearerGrant {
+			t.Errorf("grant_type = %q", request.Form.Get("grant_type"))
+		}
+
+		parts := strings.Split(request.Form.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("assertion parts = %d, want 3", len(parts))
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatalf("decode signature: %v", err)
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			t.Errorf("verify signature: %v", err)
+		}
+
+		claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatalf("decode claims: %v", err)
+		}
+		var claims assertionClaims
+		if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+			t.Fatalf("unmarshal claims: %v", err)
+		}
+		if claims.Issuer != "bot@example.com" || claims.Scope != spreadsheetsScope ||
+			!strings.HasSuffix(claims.Audience, "/token") || claims.ExpiresAt-claims.IssuedAt != 3600 {
+			t.Errorf("claims = %#v", claims)
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(writer, `{"access_token":"token-%d","expires_in":3600}`, requests.Load())
+	}))
+	t.Cleanup(server.Close)
+
+	tokens, err := newTokenSource(resty.New(), server.URL+"/token", "bot@example.com", privateKeyPEM)
+	if err != nil {
+		t.Fatalf("newTokenSource() error = %v", err)
+	}
+	now := time.Date(2026, time.August, 1, 12, 0, 0, 0, time.UTC)
+	tokens.now = func() time.Time { return now }
+
+	for range 2 {
+		token, err := tokens.token(t.Context())
+		if err != nil {
+			t.Fatalf("token() error = %v", err)
+		}
+		if token != "token-1" {
+			t.Fatalf("token = %q, want token-1", token)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	token, err := tokens.token(t.Context())
+	if err != nil {
+		t.Fatalf("token() error = %v", err)
+	}
+	if token != "token-2" || requests.Load() != 2 {
+		t.Fatalf("token = %q after %d requests, want token-2 after 2", token, requests.Load())
+	}
+}
+
+func TestNewTokenSourceRejectsInvalidPrivateKey(t *testing.T) {
+	if _, err := newTokenSource(resty.New(), tokenURL, "bot@example.com", "not a key"); err == nil {
+		t.Fatal("newTokenSource() error = nil, want invalid key error")
+	}
+}
+
+func TestCreateTableAddsSheetAndStoresSchema(t *testing.T) {
+	var batches []batchUpdateReq
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost || request.URL.Path != "/v4/spreadsheets/spreadsheet:batchUpdate" {
+			t.Errorf("request = %s %s", request.Method, request.URL.Path)
+		}
+		if request.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("authorization = %q", request.Header.Get("Authorization"))
+		}
+
+		var requestData batchUpdateReq
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		batches = append(batches, requestData)
+
+		writer.Header().Set("Content-Type", "application/json")
+		if len(batches) == 1 {
+			_, _ = fmt.Fprint(writer, `{"replies":[{"addSheet":{"properties":{"sheetId":42,"title":"Jan 2026"}}}]}`)
+
+			return
+		}
+		_, _ = fmt.Fprint(writer, `{"replies":[{},{},{},{},{},{},{},{"createDeveloperMetadata":{"developerMetadata":{"metadataId":7}}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	definition := sheet.NewTable("Jan 2026").
+		SetIcon("💸").
+		AddColumn(sheet.NewTitleColumn("Name")).
+		AddColumn(sheet.NewSelectColumn("Category").Options(sheet.NewSelectOption("Food").Color("red"))).
+		AddColumn(sheet.NewNumberColumn("Amount").Currency(sheet.Currency("BRL"))).
+		AddColumn(sheet.NewTextColumn("Notes").Hidden()).
+		AddColumn(sheet.NewDateColumn("Date"))
+	client := testClient(server.URL)
+	table, err := client.CreateTable(t.Context(), "connection", definition)
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if table != (sheet.Table{ID: "42", Title: "Jan 2026"}) {
+		t.Fatalf("table = %#v", table)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("batch updates = %d, want 2", len(batches))
+	}
+
+	addSheet := batches[0].Requests[0].AddSheet
+	if addSheet == nil || addSheet.Properties.Title != "Jan 2026" ||
+		addSheet.Properties.GridProperties.ColumnCount != 5 || addSheet.Properties.GridProperties.FrozenRowCount != 1 {
+		t.Fatalf("add sheet request = %#v", batches[0].Requests[0])
+	}
+
+	var headers []string
+	var validation *setDataValidationReq
+	var formats []string
+	var hidden []dimensionRange
+	var metadata *createDeveloperMetadataReq
+	for _, item := range batches[1].Requests {
+		switch {
+		case item.UpdateCells != nil:
+			headers = append(headers, item.UpdateCells.Rows[0].Values[0].UserEnteredValue.StringValue)
+		case item.SetDataValidation != nil:
+			validation = item.SetDataValidation
+		case item.RepeatCell != nil:
+			formats = append(formats, item.RepeatCell.Cell.UserEnteredFormat.NumberFormat.Type)
+		case item.UpdateDimensionProperties != nil:
+			if item.UpdateDimensionProperties.Properties.HiddenByUser {
+				hidden = append(hidden, item.UpdateDimensionProperties.Range)
+			}
+		case item.CreateDeveloperMetadata != nil:
+			metadata = item.CreateDeveloperMetadata
+		}
+	}
+	if want := []string{"Name", "Category", "Amount", "Notes", "Date"}; !reflect.DeepEqual(headers, want) {
+		t.Fatalf("headers = %#v, want %#v", headers, want)
+	}
+	if validation == nil || validation.Range.StartColumnIndex != 1 ||
+		!reflect.DeepEqual(validation.Rule.Condition.Values, []conditionValue{{UserEnteredValue: "Food"}}) {
+		t.Fatalf("validation = %#v", validation)
+	}
+	if want := []string{"CURRENCY", "DATE_TIME"}; !reflect.DeepEqual(formats, want) {
+		t.Fatalf("formats = %#v, want %#v", formats, want)
+	}
+	wantHidden := []dimensionRange{{SheetID: 42, Dimension: "COLUMNS", StartIndex: 3, EndIndex: 4}}
+	if !reflect.DeepEqual(hidden, wantHidden) {
+		t.Fatalf("hidden columns = %#v, want %#v", hidden, wantHidden)
+	}
+	if metadata == nil || metadata.DeveloperMetadata.MetadataKey != schemaMetadataKey ||
+		metadata.DeveloperMetadata.Location.SheetID != 42 {
+		t.Fatalf("metadata = %#v", metadata)
+	}
+
+	layout, ok := client.layouts[layoutKey(client.conns["connection"], "42")]
+	if !ok || layout.metadataID != 7 || len(layout.schema.Columns) != 5 {
+		t.Fatalf("cached layout = %#v, %v", layout, ok)
+	}
+}
+
+func TestCreateTableRejectsUnsupportedCurrencyBeforeRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requests.Add(1)
+	}))
+	t.Cleanup(server.Close)
+
+	definition := sheet.NewTable("Jan 2026").
+		AddColumn(sheet.NewNumberColumn("Amount").Currency(sheet.Currency("USD")))
+	if _, err := testClient(server.URL).CreateTable(t.Context(), "connection", definition); err == nil {
+		t.Fatal("CreateTable() error = nil, want unsupported currency error")
+	}
+	if requests.Load() != 0 {
+		t.Fatalf("requests = %d, want 0", requests.Load())
+	}
+}
+
+func TestListTablesSkipsTabsWithoutSchema(t *testing.T) {
+	server := httptest.NewServer(spreadsheetHandler(t, nil))
+	t.Cleanup(server.Close)
+
+	tables, err := testClient(server.URL).ListTables(t.Context(), "connection")
+	if err != nil {
+		t.Fatalf("ListTables() error = %v", err)
+	}
+
+	want := []sheet.Table{{ID: "42", Title: "Jan 2026"}}
+	if !reflect.DeepEqual(tables, want) {
+		t.Fatalf("tables = %#v, want %#v", tables, want)
+	}
+}
+
+func TestInsertRowOrdersValuesBySchemaAndCachesLayout(t *testing.T) {
+	var spreadsheetRequests atomic.Int32
+	var appended []appendValuesReq
+	server := httptest.NewServer(spreadsheetHandler(t, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			spreadsheetRequests.Add(1)
+
+			return
+		}
+
+		if request.URL.Path != "/v4/spreadsheets/spreadsheet/values/'Jan 2026'!A1:append" {
+			t.Errorf("path = %q", request.URL.Path)
+		}
+		query := request.URL.Query()
+		if query.Get("valueInputOption") != "RAW" || query.Get("insertDataOption") != "INSERT_ROWS" {
+			t.Errorf("query = %v", query)
+		}
+
+		var requestData appendValuesReq
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		appended = append(appended, requestData)
+		_, _ = fmt.Fprint(writer, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := testClient(server.URL)
+	row := sheet.Row{
+		"Date":     sheet.DateCell(time.Date(2026, time.January, 2, 15, 30, 0, 0, time.UTC)),
+		"Amount":   sheet.NumberCell(10.5),
+		"Category": sheet.SelectCell("Food"),
+		"Name":     sheet.TitleCell("Store"),
+	}
+	for range 2 {
+		if err := client.InsertRow(t.Context(), "connection", "42", row); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+
+	if spreadsheetRequests.Load() != 1 {
+		t.Fatalf("spreadsheet requests = %d, want 1", spreadsheetRequests.Load())
+	}
+	want := []any{"Store", "Food", 10.5, nil, 46024.645833333336}
+	if len(appended) != 2 || !reflect.DeepEqual(appended[0].Values, [][]any{want}) {
+		t.Fatalf("appended = %#v, want %#v", appended, want)
+	}
+}
+
+func TestInsertRowRejectsInvalidCellsBeforeRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		row  sheet.Row
+	}{
+		{name: "unknown column", row: sheet.Row{"Unknown": sheet.TextCell("value")}},
+		{name: "mismatched type", row: sheet.Row{"Amount": sheet.TextCell("10")}},
+		{name: "nil cell", row: sheet.Row{"Name": nil}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(spreadsheetHandler(t, func(_ http.ResponseWriter, request *http.Request) {
+				if request.URL.Path != "/v4/spreadsheets/spreadsheet" {
+					t.Errorf("unexpected request %s", request.URL.Path)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			if err := testClient(server.URL).InsertRow(t.Context(), "connection", "42", test.row); err == nil {
+				t.Fatal("InsertRow() error = nil, want invalid row error")
+			}
+		})
+	}
+}
+
+func TestInsertRowsAppendsValidRowsInOneRequest(t *testing.T) {
+	var appended []appendValuesReq
+	server := httptest.NewServer(spreadsheetHandler(t, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			return
+		}
+
+		var requestData appendValuesReq
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		appended = append(appended, requestData)
+		_, _ = fmt.Fprint(writer, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	err := testClient(server.URL).InsertRows(t.Context(), "connection", "42", []sheet.Row{
+		{"Name": sheet.TitleCell("Store")},
+		{"Amount": sheet.TextCell("10")},
+		{"Name": sheet.TitleCell("Market"), "Amount": sheet.NumberCell(-3)},
+	})
+
+	failed := sheet.FailedRows(err, 3)
+	if len(failed) != 1 || failed[0].Index != 1 {
+		t.Fatalf("InsertRows() error = %v, want only row 1 to fail", err)
+	}
+	want := [][]any{{"Store", nil, nil, nil, nil}, {"Market", nil, -3.0, nil, nil}}
+	if len(appended) != 1 || !reflect.DeepEqual(appended[0].Values, want) {
+		t.Fatalf("appended = %#v, want one request with %#v", appended, want)
+	}
+}
+
+func TestListRowsMapsValuesBySchemaAndSkipsMalformedRows(t *testing.T) {
+	server := httptest.NewServer(spreadsheetHandler(t, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			return
+		}
+
+		if request.URL.Path != "/v4/spreadsheets/spreadsheet/values/'Jan 2026'!A2:E" {
+			t.Errorf("path = %q", request.URL.Path)
+		}
+		if request.URL.Query().Get("valueRenderOption") != "UNFORMATTED_VALUE" ||
+			request.URL.Query().Get("dateTimeRenderOption") != "SERIAL_NUMBER" {
+			t.Errorf("query = %v", request.URL.Query())
+		}
+		_, _ = fmt.Fprint(writer, `{"values":[
+			["Store","Food",10.5,1234,46024.645833333336],
+			["Market","",-3],
+			[],
+			["Broken","Food","ten"]
+		]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	records, err := testClient(server.URL).ListRows(t.Context(), "connection", "42")
+	if err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+
+	want := []sheet.Record{
+		{ID: "2", Row: sheet.Row{
+			"Name":     sheet.TitleCell("Store"),
+			"Category": sheet.SelectCell("Food"),
+			"Amount":   sheet.NumberCell(10.5),
+			"Notes":    sheet.TextCell("1234"),
+			"Date":     sheet.DateCell(time.Date(2026, time.January, 2, 15, 30, 0, 0, time.UTC)),
+		}},
+		{ID: "3", Row: sheet.Row{
+			"Name":   sheet.TitleCell("Market"),
+			"Amount": sheet.NumberCell(-3),
+			"Notes":  sheet.TextCell(""),
+		}},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("records = %#v, want %#v", records, want)
+	}
+}
+
+func TestUpdateRowWritesOnlySetCellsOfRow(t *testing.T) {
+	var updated updateValuesReq
+	server := httptest.NewServer(spreadsheetHandler(t, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			return
+		}
+
+		if request.Method != http.MethodPut ||
+			request.URL.Path != "/v4/spreadsheets/spreadsheet/values/'Jan 2026'!A5:E5" ||
+			request.URL.Query().Get("valueInputOption") != "RAW" {
+			t.Errorf("request = %s %s?%s", request.Method, request.URL.Path, request.URL.RawQuery)
+		}
+		if err := json.NewDecoder(request.Body).Decode(&updated); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = fmt.Fprint(writer, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := testClient(server.URL)
+	row := sheet.Row{"Amount": sheet.NumberCell(12), "Name": sheet.TitleCell("Store")}
+	if err := client.UpdateRow(t.Context(), "connection", "42", "5", row); err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	if want := [][]any{{"Store", nil, 12.0, nil, nil}}; !reflect.DeepEqual(updated.Values, want) {
+		t.Fatalf("updated values = %#v, want %#v", updated.Values, want)
+	}
+
+	for _, rowID := range []string{"", "1", "row"} {
+		if err := client.UpdateRow(t.Context(), "connection", "42", rowID, row); err == nil {
+			t.Fatalf("UpdateRow(%q) error = nil", rowID)
+		}
+	}
+}
+
+func TestDeleteRowDeletesSheetRow(t *testing.T) {
+	var requestData batchUpdateReq
+	server := httptest.NewServer(spreadsheetHandler(t, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			return
+		}
+
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = fmt.Fprint(writer, `{"replies":[{}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := testClient(server.URL)
+	if err := client.DeleteRow(t.Context(), "connection", "42", "5"); err != nil {
+		t.Fatalf("DeleteRow() error = %v", err)
+	}
+	want := []batchUpdateReqItem{{DeleteDimension: &deleteDimensionReq{Range: dimensionRange{
+		SheetID:    42,
+		Dimension:  "ROWS",
+		StartIndex: 4,
+		EndIndex:   5,
+	}}}}
+	if !reflect.DeepEqual(requestData.Requests, want) {
+		t.Fatalf("requests = %#v, want %#v", requestData.Requests, want)
+	}
+
+	for _, rowID := range []string{"", "1", "row"} {
+		if err := client.DeleteRow(t.Context(), "connection", "42", rowID); err == nil {
+			t.Fatalf("DeleteRow(%q) error = nil", rowID)
+		}
+	}
+}
+
+func TestDeleteRowKeepsListedRowIDsValid(t *testing.T) {
+	var startIndexes []int
+	server := httptest.NewServer(spreadsheetHandler(t, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			return
+		}
+		if request.Method == http.MethodGet {
+			_, _ = fmt.Fprint(writer, `{"values":[]}`)
+
+			return
+		}
+
+		var requestData batchUpdateReq
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		startIndexes = append(startIndexes, requestData.Requests[0].DeleteDimension.Range.StartIndex)
+		_, _ = fmt.Fprint(writer, `{"replies":[{}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	// Rows 3 and 5 were listed; once row 3 is gone, row 5 is the fourth row.
+	client := testClient(server.URL)
+	for _, rowID := range []string{"3", "5"} {
+		if err := client.DeleteRow(t.Context(), "connection", "42", rowID); err != nil {
+			t.Fatalf("DeleteRow(%q) error = %v", rowID, err)
+		}
+	}
+	if err := client.DeleteRow(t.Context(), "connection", "42", "3"); err == nil {
+		t.Fatal("DeleteRow() of a deleted row error = nil")
+	}
+
+	if _, err := client.ListRows(t.Context(), "connection", "42"); err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+	if err := client.DeleteRow(t.Context(), "connection", "42", "5"); err != nil {
+		t.Fatalf("DeleteRow() after listing error = %v", err)
+	}
+
+	if !reflect.DeepEqual(startIndexes, []int{2, 3, 4}) {
+		t.Fatalf("deleted start indexes = %v, want [2 3 4]", startIndexes)
+	}
+}
+
+func TestEnsureTableColumnsAppendsColumnsAndMergesOptions(t *testing.T) {
+	var requestData batchUpdateReq
+	server := httptest.NewServer(spreadsheetHandler(t, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			return
+		}
+
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = fmt.Fprint(writer, `{"replies":[]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := testClient(server.URL)
+	err := client.EnsureTableColumns(
+		t.Context(),
+		"connection",
+		"42",
+		sheet.NewSelectColumn("Category").Options(sheet.NewSelectOption("Food"), sheet.NewSelectOption("Home")),
+		sheet.NewSelectColumn("Budget group").Options(sheet.NewSelectOption("Essentials")),
+		sheet.NewCheckboxColumn("Reviewed"),
+	)
+	if err != nil {
+		t.Fatalf("EnsureTableColumns() error = %v", err)
+	}
+
+	requests := requestData.Requests
+	if len(requests) == 0 || requests[0].AppendDimension == nil || requests[0].AppendDimension.Length != 2 {
+		t.Fatalf("first request = %#v, want two appended columns", requests)
+	}
+
+	var validations []setDataValidationReq
+	var metadata *updateDeveloperMetadataReq
+	for _, item := range requests {
+		if item.SetDataValidation != nil {
+			validations = append(validations, *item.SetDataValidation)
+		}
+		if item.UpdateDeveloperMetadata != nil {
+			metadata = item.UpdateDeveloperMetadata
+		}
+	}
+	if len(validations) != 3 || validations[0].Range.StartColumnIndex != 1 ||
+		len(validations[0].Rule.Condition.Values) != 2 || validations[1].Range.StartColumnIndex != 5 ||
+		validations[2].Range.StartColumnIndex != 6 || validations[2].Rule.Condition.Type != "BOOLEAN" {
+		t.Fatalf("validations = %#v", validations)
+	}
+	if metadata == nil || metadata.DataFilters[0].DeveloperMetadataLookup.MetadataID != 7 {
+		t.Fatalf("metadata = %#v", metadata)
+	}
+
+	var schema tableSchema
+	if err := json.Unmarshal([]byte(metadata.DeveloperMetadata.MetadataValue), &schema); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+	if len(schema.Columns) != 7 || schema.Columns[5].Name != "Budget group" ||
+		!reflect.DeepEqual(schema.Columns[1].Options, []string{"Food", "Home"}) {
+		t.Fatalf("schema = %#v", schema)
+	}
+}
+
+func TestEnsureTableColumnsSkipsSatisfiedAndRejectsIncompatibleColumns(t *testing.T) {
+	var updates atomic.Int32
+	server := httptest.NewServer(spreadsheetHandler(t, func(_ http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/v4/spreadsheets/spreadsheet" {
+			updates.Add(1)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := testClient(server.URL)
+	err := client.EnsureTableColumns(
+		t.Context(),
+		"connection",
+		"42",
+		sheet.NewSelectColumn("Category").Options(sheet.NewSelectOption("Food")),
+	)
+	if err != nil {
+		t.Fatalf("EnsureTableColumns() error = %v", err)
+	}
+	if updates.Load() != 0 {
+		t.Fatalf("updates = %d, want 0", updates.Load())
+	}
+
+	err = client.EnsureTableColumns(t.Context(), "connection", "42", sheet.NewSelectColumn("Amount"))
+	if err == nil {
+		t.Fatal("EnsureTableColumns() error = nil, want incompatible type error")
+	}
+}
+
+func TestOperationsRejectMissingConnection(t *testing.T) {
+	client := testClient("http://127.0.0.1")
+	operations := map[string]func() error{
+		"CreateTable": func() error {
+			_, err := client.CreateTable(t.Context(), "missing", sheet.NewTable("Jan 2026"))
+
+			return err
+		},
+		"EnsureTableColumns": func() error {
+			return client.EnsureTableColumns(t.Context(), "missing", "42")
+		},
+		"InsertRow": func() error {
+			return client.InsertRow(t.Context(), "missing", "42", sheet.Row{})
+		},
+		"InsertRows": func() error {
+			return client.InsertRows(t.Context(), "missing", "42", []sheet.Row{{}})
+		},
+		"UpdateRow": func() error {
+			return client.UpdateRow(t.Context(), "missing", "42", "2", sheet.Row{})
+		},
+		"DeleteRow": func() error {
+			return client.DeleteRow(t.Context(), "missing", "42", "2")
+		},
+		"ListTables": func() error {
+			_, err := client.ListTables(t.Context(), "missing")
+
+			return err
+		},
+		"ListRows": func() error {
+			_, err := client.ListRows(t.Context(), "missing", "42")
+
+			return err
+		},
+	}
+
+	for name, operation := range operations {
+		t.Run(name, func(t *testing.T) {
+			if err := operation(); err == nil || !strings.Contains(err.Error(), "connection not found") {
+				t.Fatalf("error = %v, want missing connection", err)
+			}
+		})
+	}
+}
+
+func TestColumnLetter(t *testing.T) {
+	tests := map[int]string{0: "A", 4: "E", 25: "Z", 26: "AA", 51: "AZ", 701: "ZZ", 702: "AAA"}
+	for index, want := range tests {
+		if got := columnLetter(index); got != want {
+			t.Errorf("columnLetter(%d) = %q, want %q", index, got, want)
+		}
+	}
+}
+
+func TestSheetRangeQuotesTitle(t *testing.T) {
+	if got, want := sheetRange("Jan's 2026", "A1"), "'Jan''s 2026'!A1"; got != want {
+		t.Fatalf("sheetRange() = %q, want %q", got, want)
+	}
+}
+
+// spreadsheetHandler serves a spreadsheet with a managed tab (sheet 42) and an unmanaged one,
+// delegating every request to next after the spreadsheet metadata is written.
+func spreadsheetHandler(t *testing.T, next http.HandlerFunc) http.HandlerFunc {
+	t.Helper()
+
+	spreadsheet, err := json.Marshal(getSpreadsheetResp{Sheets: []spreadsheetSheet{
+		{
+			Properties: spreadsheetSheetProperties{
+				SheetID:        42,
+				Title:          "Jan 2026",
+				GridProperties: &gridProperties{ColumnCount: 5},
+			},
+			DeveloperMetadata: []developerMetadata{
+				{MetadataID: 3, MetadataKey: "other", MetadataValue: "value"},
+				{MetadataID: 7, MetadataKey: schemaMetadataKey, MetadataValue: testSchema},
+			},
+		},
+		{Properties: spreadsheetSheetProperties{SheetID: 0, Title: "Notes"}},
+	}})
+	if err != nil {
+		t.Fatalf("marshal spreadsheet: %v", err)
+	}
+
+	return func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		if request.Method == http.MethodGet && request.URL.Path == "/v4/spreadsheets/spreadsheet" {
+			if request.URL.Query().Get("fields") != spreadsheetFields {
+				t.Errorf("fields = %q", request.URL.Query().Get("fields"))
+			}
+			_, _ = writer.Write(spreadsheet)
+		}
+		if next != nil {
+			next(writer, request)
+		}
+	}
+}
+
+func testPrivateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	return privateKey, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func testClient(baseURL string) *Client {
+	return &Client{
+		client: resty.New().SetBaseURL(baseURL),
+		conns: map[string]conn{
+			"connection": {
+				spreadsheetID: "spreadsheet",
+				tokens: &tokenSource{
+					accessToken: "token",
+					expiresAt:   time.Now().Add(time.Hour),
+					now:         time.Now,
+				},
+			},
+		},
+		layouts:     map[string]tableLayout{},
+		deletedRows: map[string][]int{},
+	}
+}
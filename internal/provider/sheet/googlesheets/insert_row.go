@@ -0,0 +1,120 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+type appendValuesReq struct {
+	Values [][]any `json:"values"`
+}
+
+func (c *Client) InsertRow(
+	ctx context.Context,
+	connectionID, tableID string,
+	row sheet.Row,
+) error {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	layout, err := c.cachedTableLayout(ctx, conn, tableID)
+	if err != nil {
+		return err
+	}
+
+	values, err := rowValues(layout.schema, row)
+	if err != nil {
+		return fmt.Errorf("invalid row: %w", err)
+	}
+
+	return c.appendValues(ctx, conn, layout, [][]any{values})
+}
+
+func (c *Client) appendValues(ctx context.Context, conn conn, layout tableLayout, values [][]any) error {
+	request, err := c.request(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	requestData := appendValuesReq{Values: values}
+	res, err := request.
+		SetQueryParams(map[string]string{
+			"valueInputOption": "RAW",
+			"insertDataOption": "INSERT_ROWS",
+		}).
+		SetBody(requestData).
+		Post(valuesPath(conn.spreadsheetID, sheetRange(layout.title, "A1")) + ":append")
+	if err != nil {
+		return fmt.Errorf(
+			"failed to insert rows with request data %+v: %w",
+			requestData,
+			err,
+		)
+	}
+
+	if res.IsError() {
+		return fmt.Errorf(
+			"failed to insert rows with request data %+v and response %s",
+			requestData,
+			res.Body(),
+		)
+	}
+
+	return nil
+}
+
+// rowValues orders the row cells by the table columns, leaving absent cells empty.
+func rowValues(schema tableSchema, row sheet.Row) ([]any, error) {
+	values := make([]any, len(schema.Columns))
+	for name, cell := range row {
+		index, exists := schema.columnIndex(name)
+		if !exists {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+
+		value, err := cellValue(schema.Columns[index].Type, cell)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", name, err)
+		}
+		values[index] = value
+	}
+
+	return values, nil
+}
+
+func cellValue(columnType sheet.ColumnType, cell sheet.Cell) (any, error) {
+	if cell == nil {
+		return nil, errors.New("cell is nil")
+	}
+
+	switch value := cell.(type) {
+	case sheet.TitleCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeTitle, string(value))
+	case sheet.TextCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeText, string(value))
+	case sheet.NumberCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeNumber, float64(value))
+	case sheet.SelectCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeSelect, string(value))
+	case sheet.DateCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeDate, timeToSerial(time.Time(value)))
+	case sheet.CheckboxCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeCheckbox, bool(value))
+	default:
+		return nil, fmt.Errorf("unsupported cell type %T", cell)
+	}
+}
+
+func cellValueOfType(columnType, cellType sheet.ColumnType, value any) (any, error) {
+	if columnType != cellType {
+		return nil, fmt.Errorf("cell type %q does not match column type %q", cellType, columnType)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,53 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// InsertRows appends every valid row with a single values:append request. Invalid rows are
+// reported without being sent, and a failed request fails all the rows it carried.
+func (c *Client) InsertRows(
+	ctx context.Context,
+	connectionID, tableID string,
+	rows []sheet.Row,
+) error {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	layout, err := c.cachedTableLayout(ctx, conn, tableID)
+	if err != nil {
+		return err
+	}
+
+	var rowErrors []sheet.RowError
+	values := make([][]any, 0, len(rows))
+	indexes := make([]int, 0, len(rows))
+	for index, row := range rows {
+		rowValues, err := rowValues(layout.schema, row)
+		if err != nil {
+			rowErrors = append(rowErrors, sheet.RowError{Index: index, Err: fmt.Errorf("invalid row: %w", err)})
+
+			continue
+		}
+		values = append(values, rowValues)
+		indexes = append(indexes, index)
+	}
+
+	if len(values) > 0 {
+		if err := c.appendValues(ctx, conn, layout, values); err != nil {
+			for _, index := range indexes {
+				rowErrors = append(rowErrors, sheet.RowError{Index: index, Err: err})
+			}
+		}
+	}
+
+	return sheet.NewInsertRowsError(len(rows), rowErrors)
+}
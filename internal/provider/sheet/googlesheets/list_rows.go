@@ -0,0 +1,207 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// firstDataRow is the number of the first row below the header.
+const firstDataRow = 2
+
+type listRowsResp struct {
+	Values [][]any `json:"values"`
+}
+
+func (c *Client) ListRows(
+	ctx context.Context,
+	connectionID, tableID string,
+) ([]sheet.Record, error) {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := c.tableLayout(ctx, conn, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if len(layout.schema.Columns) == 0 {
+		return nil, nil
+	}
+
+	request, err := c.request(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	a1Range := sheetRange(
+		layout.title,
+		fmt.Sprintf("A%d:%s", firstDataRow, columnLetter(len(layout.schema.Columns)-1)),
+	)
+	res, err := request.
+		SetQueryParams(map[string]string{
+			"majorDimension":       "ROWS",
+			"valueRenderOption":    "UNFORMATTED_VALUE",
+			"dateTimeRenderOption": "SERIAL_NUMBER",
+		}).
+		Get(valuesPath(conn.spreadsheetID, a1Range))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rows: %w", err)
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to list rows with response: %s", res.Body())
+	}
+
+	var resp listRowsResp
+	if err := json.Unmarshal(res.Body(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	c.forgetDeletedRows(conn, tableID)
+
+	return processRows(layout.schema, resp.Values), nil
+}
+
+// processRows maps the values read from the second row on. Each record is identified by its row
+// number, which stays valid until rows above it are added or sorted, or removed other than
+// through DeleteRow.
+func processRows(schema tableSchema, values [][]any) []sheet.Record {
+	records := make([]sheet.Record, 0, len(values))
+	for index, rowValues := range values {
+		if isEmptyRow(rowValues) {
+			continue
+		}
+
+		row, err := mapValuesToRow(schema, rowValues)
+		if err != nil {
+			continue
+		}
+		records = append(records, sheet.Record{ID: strconv.Itoa(index + firstDataRow), Row: row})
+	}
+
+	return records
+}
+
+func isEmptyRow(values []any) bool {
+	for _, value := range values {
+		if value != nil && value != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mapValuesToRow(schema tableSchema, values []any) (sheet.Row, error) {
+	row := make(sheet.Row, len(schema.Columns))
+	for index, column := range schema.Columns {
+		var value any
+		if index < len(values) {
+			value = values[index]
+		}
+
+		cell, ok, err := mapValueToCell(column.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", column.Name, err)
+		}
+		if ok {
+			row[column.Name] = cell
+		}
+	}
+
+	return row, nil
+}
+
+func mapValueToCell(columnType sheet.ColumnType, value any) (sheet.Cell, bool, error) {
+	switch columnType {
+	case sheet.ColumnTypeTitle:
+		return sheet.TitleCell(stringValue(value)), true, nil
+	case sheet.ColumnTypeText:
+		return sheet.TextCell(stringValue(value)), true, nil
+	case sheet.ColumnTypeSelect:
+		if stringValue(value) == "" {
+			return nil, false, nil
+		}
+
+		return sheet.SelectCell(stringValue(value)), true, nil
+	case sheet.ColumnTypeNumber:
+		number, ok, err := numberValue(value)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+
+		return sheet.NumberCell(number), true, nil
+	case sheet.ColumnTypeDate:
+		serial, ok, err := numberValue(value)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+
+		return sheet.DateCell(serialToTime(serial)), true, nil
+	case sheet.ColumnTypeCheckbox:
+		checked, ok, err := boolValue(value)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+
+		return sheet.CheckboxCell(checked), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// stringValue renders a value as text, since cells typed by hand may hold numbers or booleans.
+func stringValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// boolValue reads a checkbox, which users may also have typed as text.
+func boolValue(value any) (bool, bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return false, false, nil
+	case bool:
+		return value, true, nil
+	case string:
+		if value == "" {
+			return false, false, nil
+		}
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, false, fmt.Errorf("value %q is not a checkbox", value)
+		}
+
+		return checked, true, nil
+	default:
+		return false, false, fmt.Errorf("value %v is not a checkbox", value)
+	}
+}
+
+func numberValue(value any) (float64, bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return value, true, nil
+	case string:
+		if value == "" {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("value %q is not a number", value)
+	default:
+		return 0, false, fmt.Errorf("value %v is not a number", value)
+	}
+}
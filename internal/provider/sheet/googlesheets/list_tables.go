@@ -0,0 +1,40 @@
+package googlesheets
+
+import (
+	"context"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// ListTables returns the tabs created by this provider. Tabs without a column schema are left out
+// because their rows cannot be typed.
+func (c *Client) ListTables(
+	ctx context.Context,
+	connectionID string,
+) ([]sheet.Table, error) {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	spreadsheet, err := c.getSpreadsheet(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []sheet.Table
+	for _, spreadsheetSheet := range spreadsheet.Sheets {
+		layout, err := newTableLayout(spreadsheetSheet)
+		if err != nil {
+			continue
+		}
+		c.storeTableLayout(conn, layout)
+
+		tables = append(tables, sheet.Table{
+			ID:    formatSheetID(layout.sheetID),
+			Title: layout.title,
+		})
+	}
+
+	return tables, nil
+}
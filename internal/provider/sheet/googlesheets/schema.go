@@ -0,0 +1,189 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// schemaMetadataKey identifies the sheet-level developer metadata that stores the typed column
+// layout of a tab. Sheets only keeps cell values, so the schema is required to map rows back to
+// typed cells.
+const schemaMetadataKey = "openfinance_to_sheets.columns"
+
+const spreadsheetFields = "sheets(properties(sheetId,title,gridProperties(columnCount))," +
+	"developerMetadata(metadataId,metadataKey,metadataValue))"
+
+type getSpreadsheetResp struct {
+	Sheets []spreadsheetSheet `json:"sheets"`
+}
+
+type spreadsheetSheet struct {
+	Properties        spreadsheetSheetProperties `json:"properties"`
+	DeveloperMetadata []developerMetadata        `json:"developerMetadata"`
+}
+
+type spreadsheetSheetProperties struct {
+	SheetID        int64           `json:"sheetId"`
+	Title          string          `json:"title"`
+	GridProperties *gridProperties `json:"gridProperties,omitempty"`
+}
+
+type gridProperties struct {
+	ColumnCount    int `json:"columnCount,omitempty"`
+	FrozenRowCount int `json:"frozenRowCount,omitempty"`
+}
+
+type developerMetadata struct {
+	MetadataID    int64                      `json:"metadataId,omitempty"`
+	MetadataKey   string                     `json:"metadataKey,omitempty"`
+	MetadataValue string                     `json:"metadataValue"`
+	Location      *developerMetadataLocation `json:"location,omitempty"`
+	Visibility    string                     `json:"visibility,omitempty"`
+}
+
+type developerMetadataLocation struct {
+	SheetID int64 `json:"sheetId"`
+}
+
+type tableSchema struct {
+	Columns []schemaColumn `json:"columns"`
+}
+
+type schemaColumn struct {
+	Name     string           `json:"name"`
+	Type     sheet.ColumnType `json:"type"`
+	Currency sheet.Currency   `json:"currency,omitempty"`
+	Options  []string         `json:"options,omitempty"`
+	Hidden   bool             `json:"hidden,omitempty"`
+}
+
+type tableLayout struct {
+	sheetID     int64
+	title       string
+	columnCount int
+	metadataID  int64
+	schema      tableSchema
+}
+
+func (s tableSchema) columnIndex(name string) (int, bool) {
+	for index, column := range s.Columns {
+		if column.Name == name {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
+func (c *Client) getSpreadsheet(ctx context.Context, conn conn) (getSpreadsheetResp, error) {
+	request, err := c.request(ctx, conn)
+	if err != nil {
+		return getSpreadsheetResp{}, err
+	}
+
+	res, err := request.
+		SetQueryParam("fields", spreadsheetFields).
+		Get(spreadsheetPath(conn.spreadsheetID))
+	if err != nil {
+		return getSpreadsheetResp{}, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+	if res.IsError() {
+		return getSpreadsheetResp{}, fmt.Errorf("failed to get spreadsheet with response: %s", res.Body())
+	}
+
+	data := getSpreadsheetResp{}
+	if err := json.Unmarshal(res.Body(), &data); err != nil {
+		return getSpreadsheetResp{}, fmt.Errorf("failed to unmarshal spreadsheet: %w", err)
+	}
+
+	return data, nil
+}
+
+// cachedTableLayout avoids reading the spreadsheet before every row insert. The cache is refreshed
+// whenever this client changes the layout of a table.
+func (c *Client) cachedTableLayout(ctx context.Context, conn conn, tableID string) (tableLayout, error) {
+	c.mutex.Lock()
+	layout, ok := c.layouts[layoutKey(conn, tableID)]
+	c.mutex.Unlock()
+	if ok {
+		return layout, nil
+	}
+
+	return c.tableLayout(ctx, conn, tableID)
+}
+
+func (c *Client) tableLayout(ctx context.Context, conn conn, tableID string) (tableLayout, error) {
+	sheetID, err := parseSheetID(tableID)
+	if err != nil {
+		return tableLayout{}, err
+	}
+
+	spreadsheet, err := c.getSpreadsheet(ctx, conn)
+	if err != nil {
+		return tableLayout{}, err
+	}
+
+	for _, spreadsheetSheet := range spreadsheet.Sheets {
+		if spreadsheetSheet.Properties.SheetID != sheetID {
+			continue
+		}
+
+		layout, err := newTableLayout(spreadsheetSheet)
+		if err != nil {
+			return tableLayout{}, err
+		}
+		c.storeTableLayout(conn, layout)
+
+		return layout, nil
+	}
+
+	return tableLayout{}, fmt.Errorf("table %q not found", tableID)
+}
+
+func (c *Client) storeTableLayout(conn conn, layout tableLayout) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.layouts[layoutKey(conn, formatSheetID(layout.sheetID))] = layout
+}
+
+func layoutKey(conn conn, tableID string) string {
+	return conn.spreadsheetID + "/" + tableID
+}
+
+func newTableLayout(spreadsheetSheet spreadsheetSheet) (tableLayout, error) {
+	layout := tableLayout{
+		sheetID: spreadsheetSheet.Properties.SheetID,
+		title:   spreadsheetSheet.Properties.Title,
+	}
+	if spreadsheetSheet.Properties.GridProperties != nil {
+		layout.columnCount = spreadsheetSheet.Properties.GridProperties.ColumnCount
+	}
+
+	for _, metadata := range spreadsheetSheet.DeveloperMetadata {
+		if metadata.MetadataKey != schemaMetadataKey {
+			continue
+		}
+
+		if err := json.Unmarshal([]byte(metadata.MetadataValue), &layout.schema); err != nil {
+			return tableLayout{}, fmt.Errorf("failed to unmarshal schema of table %q: %w", layout.title, err)
+		}
+		layout.metadataID = metadata.MetadataID
+
+		return layout, nil
+	}
+
+	return tableLayout{}, fmt.Errorf("table %q has no column schema", layout.title)
+}
+
+func encodeSchema(schema tableSchema) (string, error) {
+	value, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal column schema: %w", err)
+	}
+
+	return string(value), nil
+}
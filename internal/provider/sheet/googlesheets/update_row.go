@@ -0,0 +1,69 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+type updateValuesReq struct {
+	Values [][]any `json:"values"`
+}
+
+// UpdateRow writes the row cells over the sheet row with the given number, taking into account
+// the rows deleted since the table was listed. Cells the row does not set are sent as null, which
+// Sheets leaves unchanged.
+func (c *Client) UpdateRow(
+	ctx context.Context,
+	connectionID, tableID, rowID string,
+	row sheet.Row,
+) error {
+	conn, err := c.connection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	rowNumber, err := c.currentRowNumber(conn, tableID, rowID)
+	if err != nil {
+		return err
+	}
+
+	layout, err := c.cachedTableLayout(ctx, conn, tableID)
+	if err != nil {
+		return err
+	}
+
+	values, err := rowValues(layout.schema, row)
+	if err != nil {
+		return fmt.Errorf("invalid row: %w", err)
+	}
+
+	request, err := c.request(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	a1Range := sheetRange(
+		layout.title,
+		fmt.Sprintf("A%d:%s%d", rowNumber, columnLetter(len(layout.schema.Columns)-1), rowNumber),
+	)
+	requestData := updateValuesReq{Values: [][]any{values}}
+	res, err := request.
+		SetQueryParam("valueInputOption", "RAW").
+		SetBody(requestData).
+		Put(valuesPath(conn.spreadsheetID, a1Range))
+	if err != nil {
+		return fmt.Errorf("failed to update row %q with request data %+v: %w", rowID, requestData, err)
+	}
+	if res.IsError() {
+		return fmt.Errorf(
+			"failed to update row %q with request data %+v and response %s",
+			rowID,
+			requestData,
+			res.Body(),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package sheet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RowError is the failure of one row of an InsertRows call. Index points into the rows passed
+// to InsertRows.
+type RowError struct {
+	Index int
+	Err   error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Index, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// InsertRowsError reports a partial InsertRows failure: every row without a RowError was
+// inserted.
+type InsertRowsError struct {
+	Rows   int
+	Errors []RowError
+}
+
+func (e *InsertRowsError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("failed to insert 0 of %d rows", e.Rows)
+	}
+
+	return fmt.Sprintf("failed to insert %d of %d rows: %v", len(e.Errors), e.Rows, e.Errors[0])
+}
+
+func (e *InsertRowsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, rowErr := range e.Errors {
+		errs = append(errs, rowErr)
+	}
+
+	return errs
+}
+
+// NewInsertRowsError returns nil when no row failed, so adapters can return it directly. The row
+// errors are sorted by index.
+func NewInsertRowsError(rows int, rowErrors []RowError) error {
+	if len(rowErrors) == 0 {
+		return nil
+	}
+
+	sort.Slice(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Index < rowErrors[j].Index
+	})
+
+	return &InsertRowsError{Rows: rows, Errors: rowErrors}
+}
+
+// FailedRows returns the row errors of an InsertRows error. An error that is not an
+// InsertRowsError fails every row.
+func FailedRows(err error, rows int) []RowError {
+	if err == nil {
+		return nil
+	}
+
+	var insertRowsErr *InsertRowsError
+	if errors.As(err, &insertRowsErr) {
+		return insertRowsErr.Errors
+	}
+
+	rowErrors := make([]RowError, rows)
+	for index := range rowErrors {
+		rowErrors[index] = RowError{Index: index, Err: err}
+	}
+
+	return rowErrors
+}
+
+// RowInserter inserts a single row; every Provider is one.
+type RowInserter interface {
+	InsertRow(ctx context.Context, connectionID, tableID string, row Row) error
+}
+
+// InsertRowsIndividually is the InsertRows fallback for backends without a batch API. It inserts
+// the rows with at most limit concurrent InsertRow calls and keeps going after a row fails, so
+// the caller learns exactly which rows are missing.
+func InsertRowsIndividually(
+	ctx context.Context,
+	inserter RowInserter,
+	connectionID, tableID string,
+	rows []Row,
+	limit int,
+) error {
+	var group errgroup.Group
+	group.SetLimit(max(limit, 1))
+
+	var mutex sync.Mutex
+	var rowErrors []RowError
+	for index, row := range rows {
+		group.Go(func() error {
+			err := ctx.Err()
+			if err == nil {
+				err = inserter.InsertRow(ctx, connectionID, tableID, row)
+			}
+			if err != nil {
+				mutex.Lock()
+				rowErrors = append(rowErrors, RowError{Index: index, Err: err})
+				mutex.Unlock()
+			}
+
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	return NewInsertRowsError(len(rows), rowErrors)
+}
@@ -0,0 +1,121 @@
+package sheet_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+type rowInserterFunc func(ctx context.Context, connectionID, tableID string, row sheet.Row) error
+
+func (f rowInserterFunc) InsertRow(ctx context.Context, connectionID, tableID string, row sheet.Row) error {
+	return f(ctx, connectionID, tableID, row)
+}
+
+func TestInsertRowsIndividuallyBoundsConcurrencyAndReportsFailedRows(t *testing.T) {
+	rowErr := errors.New("row rejected")
+	rows := make([]sheet.Row, 12)
+	for index := range rows {
+		rows[index] = sheet.Row{"Amount": sheet.NumberCell(index)}
+	}
+
+	var active, maximum atomic.Int32
+	err := sheet.InsertRowsIndividually(
+		t.Context(),
+		rowInserterFunc(func(_ context.Context, connectionID, tableID string, row sheet.Row) error {
+			if connectionID != "connection" || tableID != "table" {
+				t.Errorf("InsertRow(%q, %q)", connectionID, tableID)
+			}
+
+			current := active.Add(1)
+			defer active.Add(-1)
+			for {
+				previous := maximum.Load()
+				if current <= previous || maximum.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			if amount := row["Amount"]; amount == sheet.NumberCell(3) || amount == sheet.NumberCell(7) {
+				return rowErr
+			}
+
+			return nil
+		}),
+		"connection",
+		"table",
+		rows,
+		3,
+	)
+
+	var insertRowsErr *sheet.InsertRowsError
+	if !errors.As(err, &insertRowsErr) || !errors.Is(err, rowErr) {
+		t.Fatalf("InsertRowsIndividually() error = %v, want InsertRowsError", err)
+	}
+	want := []sheet.RowError{{Index: 3, Err: rowErr}, {Index: 7, Err: rowErr}}
+	if insertRowsErr.Rows != len(rows) || !reflect.DeepEqual(insertRowsErr.Errors, want) {
+		t.Fatalf("InsertRowsError = %#v, want errors %#v", insertRowsErr, want)
+	}
+	if maximum.Load() > 3 || maximum.Load() < 2 {
+		t.Fatalf("insert concurrency = %d, want between 2 and 3", maximum.Load())
+	}
+}
+
+func TestInsertRowsIndividuallyFailsRemainingRowsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := sheet.InsertRowsIndividually(
+		ctx,
+		rowInserterFunc(func(context.Context, string, string, sheet.Row) error {
+			t.Error("InsertRow() called after cancellation")
+
+			return nil
+		}),
+		"connection",
+		"table",
+		[]sheet.Row{{}, {}},
+		1,
+	)
+	if failed := sheet.FailedRows(err, 2); len(failed) != 2 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("InsertRowsIndividually() error = %v, want both rows canceled", err)
+	}
+}
+
+func TestFailedRows(t *testing.T) {
+	backendErr := errors.New("backend unavailable")
+	partial := sheet.NewInsertRowsError(3, []sheet.RowError{{Index: 2, Err: backendErr}, {Index: 0, Err: backendErr}})
+
+	tests := map[string]struct {
+		err  error
+		want []sheet.RowError
+	}{
+		"success": {},
+		"partial": {
+			err:  partial,
+			want: []sheet.RowError{{Index: 0, Err: backendErr}, {Index: 2, Err: backendErr}},
+		},
+		"whole batch": {
+			err: backendErr,
+			want: []sheet.RowError{
+				{Index: 0, Err: backendErr},
+				{Index: 1, Err: backendErr},
+				{Index: 2, Err: backendErr},
+			},
+		},
+		"no row error": {err: sheet.NewInsertRowsError(3, nil)},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := sheet.FailedRows(test.err, 3); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("FailedRows() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
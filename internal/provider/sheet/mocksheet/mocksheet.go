@@ -110,6 +110,75 @@ func (_c *MockSheet_CreateTable_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// DeleteRow provides a mock function for the type MockSheet
+func (_mock *MockSheet) DeleteRow(ctx context.Context, connectionID string, tableID string, rowID string) error {
+	ret := _mock.Called(ctx, connectionID, tableID, rowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRow")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, connectionID, tableID, rowID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSheet_DeleteRow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRow'
+type MockSheet_DeleteRow_Call struct {
+	*mock.Call
+}
+
+// DeleteRow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - connectionID string
+//   - tableID string
+//   - rowID string
+func (_e *MockSheet_Expecter) DeleteRow(ctx interface{}, connectionID interface{}, tableID interface{}, rowID interface{}) *MockSheet_DeleteRow_Call {
+	return &MockSheet_DeleteRow_Call{Call: _e.mock.On("DeleteRow", ctx, connectionID, tableID, rowID)}
+}
+
+func (_c *MockSheet_DeleteRow_Call) Run(run func(ctx context.Context, connectionID string, tableID string, rowID string)) *MockSheet_DeleteRow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSheet_DeleteRow_Call) Return(err error) *MockSheet_DeleteRow_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSheet_DeleteRow_Call) RunAndReturn(run func(ctx context.Context, connectionID string, tableID string, rowID string) error) *MockSheet_DeleteRow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // EnsureTableColumns provides a mock function for the type MockSheet
 func (_mock *MockSheet) EnsureTableColumns(ctx context.Context, connectionID string, tableID string, columns ...sheet.Column) error {
 	var tmpRet mock.Arguments
@@ -257,24 +326,93 @@ func (_c *MockSheet_InsertRow_Call) RunAndReturn(run func(ctx context.Context, c
 	return _c
 }
 
+// InsertRows provides a mock function for the type MockSheet
+func (_mock *MockSheet) InsertRows(ctx context.Context, connectionID string, tableID string, rows []sheet.Row) error {
+	ret := _mock.Called(ctx, connectionID, tableID, rows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRows")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, []sheet.Row) error); ok {
+		r0 = returnFunc(ctx, connectionID, tableID, rows)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSheet_InsertRows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertRows'
+type MockSheet_InsertRows_Call struct {
+	*mock.Call
+}
+
+// InsertRows is a helper method to define mock.On call
+//   - ctx context.Context
+//   - connectionID string
+//   - tableID string
+//   - rows []sheet.Row
+func (_e *MockSheet_Expecter) InsertRows(ctx interface{}, connectionID interface{}, tableID interface{}, rows interface{}) *MockSheet_InsertRows_Call {
+	return &MockSheet_InsertRows_Call{Call: _e.mock.On("InsertRows", ctx, connectionID, tableID, rows)}
+}
+
+func (_c *MockSheet_InsertRows_Call) Run(run func(ctx context.Context, connectionID string, tableID string, rows []sheet.Row)) *MockSheet_InsertRows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 []sheet.Row
+		if args[3] != nil {
+			arg3 = args[3].([]sheet.Row)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSheet_InsertRows_Call) Return(err error) *MockSheet_InsertRows_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSheet_InsertRows_Call) RunAndReturn(run func(ctx context.Context, connectionID string, tableID string, rows []sheet.Row) error) *MockSheet_InsertRows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListRows provides a mock function for the type MockSheet
-func (_mock *MockSheet) ListRows(ctx context.Context, connectionID string, tableID string) ([]sheet.Row, error) {
+func (_mock *MockSheet) ListRows(ctx context.Context, connectionID string, tableID string) ([]sheet.Record, error) {
 	ret := _mock.Called(ctx, connectionID, tableID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListRows")
 	}
 
-	var r0 []sheet.Row
+	var r0 []sheet.Record
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]sheet.Row, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) ([]sheet.Record, error)); ok {
 		return returnFunc(ctx, connectionID, tableID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []sheet.Row); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) []sheet.Record); ok {
 		r0 = returnFunc(ctx, connectionID, tableID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]sheet.Row)
+			r0 = ret.Get(0).([]sheet.Record)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
@@ -321,12 +459,12 @@ func (_c *MockSheet_ListRows_Call) Run(run func(ctx context.Context, connectionI
 	return _c
 }
 
-func (_c *MockSheet_ListRows_Call) Return(rows []sheet.Row, err error) *MockSheet_ListRows_Call {
-	_c.Call.Return(rows, err)
+func (_c *MockSheet_ListRows_Call) Return(records []sheet.Record, err error) *MockSheet_ListRows_Call {
+	_c.Call.Return(records, err)
 	return _c
 }
 
-func (_c *MockSheet_ListRows_Call) RunAndReturn(run func(ctx context.Context, connectionID string, tableID string) ([]sheet.Row, error)) *MockSheet_ListRows_Call {
+func (_c *MockSheet_ListRows_Call) RunAndReturn(run func(ctx context.Context, connectionID string, tableID string) ([]sheet.Record, error)) *MockSheet_ListRows_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -398,3 +536,78 @@ func (_c *MockSheet_ListTables_Call) RunAndReturn(run func(ctx context.Context,
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateRow provides a mock function for the type MockSheet
+func (_mock *MockSheet) UpdateRow(ctx context.Context, connectionID string, tableID string, rowID string, row sheet.Row) error {
+	ret := _mock.Called(ctx, connectionID, tableID, rowID, row)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRow")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, sheet.Row) error); ok {
+		r0 = returnFunc(ctx, connectionID, tableID, rowID, row)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSheet_UpdateRow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRow'
+type MockSheet_UpdateRow_Call struct {
+	*mock.Call
+}
+
+// UpdateRow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - connectionID string
+//   - tableID string
+//   - rowID string
+//   - row sheet.Row
+func (_e *MockSheet_Expecter) UpdateRow(ctx interface{}, connectionID interface{}, tableID interface{}, rowID interface{}, row interface{}) *MockSheet_UpdateRow_Call {
+	return &MockSheet_UpdateRow_Call{Call: _e.mock.On("UpdateRow", ctx, connectionID, tableID, rowID, row)}
+}
+
+func (_c *MockSheet_UpdateRow_Call) Run(run func(ctx context.Context, connectionID string, tableID string, rowID string, row sheet.Row)) *MockSheet_UpdateRow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 sheet.Row
+		if args[4] != nil {
+			arg4 = args[4].(sheet.Row)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSheet_UpdateRow_Call) Return(err error) *MockSheet_UpdateRow_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSheet_UpdateRow_Call) RunAndReturn(run func(ctx context.Context, connectionID string, tableID string, rowID string, row sheet.Row) error) *MockSheet_UpdateRow_Call {
+	_c.Call.Return(run)
+	return _c
+}
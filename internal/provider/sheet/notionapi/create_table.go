@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/go-resty/resty/v2"
+
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 )
 
@@ -32,6 +34,7 @@ type createTableReqProperty struct {
 	Number   *createTableReqNumber `json:"number,omitempty"`
 	Select   *createTableReqSelect `json:"select,omitempty"`
 	Date     *struct{}             `json:"date,omitempty"`
+	Checkbox *struct{}             `json:"checkbox,omitempty"`
 }
 
 type createTableReqNumber struct {
@@ -84,11 +87,9 @@ func (c *Client) CreateTable(
 		return sheet.Table{}, fmt.Errorf("invalid table definition: %w", err)
 	}
 
-	res, err := c.client.R().
-		SetContext(ctx).
-		SetHeader("Authorization", "Bearer "+conn.accessToken).
-		SetBody(requestData).
-		Post("/v1/databases")
+	res, err := c.send(ctx, conn, false, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetBody(requestData).Post("/v1/databases")
+	})
 	if err != nil {
 		return sheet.Table{}, fmt.Errorf(
 			"failed to create table with request data %+v: %w",
@@ -145,6 +146,19 @@ func createTableRequest(
 	return requestData, nil
 }
 
+// numberFormat is the Notion number format showing amounts in the currency, or the plain number
+// format without one.
+func numberFormat(currency sheet.Currency) (string, error) {
+	switch currency {
+	case "":
+		return "", nil
+	case sheet.Currency("BRL"):
+		return "real", nil
+	default:
+		return "", fmt.Errorf("unsupported currency %q", currency)
+	}
+}
+
 func createTableProperty(column sheet.ColumnDefinition) (createTableReqProperty, error) {
 	empty := &struct{}{}
 	switch column.Type() {
@@ -153,16 +167,12 @@ func createTableProperty(column sheet.ColumnDefinition) (createTableReqProperty,
 	case sheet.ColumnTypeText:
 		return createTableReqProperty{RichText: empty}, nil
 	case sheet.ColumnTypeNumber:
-		number := &createTableReqNumber{}
-		switch column.Currency() {
-		case "":
-		case sheet.Currency("BRL"):
-			number.Format = "real"
-		default:
-			return createTableReqProperty{}, fmt.Errorf("unsupported currency %q", column.Currency())
+		format, err := numberFormat(column.Currency())
+		if err != nil {
+			return createTableReqProperty{}, err
 		}
 
-		return createTableReqProperty{Number: number}, nil
+		return createTableReqProperty{Number: &createTableReqNumber{Format: format}}, nil
 	case sheet.ColumnTypeSelect:
 		selectOptions := column.SelectOptions()
 		options := make([]createTableReqSelectOption, 0, len(selectOptions))
@@ -176,6 +186,8 @@ func createTableProperty(column sheet.ColumnDefinition) (createTableReqProperty,
 		return createTableReqProperty{Select: &createTableReqSelect{Options: options}}, nil
 	case sheet.ColumnTypeDate:
 		return createTableReqProperty{Date: empty}, nil
+	case sheet.ColumnTypeCheckbox:
+		return createTableReqProperty{Checkbox: empty}, nil
 	default:
 		return createTableReqProperty{}, fmt.Errorf("unsupported column type %q", column.Type())
 	}
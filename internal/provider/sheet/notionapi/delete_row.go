@@ -0,0 +1,42 @@
+package notionapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type deleteRowReq struct {
+	Archived bool `json:"archived"`
+}
+
+// DeleteRow archives the page with the given ID, which moves it to the Notion trash where it can
+// still be restored. Archiving an archived page changes nothing, so the call is retried like a
+// read.
+func (c *Client) DeleteRow(
+	ctx context.Context,
+	connectionID, _, rowID string,
+) error {
+	conn, ok := c.conns[connectionID]
+	if !ok {
+		return errors.New("connection not found for ingest profile " + connectionID)
+	}
+	if rowID == "" {
+		return errors.New("row ID is required")
+	}
+
+	requestData := deleteRowReq{Archived: true}
+	res, err := c.send(ctx, conn, true, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetBody(requestData).Patch(fmt.Sprintf("/v1/pages/%s", rowID))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive row %q: %w", rowID, err)
+	}
+	if res.IsError() {
+		return fmt.Errorf("failed to archive row %q with response %s", rowID, res.Body())
+	}
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/go-resty/resty/v2"
+
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 )
@@ -34,7 +36,10 @@ type updateTableReq struct {
 }
 
 type updateTableReqProperty struct {
-	Select *updateTableReqSelect `json:"select,omitempty"`
+	RichText *struct{}             `json:"rich_text,omitempty"`
+	Select   *updateTableReqSelect `json:"select,omitempty"`
+	Checkbox *struct{}             `json:"checkbox,omitempty"`
+	Number   *createTableReqNumber `json:"number,omitempty"`
 }
 
 type updateTableReqSelect struct {
@@ -69,7 +74,7 @@ func (c *Client) EnsureTableColumns(
 
 	updates := make(map[string]updateTableReqProperty)
 	for _, column := range definitions {
-		property, changed, err := ensureSelectProperty(table.Properties, column)
+		property, changed, err := ensureProperty(table.Properties, column)
 		if err != nil {
 			return err
 		}
@@ -82,11 +87,9 @@ func (c *Client) EnsureTableColumns(
 	}
 
 	requestData := updateTableReq{Properties: updates}
-	res, err := c.client.R().
-		SetContext(ctx).
-		SetHeader("Authorization", "Bearer "+conn.accessToken).
-		SetBody(requestData).
-		Patch(fmt.Sprintf("/v1/databases/%s", tableID))
+	res, err := c.send(ctx, conn, true, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetBody(requestData).Patch(fmt.Sprintf("/v1/databases/%s", tableID))
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update database schema: %w", err)
 	}
@@ -108,7 +111,9 @@ func ensureColumnDefinitions(columns []sheet.Column) ([]sheet.ColumnDefinition,
 		if err := definition.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid columns: column %d: %w", columnIndex, err)
 		}
-		if definition.Type() != sheet.ColumnTypeSelect {
+		switch definition.Type() {
+		case sheet.ColumnTypeSelect, sheet.ColumnTypeText, sheet.ColumnTypeCheckbox, sheet.ColumnTypeNumber:
+		default:
 			return nil, fmt.Errorf(
 				"column %q has unsupported ensure type %q",
 				definition.Name(),
@@ -127,10 +132,9 @@ func (c *Client) retrieveTable(
 	conn conn,
 	tableID string,
 ) (retrieveTableResp, error) {
-	res, err := c.client.R().
-		SetContext(ctx).
-		SetHeader("Authorization", "Bearer "+conn.accessToken).
-		Get(fmt.Sprintf("/v1/databases/%s", tableID))
+	res, err := c.send(ctx, conn, true, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.Get(fmt.Sprintf("/v1/databases/%s", tableID))
+	})
 	if err != nil {
 		return retrieveTableResp{}, fmt.Errorf("failed to retrieve database schema: %w", err)
 	}
@@ -146,6 +150,45 @@ func (c *Client) retrieveTable(
 	return table, nil
 }
 
+// ensureProperty adds a missing text, number, select or checkbox property, or merges new options
+// into an existing select property. Notion cannot hide properties through the API, so hidden
+// columns stay visible, and the format of existing number properties is kept.
+func ensureProperty(
+	properties map[string]retrieveTableRespProperty,
+	column sheet.ColumnDefinition,
+) (updateTableReqProperty, bool, error) {
+	if column.Type() == sheet.ColumnTypeSelect {
+		return ensureSelectProperty(properties, column)
+	}
+
+	propertyType, property := "rich_text", updateTableReqProperty{RichText: &struct{}{}}
+	switch column.Type() {
+	case sheet.ColumnTypeCheckbox:
+		propertyType, property = "checkbox", updateTableReqProperty{Checkbox: &struct{}{}}
+	case sheet.ColumnTypeNumber:
+		format, err := numberFormat(column.Currency())
+		if err != nil {
+			return updateTableReqProperty{}, false, fmt.Errorf("column %q: %w", column.Name(), err)
+		}
+		propertyType, property = "number", updateTableReqProperty{Number: &createTableReqNumber{Format: format}}
+	}
+
+	existing, exists := properties[column.Name()]
+	if !exists {
+		return property, true, nil
+	}
+	if existing.Type != propertyType {
+		return updateTableReqProperty{}, false, fmt.Errorf(
+			"column %q has type %q, want %q",
+			column.Name(),
+			existing.Type,
+			propertyType,
+		)
+	}
+
+	return updateTableReqProperty{}, false, nil
+}
+
 func ensureSelectProperty(
 	properties map[string]retrieveTableRespProperty,
 	column sheet.ColumnDefinition,
@@ -2,10 +2,13 @@ package notionapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/go-resty/resty/v2"
+
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 )
 
@@ -24,6 +27,7 @@ type insertRowReqProperty struct {
 	Number   *float64               `json:"number,omitempty"`
 	Select   *insertRowReqSelect    `json:"select,omitempty"`
 	Date     *insertRowReqDate      `json:"date,omitempty"`
+	Checkbox *bool                  `json:"checkbox,omitempty"`
 }
 
 type insertRowReqSelect struct {
@@ -57,11 +61,15 @@ func (c *Client) InsertRow(
 		return fmt.Errorf("invalid row: %w", err)
 	}
 
-	res, err := c.client.R().
-		SetContext(ctx).
-		SetHeader("Authorization", "Bearer "+conn.accessToken).
-		SetBody(requestData).
-		Post("/v1/pages")
+	verify := func(ctx context.Context) (bool, error) {
+		return c.rowExists(ctx, conn, tableID, row)
+	}
+	res, err := c.send(ctx, conn, false, verify, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetBody(requestData).Post("/v1/pages")
+	})
+	if errors.Is(err, errAlreadyApplied) {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf(
 			"failed to insert row with request data %+v: %w",
@@ -125,7 +133,72 @@ func insertRowProperty(cell sheet.Cell) (insertRowReqProperty, error) {
 		return insertRowReqProperty{
 			Date: &insertRowReqDate{Start: time.Time(value).Format(time.RFC3339)},
 		}, nil
+	case sheet.CheckboxCell:
+		return insertRowReqProperty{Checkbox: new(bool(value))}, nil
 	default:
 		return insertRowReqProperty{}, fmt.Errorf("unsupported cell type %T", cell)
 	}
 }
+
+// rowExists looks for a page matching every non-empty cell of the row. InsertRow uses it before
+// retrying an insert whose outcome is unknown: ingest never inserts two identical rows, so a
+// match means the first attempt created the page.
+func (c *Client) rowExists(ctx context.Context, conn conn, tableID string, row sheet.Row) (bool, error) {
+	filter, err := rowFilter(row)
+	if err != nil {
+		return false, err
+	}
+
+	requestData := listRowsReq{PageSize: 1, Filter: filter}
+	res, err := c.send(ctx, conn, true, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetBody(requestData).Post(fmt.Sprintf("/v1/databases/%s/query", tableID))
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to query database: %w", err)
+	}
+	if res.IsError() {
+		return false, fmt.Errorf("failed to query database with response: %s", res.Body())
+	}
+
+	var resp listRowsResp
+	if err := json.Unmarshal(res.Body(), &resp); err != nil {
+		return false, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return len(resp.Results) > 0, nil
+}
+
+func rowFilter(row sheet.Row) (*listRowsReqFilter, error) {
+	filter := &listRowsReqFilter{}
+	for column, cell := range row {
+		condition := listRowsReqCondition{Property: column}
+		switch value := cell.(type) {
+		case sheet.TitleCell:
+			if value == "" {
+				continue
+			}
+			condition.Title = &listRowsReqEquals[string]{Equals: string(value)}
+		case sheet.TextCell:
+			if value == "" {
+				continue
+			}
+			condition.RichText = &listRowsReqEquals[string]{Equals: string(value)}
+		case sheet.NumberCell:
+			condition.Number = &listRowsReqEquals[float64]{Equals: float64(value)}
+		case sheet.SelectCell:
+			condition.Select = &listRowsReqEquals[string]{Equals: formatSelectOption(string(value))}
+		case sheet.DateCell:
+			condition.Date = &listRowsReqEquals[string]{Equals: time.Time(value).Format(time.RFC3339)}
+		case sheet.CheckboxCell:
+			condition.Checkbox = &listRowsReqEquals[bool]{Equals: bool(value)}
+		default:
+			continue
+		}
+		filter.And = append(filter.And, condition)
+	}
+	if len(filter.And) == 0 {
+		return nil, errors.New("row has no cells to match")
+	}
+
+	return filter, nil
+}
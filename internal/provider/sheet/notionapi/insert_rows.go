@@ -0,0 +1,25 @@
+package notionapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// insertRowsConcurrency keeps page creation close to Notion's average limit of three requests
+// per second.
+const insertRowsConcurrency = 3
+
+// InsertRows creates one page per row, since Notion has no batch endpoint.
+func (c *Client) InsertRows(
+	ctx context.Context,
+	connectionID, tableID string,
+	rows []sheet.Row,
+) error {
+	if _, ok := c.conns[connectionID]; !ok {
+		return errors.New("connection not found for ingest profile " + connectionID)
+	}
+
+	return sheet.InsertRowsIndividually(ctx, c, connectionID, tableID, rows, insertRowsConcurrency)
+}
@@ -7,15 +7,36 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/go-resty/resty/v2"
+
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 )
 
 const maxPageSize = 100
 
 type listRowsReq struct {
-	StartCursor string            `json:"start_cursor,omitempty"`
-	PageSize    int               `json:"page_size,omitempty"`
-	Sorts       []listRowsReqSort `json:"sorts,omitempty"`
+	StartCursor string             `json:"start_cursor,omitempty"`
+	PageSize    int                `json:"page_size,omitempty"`
+	Filter      *listRowsReqFilter `json:"filter,omitempty"`
+	Sorts       []listRowsReqSort  `json:"sorts,omitempty"`
+}
+
+type listRowsReqFilter struct {
+	And []listRowsReqCondition `json:"and"`
+}
+
+type listRowsReqCondition struct {
+	Property string                      `json:"property"`
+	Title    *listRowsReqEquals[string]  `json:"title,omitempty"`
+	RichText *listRowsReqEquals[string]  `json:"rich_text,omitempty"`
+	Number   *listRowsReqEquals[float64] `json:"number,omitempty"`
+	Select   *listRowsReqEquals[string]  `json:"select,omitempty"`
+	Date     *listRowsReqEquals[string]  `json:"date,omitempty"`
+	Checkbox *listRowsReqEquals[bool]    `json:"checkbox,omitempty"`
+}
+
+type listRowsReqEquals[T any] struct {
+	Equals T `json:"equals"`
 }
 
 type listRowsReqSort struct {
@@ -31,6 +52,7 @@ type listRowsResp struct {
 }
 
 type listRowsRespPage struct {
+	ID         string                          `json:"id"`
 	Properties map[string]listRowsRespProperty `json:"properties"`
 }
 
@@ -41,6 +63,7 @@ type listRowsRespProperty struct {
 	Number   *float64               `json:"number"`
 	Select   *listRowsRespSelect    `json:"select"`
 	Date     *listRowsRespDate      `json:"date"`
+	Checkbox bool                   `json:"checkbox"`
 }
 
 type listRowsRespSelect struct {
@@ -58,13 +81,13 @@ type listRowsRespDate struct {
 func (c *Client) ListRows(
 	ctx context.Context,
 	connectionID, tableID string,
-) ([]sheet.Row, error) {
+) ([]sheet.Record, error) {
 	conn, ok := c.conns[connectionID]
 	if !ok {
 		return nil, errors.New("connection not found for ingest profile " + connectionID)
 	}
 
-	var rows []sheet.Row
+	var records []sheet.Record
 	var cursor string
 	hasMore := true
 
@@ -74,7 +97,7 @@ func (c *Client) ListRows(
 			return nil, err
 		}
 
-		rows = append(rows, processRows(resp.Results)...)
+		records = append(records, processRows(resp.Results)...)
 
 		hasMore = resp.HasMore
 		if resp.NextCursor != nil {
@@ -82,7 +105,7 @@ func (c *Client) ListRows(
 		}
 	}
 
-	return rows, nil
+	return records, nil
 }
 
 func (c *Client) queryRowsPage(
@@ -101,11 +124,9 @@ func (c *Client) queryRowsPage(
 		requestData.StartCursor = cursor
 	}
 
-	res, err := c.client.R().
-		SetContext(ctx).
-		SetHeader("Authorization", "Bearer "+conn.accessToken).
-		SetBody(requestData).
-		Post(fmt.Sprintf("/v1/databases/%s/query", tableID))
+	res, err := c.send(ctx, conn, true, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetBody(requestData).Post(fmt.Sprintf("/v1/databases/%s/query", tableID))
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query database: %w", err)
 	}
@@ -121,17 +142,17 @@ func (c *Client) queryRowsPage(
 	return &resp, nil
 }
 
-func processRows(pages []listRowsRespPage) []sheet.Row {
-	rows := make([]sheet.Row, 0, len(pages))
+func processRows(pages []listRowsRespPage) []sheet.Record {
+	records := make([]sheet.Record, 0, len(pages))
 	for _, page := range pages {
 		row, err := mapPageToRow(page)
 		if err != nil {
 			continue
 		}
-		rows = append(rows, row)
+		records = append(records, sheet.Record{ID: page.ID, Row: row})
 	}
 
-	return rows
+	return records
 }
 
 func mapPageToRow(page listRowsRespPage) (sheet.Row, error) {
@@ -177,6 +198,8 @@ func mapPropertyToCell(property listRowsRespProperty) (sheet.Cell, bool, error)
 		}
 
 		return sheet.DateCell(value), true, nil
+	case "checkbox":
+		return sheet.CheckboxCell(property.Checkbox), true, nil
 	default:
 		return nil, false, nil
 	}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/go-resty/resty/v2"
+
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 )
 
@@ -70,11 +72,9 @@ func (c *Client) fetchTablesPage(
 		queryParams["start_cursor"] = cursor
 	}
 
-	res, err := c.client.R().
-		SetContext(ctx).
-		SetHeader("Authorization", "Bearer "+conn.accessToken).
-		SetQueryParams(queryParams).
-		Get(fmt.Sprintf("/v1/blocks/%s/children", conn.pageID))
+	res, err := c.send(ctx, conn, true, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetQueryParams(queryParams).Get(fmt.Sprintf("/v1/blocks/%s/children", conn.pageID))
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tables: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 	"github.com/go-resty/resty/v2"
 )
@@ -11,11 +12,13 @@ import (
 type conn struct {
 	accessToken string
 	pageID      string
+	limiter     *tokenLimiter
 }
 
 type Client struct {
 	client *resty.Client
 	conns  map[string]conn
+	retry  retryPolicy
 }
 
 func NewClient(env *config.Env) *Client {
@@ -24,16 +27,29 @@ func NewClient(env *config.Env) *Client {
 		SetHeader("Notion-Version", "2022-06-28")
 
 	conns := map[string]conn{}
+	limiters := map[string]*tokenLimiter{}
 	for _, ingestProfile := range env.IngestProfiles {
+		if ingestProfile.SheetProviderOrDefault() != entity.SheetProviderNotion {
+			continue
+		}
+
+		limiter, ok := limiters[ingestProfile.NotionToken]
+		if !ok {
+			limiter = newTokenLimiter()
+			limiters[ingestProfile.NotionToken] = limiter
+		}
+
 		conns[ingestProfile.ID] = conn{
 			accessToken: ingestProfile.NotionToken,
 			pageID:      ingestProfile.NotionPageID,
+			limiter:     limiter,
 		}
 	}
 
 	return &Client{
 		client: client,
 		conns:  conns,
+		retry:  defaultRetryPolicy,
 	}
 }
 
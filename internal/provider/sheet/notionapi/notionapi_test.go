@@ -6,12 +6,22 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/mock"
 
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/usecase/ingest"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/pkg/validator"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/companyapi/mockcompanyapi"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/gpt/mockgpt"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/kvstore/mockkvstore"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/openfinance/mockopenfinance"
 	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
 )
 
@@ -159,6 +169,194 @@ func TestEnsureTableColumnsAddsMissingSelectProperty(t *testing.T) {
 	}
 }
 
+func TestEnsureTableColumnsAddsMissingTextAndCheckboxProperties(t *testing.T) {
+	var requestData updateTableReq
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		if request.Method == http.MethodGet {
+			_, _ = fmt.Fprint(writer, `{"properties":{"Notes":{"type":"rich_text"}}}`)
+
+			return
+		}
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = fmt.Fprint(writer, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	err := testClient(server.URL).EnsureTableColumns(
+		t.Context(),
+		"connection",
+		"table",
+		sheet.NewTextColumn("Notes"),
+		sheet.NewTextColumn("External ID").Hidden(),
+		sheet.NewCheckboxColumn("Reviewed"),
+	)
+	if err != nil {
+		t.Fatalf("EnsureTableColumns() error = %v", err)
+	}
+	if len(requestData.Properties) != 2 || requestData.Properties["External ID"].RichText == nil ||
+		requestData.Properties["Reviewed"].Checkbox == nil {
+		t.Fatalf("request properties = %#v", requestData.Properties)
+	}
+}
+
+func TestEnsureTableColumnsAddsMissingNumberPropertiesWithCurrencyFormat(t *testing.T) {
+	var requestData updateTableReq
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		if request.Method == http.MethodGet {
+			_, _ = fmt.Fprint(writer, `{"properties":{"Amount":{"type":"number"}}}`)
+
+			return
+		}
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		_, _ = fmt.Fprint(writer, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	err := testClient(server.URL).EnsureTableColumns(
+		t.Context(),
+		"connection",
+		"table",
+		sheet.NewNumberColumn("Amount").Currency(sheet.Currency("BRL")),
+		sheet.NewNumberColumn("Installment"),
+		sheet.NewNumberColumn("Purchase Amount").Currency(sheet.Currency("BRL")),
+	)
+	if err != nil {
+		t.Fatalf("EnsureTableColumns() error = %v", err)
+	}
+	want := map[string]updateTableReqProperty{
+		"Installment":     {Number: &createTableReqNumber{}},
+		"Purchase Amount": {Number: &createTableReqNumber{Format: "real"}},
+	}
+	if !reflect.DeepEqual(requestData.Properties, want) {
+		t.Fatalf("request properties = %#v, want %#v", requestData.Properties, want)
+	}
+}
+
+func TestEnsureTableColumnsRejectsUnsupportedCurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		if request.Method != http.MethodGet {
+			t.Errorf("unexpected %s request", request.Method)
+		}
+		_, _ = fmt.Fprint(writer, `{"properties":{}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	err := testClient(server.URL).EnsureTableColumns(
+		t.Context(),
+		"connection",
+		"table",
+		sheet.NewNumberColumn("Amount").Currency(sheet.Currency("USD")),
+	)
+	if err == nil {
+		t.Fatal("EnsureTableColumns() error = nil, want unsupported currency")
+	}
+}
+
+func TestIngestUpgradesExistingTableWithInstallmentColumns(t *testing.T) {
+	date := time.Date(2026, time.August, 10, 12, 0, 0, 0, time.UTC)
+	var (
+		mu       sync.Mutex
+		update   updateTableReq
+		inserted []insertRowReq
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case request.Method == http.MethodGet && request.URL.Path == "/v1/blocks/page/children":
+			_, _ = fmt.Fprint(writer, `{"results":[{"id":"august","child_database":{"title":"Aug 2026"}}]}`)
+		case request.Method == http.MethodGet && request.URL.Path == "/v1/databases/august":
+			_, _ = fmt.Fprint(writer, `{"properties":{"Name":{"type":"title"},"Amount":{"type":"number"}}}`)
+		case request.Method == http.MethodPatch && request.URL.Path == "/v1/databases/august":
+			if err := json.NewDecoder(request.Body).Decode(&update); err != nil {
+				t.Errorf("decode update: %v", err)
+			}
+			_, _ = fmt.Fprint(writer, `{}`)
+		case request.Method == http.MethodPost && request.URL.Path == "/v1/databases/august/query":
+			_, _ = fmt.Fprint(writer, `{"results":[]}`)
+		case request.Method == http.MethodPost && request.URL.Path == "/v1/pages":
+			var requestData insertRowReq
+			if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+				t.Errorf("decode insert: %v", err)
+			}
+			inserted = append(inserted, requestData)
+			_, _ = fmt.Fprint(writer, `{"id":"page"}`)
+		default:
+			t.Errorf("unexpected %s %s", request.Method, request.URL.Path)
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	source := mockopenfinance.NewMockOpenFinance(t)
+	source.EXPECT().
+		ListTransactionsByIngestProfileID(mock.Anything, "connection", date, date).
+		Return([]entity.Transaction{{
+			ExternalID:        "market-2",
+			Name:              "Market PARC 02/03",
+			Amount:            50,
+			InstallmentNumber: 2,
+			TotalInstallments: 3,
+			PurchaseAmount:    150,
+			Date:              date,
+		}}, nil).
+		Once()
+
+	categorizer := mockgpt.NewMockGPT(t)
+	categorizer.EXPECT().
+		CreateChatCompletion(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(`{"Market":"Food"}`, nil).
+		Once()
+
+	cache := mockkvstore.NewMockKVStore(t)
+	cache.EXPECT().Get(mock.Anything, mock.Anything).Return(map[string]string{}, nil).Maybe()
+	cache.EXPECT().Set(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	settings := entity.IngestSettings{IngestProfiles: []entity.IngestProfileSettings{{
+		ID:                      "connection",
+		Language:                entity.LanguageEnglish,
+		InstallmentColumns:      true,
+		Categories:              []entity.Category{"Food", entity.DefaultFallbackCategory},
+		Mappings:                map[string]entity.Category{"Market": "Food"},
+		CategorizationChunkSize: entity.DefaultCategorizationChunkSize,
+		Fallback:                entity.DefaultFallbackCategory,
+	}}}
+	_, err := ingest.NewIngest(
+		validator.NewValidator(),
+		1,
+		settings,
+		mockcompanyapi.NewMockCompanyAPI(t),
+		categorizer,
+		cache,
+		testClient(server.URL),
+		source,
+	).Execute(t.Context(), ingest.IngestInput{StartDate: date, EndDate: date})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	wantNumbers := map[string]string{"Installment": "", "Installments": "", "Purchase Amount": "real"}
+	for name, format := range wantNumbers {
+		property := update.Properties[name]
+		if property.Number == nil || property.Number.Format != format {
+			t.Fatalf("property %q = %#v, want number with format %q", name, property, format)
+		}
+	}
+	if len(inserted) != 1 || inserted[0].Properties["Installment"].Number == nil ||
+		*inserted[0].Properties["Installment"].Number != 2 {
+		t.Fatalf("inserted pages = %#v", inserted)
+	}
+}
+
 func TestEnsureTableColumnsMergesSelectOptionsAdditively(t *testing.T) {
 	var requestData updateTableReq
 	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -366,6 +564,7 @@ func TestInsertRowTranslatesEveryCellType(t *testing.T) {
 		"Amount":   sheet.NumberCell(42.5),
 		"Category": sheet.SelectCell("Food, drinks"),
 		"Date":     sheet.DateCell(date),
+		"Reviewed": sheet.CheckboxCell(false),
 	})
 	if err != nil {
 		t.Fatalf("InsertRow() error = %v", err)
@@ -376,7 +575,8 @@ func TestInsertRowTranslatesEveryCellType(t *testing.T) {
 		requestData.Properties["Notes"].RichText[0].Text.Content != "note" ||
 		*requestData.Properties["Amount"].Number != 42.5 ||
 		requestData.Properties["Category"].Select.Name != "Food drinks" ||
-		requestData.Properties["Date"].Date.Start != date.Format(time.RFC3339) {
+		requestData.Properties["Date"].Date.Start != date.Format(time.RFC3339) ||
+		requestData.Properties["Reviewed"].Checkbox == nil || *requestData.Properties["Reviewed"].Checkbox {
 		t.Fatalf("request = %#v", requestData)
 	}
 }
@@ -397,6 +597,38 @@ func TestInsertRowRejectsNilCellBeforeRequest(t *testing.T) {
 	}
 }
 
+func TestInsertRowsCreatesOnePagePerRowAndReportsFailures(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		var requestData insertRowReq
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if requestData.Properties["Name"].Title[0].Text.Content == "Rejected" {
+			writer.WriteHeader(http.StatusBadRequest)
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(writer, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	err := testClient(server.URL).InsertRows(t.Context(), "connection", "table", []sheet.Row{
+		{"Name": sheet.TitleCell("Store")},
+		{"Name": sheet.TitleCell("Rejected")},
+		{"Name": nil},
+		{"Name": sheet.TitleCell("Market")},
+	})
+
+	failed := sheet.FailedRows(err, 4)
+	if len(failed) != 2 || failed[0].Index != 1 || failed[1].Index != 2 {
+		t.Fatalf("InsertRows() error = %v, want rows 1 and 2 to fail", err)
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("requests = %d, want 3", requests.Load())
+	}
+}
+
 func TestListRowsPaginatesMapsPropertiesAndSkipsMalformedDates(t *testing.T) {
 	var requests atomic.Int32
 	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -413,7 +645,7 @@ func TestListRowsPaginatesMapsPropertiesAndSkipsMalformedDates(t *testing.T) {
 		if body.StartCursor == "next" {
 			_, _ = fmt.Fprint(writer, `{
 				"has_more":false,
-				"results":[{"properties":{
+				"results":[{"id":"empty-page","properties":{
 					"Name":{"type":"title","title":[]},
 					"Notes":{"type":"rich_text","rich_text":[]},
 					"Category":{"type":"select","select":null},
@@ -429,12 +661,13 @@ func TestListRowsPaginatesMapsPropertiesAndSkipsMalformedDates(t *testing.T) {
 			"has_more":true,
 			"next_cursor":"next",
 			"results":[
-				{"properties":{
+				{"id":"store-page","properties":{
 					"Name":{"type":"title","title":[{"plain_text":"Store"},{"plain_text":"ignored"}]},
 					"Notes":{"type":"rich_text","rich_text":[{"plain_text":"first"},{"plain_text":"ignored"}]},
 					"Category":{"type":"select","select":{"name":"Food"}},
 					"Amount":{"type":"number","number":42.5},
 					"Date":{"type":"date","date":{"start":"2026-08-09T12:00:00Z"}},
+					"Reviewed":{"type":"checkbox","checkbox":true},
 					"Ignored":{"type":"formula","formula":{"type":"string","string":"x"}}
 				}},
 				{"properties":{"Date":{"type":"date","date":{"start":"not-a-date"}}}}
@@ -443,19 +676,24 @@ func TestListRowsPaginatesMapsPropertiesAndSkipsMalformedDates(t *testing.T) {
 	}))
 	t.Cleanup(server.Close)
 
-	rows, err := testClient(server.URL).ListRows(t.Context(), "connection", "table")
+	records, err := testClient(server.URL).ListRows(t.Context(), "connection", "table")
 	if err != nil {
 		t.Fatalf("ListRows() error = %v", err)
 	}
-	if requests.Load() != 2 || len(rows) != 2 {
-		t.Fatalf("requests = %d, rows = %#v", requests.Load(), rows)
+	if requests.Load() != 2 || len(records) != 2 {
+		t.Fatalf("requests = %d, records = %#v", requests.Load(), records)
+	}
+	if records[0].ID != "store-page" || records[1].ID != "empty-page" {
+		t.Fatalf("record IDs = %q, %q", records[0].ID, records[1].ID)
 	}
+	rows := []sheet.Row{records[0].Row, records[1].Row}
 
 	wantDate := time.Date(2026, time.August, 9, 12, 0, 0, 0, time.UTC)
 	if rows[0]["Name"] != sheet.TitleCell("Store") ||
 		rows[0]["Notes"] != sheet.TextCell("first") ||
 		rows[0]["Category"] != sheet.SelectCell("Food") ||
 		rows[0]["Amount"] != sheet.NumberCell(42.5) ||
+		rows[0]["Reviewed"] != sheet.CheckboxCell(true) ||
 		time.Time(rows[0]["Date"].(sheet.DateCell)) != wantDate {
 		t.Fatalf("first row = %#v", rows[0])
 	}
@@ -467,6 +705,294 @@ func TestListRowsPaginatesMapsPropertiesAndSkipsMalformedDates(t *testing.T) {
 	}
 }
 
+func TestUpdateRowPatchesPageProperties(t *testing.T) {
+	var requestData updateRowReq
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if requests.Add(1) == 1 {
+			writer.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+		if request.Method != http.MethodPatch || request.URL.Path != "/v1/pages/page-id" {
+			t.Errorf("request = %s %s", request.Method, request.URL.Path)
+		}
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(writer, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	err := retryingTestClient(server.URL).UpdateRow(
+		t.Context(),
+		"connection",
+		"table",
+		"page-id",
+		sheet.Row{"Name": sheet.TitleCell("Store"), "Amount": sheet.NumberCell(12.5)},
+	)
+	if err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	if requests.Load() != 2 || len(requestData.Properties) != 2 ||
+		requestData.Properties["Name"].Title[0].Text.Content != "Store" ||
+		*requestData.Properties["Amount"].Number != 12.5 {
+		t.Fatalf("requests = %d, request data = %#v", requests.Load(), requestData)
+	}
+}
+
+func TestDeleteRowArchivesPage(t *testing.T) {
+	var requestData map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPatch || request.URL.Path != "/v1/pages/page-id" {
+			t.Errorf("request = %s %s", request.Method, request.URL.Path)
+		}
+		if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(writer, `{"archived":true}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := retryingTestClient(server.URL)
+	if err := client.DeleteRow(t.Context(), "connection", "table", "page-id"); err != nil {
+		t.Fatalf("DeleteRow() error = %v", err)
+	}
+	if !reflect.DeepEqual(requestData, map[string]any{"archived": true}) {
+		t.Fatalf("request data = %#v", requestData)
+	}
+	if err := client.DeleteRow(t.Context(), "connection", "table", ""); err == nil {
+		t.Fatal("DeleteRow() without row ID error = nil")
+	}
+}
+
+func TestIdempotentCallsRetryTransientFailures(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		switch requests.Add(1) {
+		case 1:
+			writer.Header().Set("Retry-After", "0")
+			writer.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			writer.WriteHeader(http.StatusBadGateway)
+		}
+		_, _ = fmt.Fprint(writer, `{"results":[],"has_more":false}`)
+	}))
+	t.Cleanup(server.Close)
+
+	if _, err := retryingTestClient(server.URL).ListTables(t.Context(), "connection"); err != nil {
+		t.Fatalf("ListTables() error = %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("requests = %d, want 3", requests.Load())
+	}
+}
+
+func TestCallsStopRetryingOnClientErrorsAndAfterMaxAttempts(t *testing.T) {
+	tests := map[string]struct {
+		status       int
+		wantRequests int32
+	}{
+		"client error": {status: http.StatusBadRequest, wantRequests: 1},
+		"server error": {status: http.StatusServiceUnavailable, wantRequests: 3},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				writer.WriteHeader(test.status)
+			}))
+			t.Cleanup(server.Close)
+
+			if _, err := retryingTestClient(server.URL).ListTables(t.Context(), "connection"); err == nil {
+				t.Fatal("ListTables() error = nil")
+			}
+			if requests.Load() != test.wantRequests {
+				t.Fatalf("requests = %d, want %d", requests.Load(), test.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCreateTableOnlyRetriesRateLimits(t *testing.T) {
+	tests := map[string]struct {
+		status       int
+		wantRequests int32
+		wantErr      bool
+	}{
+		"rate limited": {status: http.StatusTooManyRequests, wantRequests: 2},
+		"server error": {status: http.StatusInternalServerError, wantRequests: 1, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				writer.Header().Set("Content-Type", "application/json")
+				if requests.Add(1) == 1 {
+					writer.WriteHeader(test.status)
+				}
+				_, _ = fmt.Fprint(writer, `{"id":"table","title":[{"plain_text":"Jan 2026"}]}`)
+			}))
+			t.Cleanup(server.Close)
+
+			_, err := retryingTestClient(server.URL).CreateTable(
+				t.Context(),
+				"connection",
+				sheet.NewTable("Jan 2026").AddColumn(sheet.NewTitleColumn("Name")),
+			)
+			if (err != nil) != test.wantErr || requests.Load() != test.wantRequests {
+				t.Fatalf("CreateTable() error = %v after %d requests", err, requests.Load())
+			}
+		})
+	}
+}
+
+func TestInsertRowChecksForThePageBeforeRetrying(t *testing.T) {
+	date := time.Date(2026, time.August, 9, 12, 0, 0, 0, time.UTC)
+	row := sheet.Row{
+		"Name":   sheet.TitleCell("Store"),
+		"Notes":  sheet.TextCell(""),
+		"Amount": sheet.NumberCell(-10),
+		"Date":   sheet.DateCell(date),
+	}
+
+	tests := map[string]struct {
+		existing  bool
+		wantPages int32
+	}{
+		"page created by the failed attempt": {existing: true, wantPages: 1},
+		"page missing":                       {existing: false, wantPages: 2},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var pages, queries atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.Header().Set("Content-Type", "application/json")
+				if request.URL.Path == "/v1/databases/table/query" {
+					queries.Add(1)
+					var requestData listRowsReq
+					if err := json.NewDecoder(request.Body).Decode(&requestData); err != nil {
+						t.Errorf("decode request: %v", err)
+					}
+					if requestData.PageSize != 1 || requestData.Filter == nil || len(requestData.Filter.And) != 3 {
+						t.Errorf("query request = %#v", requestData)
+					}
+					results := "[]"
+					if test.existing {
+						results = `[{"properties":{}}]`
+					}
+					_, _ = fmt.Fprintf(writer, `{"results":%s,"has_more":false}`, results)
+
+					return
+				}
+
+				if pages.Add(1) == 1 {
+					writer.WriteHeader(http.StatusBadGateway)
+				}
+				_, _ = fmt.Fprint(writer, `{}`)
+			}))
+			t.Cleanup(server.Close)
+
+			if err := retryingTestClient(server.URL).InsertRow(t.Context(), "connection", "table", row); err != nil {
+				t.Fatalf("InsertRow() error = %v", err)
+			}
+			if pages.Load() != test.wantPages || queries.Load() != 1 {
+				t.Fatalf("pages = %d, queries = %d, want %d pages", pages.Load(), queries.Load(), test.wantPages)
+			}
+		})
+	}
+}
+
+func TestInsertRowRetriesRateLimitsWithoutChecking(t *testing.T) {
+	var pages atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/v1/pages" {
+			t.Errorf("unexpected request %s", request.URL.Path)
+		}
+		if pages.Add(1) == 1 {
+			writer.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	err := retryingTestClient(server.URL).InsertRow(t.Context(), "connection", "table", sheet.Row{
+		"Name": sheet.TitleCell("Store"),
+	})
+	if err != nil || pages.Load() != 2 {
+		t.Fatalf("InsertRow() error = %v after %d pages", err, pages.Load())
+	}
+}
+
+func TestTokenLimiterPausesEveryRequestOfTheToken(t *testing.T) {
+	limiter := newTokenLimiter()
+	limiter.pause(50 * time.Millisecond)
+	limiter.pause(time.Millisecond)
+
+	start := time.Now()
+	if err := limiter.wait(t.Context()); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("wait() returned after %s, want the longest pause", elapsed)
+	}
+}
+
+func TestNewClientSharesLimiterPerToken(t *testing.T) {
+	client := NewClient(&config.Env{IngestProfilesFileData: config.IngestProfilesFileData{
+		IngestProfiles: []entity.IngestProfile{
+			{ID: "first", NotionToken: "token"},
+			{ID: "second", NotionToken: "token"},
+			{ID: "other", NotionToken: "other-token"},
+		},
+	}})
+
+	if client.conns["first"].limiter != client.conns["second"].limiter ||
+		client.conns["first"].limiter == client.conns["other"].limiter {
+		t.Fatalf("conns = %#v, want one limiter per token", client.conns)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 5, baseDelay: 100 * time.Millisecond, maxDelay: time.Second}
+
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		min, max   time.Duration
+	}{
+		{attempt: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 3, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{attempt: 10, min: 500 * time.Millisecond, max: time.Second},
+		{attempt: 1, retryAfter: 2 * time.Second, min: 2 * time.Second, max: 2 * time.Second},
+	}
+	for _, test := range tests {
+		for range 20 {
+			if delay := policy.delay(test.attempt, test.retryAfter); delay < test.min || delay > test.max {
+				t.Fatalf("delay(%d, %s) = %s, want [%s, %s]", test.attempt, test.retryAfter, delay, test.min, test.max)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":                              0,
+		"2":                             2 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Mon, 01 Jan 2001 00:00:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value); got != want {
+			t.Fatalf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
 func TestOperationsRejectMissingConnection(t *testing.T) {
 	client := &Client{client: resty.New(), conns: map[string]conn{}}
 	if _, err := client.CreateTable(t.Context(), "missing", sheet.NewTable("Table")); err == nil {
@@ -475,6 +1001,15 @@ func TestOperationsRejectMissingConnection(t *testing.T) {
 	if err := client.InsertRow(t.Context(), "missing", "table", nil); err == nil {
 		t.Fatal("InsertRow() error = nil")
 	}
+	if err := client.InsertRows(t.Context(), "missing", "table", []sheet.Row{{}}); err == nil {
+		t.Fatal("InsertRows() error = nil")
+	}
+	if err := client.UpdateRow(t.Context(), "missing", "table", "page", nil); err == nil {
+		t.Fatal("UpdateRow() error = nil")
+	}
+	if err := client.DeleteRow(t.Context(), "missing", "table", "page"); err == nil {
+		t.Fatal("DeleteRow() error = nil")
+	}
 	if _, err := client.ListTables(t.Context(), "missing"); err == nil {
 		t.Fatal("ListTables() error = nil")
 	}
@@ -483,6 +1018,13 @@ func TestOperationsRejectMissingConnection(t *testing.T) {
 	}
 }
 
+func retryingTestClient(baseURL string) *Client {
+	client := testClient(baseURL)
+	client.retry = retryPolicy{maxAttempts: 3, baseDelay: time.Millisecond, maxDelay: 2 * time.Millisecond}
+
+	return client
+}
+
 func testClient(baseURL string) *Client {
 	return &Client{
 		client: resty.New().SetBaseURL(baseURL),
@@ -0,0 +1,191 @@
+package notionapi
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
+)
+
+// requestsPerSecond is Notion's average request limit for each integration token.
+const requestsPerSecond = 3
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts: 5,
+	baseDelay:   500 * time.Millisecond,
+	maxDelay:    30 * time.Second,
+}
+
+// errAlreadyApplied reports that a call that failed without a clear answer took effect anyway,
+// so it must not be sent again.
+var errAlreadyApplied = errors.New("call already applied")
+
+// tokenLimiter paces the requests of one integration token. Notion rate limits the token as a
+// whole, so every profile sharing it shares the limiter.
+type tokenLimiter struct {
+	limiter *rate.Limiter
+
+	mutex       sync.Mutex
+	pausedUntil time.Time
+}
+
+func newTokenLimiter() *tokenLimiter {
+	return &tokenLimiter{limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), requestsPerSecond)}
+}
+
+// wait blocks until the token may send another request. A nil limiter never blocks.
+func (l *tokenLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	pause := time.Until(l.pausedUntil)
+	l.mutex.Unlock()
+	if err := sleep(ctx, pause); err != nil {
+		return err
+	}
+
+	return l.limiter.Wait(ctx)
+}
+
+// pause holds back every request of the token after Notion asked it to slow down.
+func (l *tokenLimiter) pause(duration time.Duration) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if until := time.Now().Add(duration); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// retryPolicy is the zero value for a single attempt.
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// delay doubles the base delay on every attempt, up to the maximum, and picks a random point in
+// its upper half so concurrent callers do not retry in lockstep. Notion's Retry-After wins when
+// it asks for a longer wait.
+func (p retryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	backoff := p.baseDelay << min(attempt-1, 30)
+	if backoff <= 0 || backoff > p.maxDelay {
+		backoff = p.maxDelay
+	}
+
+	jittered := backoff / 2
+	if half := int64(backoff - jittered); half > 0 {
+		jittered += time.Duration(rand.Int64N(half + 1))
+	}
+
+	return max(jittered, retryAfter)
+}
+
+// verifyFunc reports whether a non-idempotent call that failed without a clear answer took
+// effect anyway.
+type verifyFunc func(ctx context.Context) (bool, error)
+
+// send performs a Notion API call through the token limiter. Idempotent calls are retried on
+// network errors, 429 and 5xx responses. Other calls are retried blindly only after a 429, which
+// Notion rejects before processing; after any other failure verify must first confirm that the
+// call did not take effect, or send returns errAlreadyApplied when it did.
+func (c *Client) send(
+	ctx context.Context,
+	conn conn,
+	idempotent bool,
+	verify verifyFunc,
+	call func(request *resty.Request) (*resty.Response, error),
+) (*resty.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := conn.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		res, err := call(c.client.R().
+			SetContext(ctx).
+			SetHeader("Authorization", "Bearer "+conn.accessToken))
+
+		retryAfter, transient := transientFailure(res, err)
+		if !transient || attempt >= c.retry.maxAttempts || ctx.Err() != nil {
+			return res, err
+		}
+		conn.limiter.pause(retryAfter)
+
+		rejected := err == nil && res.StatusCode() == http.StatusTooManyRequests
+		if !idempotent && !rejected {
+			if verify == nil {
+				return res, err
+			}
+
+			applied, verifyErr := verify(ctx)
+			if verifyErr != nil {
+				return res, err
+			}
+			if applied {
+				return nil, errAlreadyApplied
+			}
+		}
+
+		if err := sleep(ctx, c.retry.delay(attempt, retryAfter)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// transientFailure reports whether a call may succeed when sent again, and how long Notion asked
+// to wait before that.
+func transientFailure(res *resty.Response, err error) (time.Duration, bool) {
+	if err != nil {
+		return 0, !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	status := res.StatusCode()
+	if status != http.StatusTooManyRequests && status < http.StatusInternalServerError {
+		return 0, false
+	}
+
+	return parseRetryAfter(res.Header().Get("Retry-After")), true
+}
+
+// parseRetryAfter reads the Retry-After header, given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
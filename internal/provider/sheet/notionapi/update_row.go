@@ -0,0 +1,57 @@
+package notionapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+type updateRowReq struct {
+	Properties map[string]insertRowReqProperty `json:"properties"`
+}
+
+// UpdateRow patches the properties of the page with the given ID. Setting the same properties
+// twice has the same effect, so the call is retried like a read.
+func (c *Client) UpdateRow(
+	ctx context.Context,
+	connectionID, _, rowID string,
+	row sheet.Row,
+) error {
+	conn, ok := c.conns[connectionID]
+	if !ok {
+		return errors.New("connection not found for ingest profile " + connectionID)
+	}
+	if rowID == "" {
+		return errors.New("row ID is required")
+	}
+
+	requestData := updateRowReq{Properties: make(map[string]insertRowReqProperty, len(row))}
+	for column, cell := range row {
+		property, err := insertRowProperty(cell)
+		if err != nil {
+			return fmt.Errorf("invalid row: column %q: %w", column, err)
+		}
+		requestData.Properties[column] = property
+	}
+
+	res, err := c.send(ctx, conn, true, nil, func(request *resty.Request) (*resty.Response, error) {
+		return request.SetBody(requestData).Patch(fmt.Sprintf("/v1/pages/%s", rowID))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update row %q with request data %+v: %w", rowID, requestData, err)
+	}
+	if res.IsError() {
+		return fmt.Errorf(
+			"failed to update row %q with request data %+v and response %s",
+			rowID,
+			requestData,
+			res.Body(),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package sheet
+
+import (
+	"context"
+	"errors"
+)
+
+// Router dispatches each operation to the provider configured for its connection.
+type Router struct {
+	providers map[string]Provider
+}
+
+func NewRouter(providers map[string]Provider) *Router {
+	return &Router{providers: providers}
+}
+
+func (r *Router) CreateTable(
+	ctx context.Context,
+	connectionID string,
+	definition TableDefinition,
+) (Table, error) {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return Table{}, err
+	}
+
+	return provider.CreateTable(ctx, connectionID, definition)
+}
+
+func (r *Router) EnsureTableColumns(
+	ctx context.Context,
+	connectionID string,
+	tableID string,
+	columns ...Column,
+) error {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return err
+	}
+
+	return provider.EnsureTableColumns(ctx, connectionID, tableID, columns...)
+}
+
+func (r *Router) InsertRow(ctx context.Context, connectionID, tableID string, row Row) error {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return err
+	}
+
+	return provider.InsertRow(ctx, connectionID, tableID, row)
+}
+
+func (r *Router) InsertRows(ctx context.Context, connectionID, tableID string, rows []Row) error {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return err
+	}
+
+	return provider.InsertRows(ctx, connectionID, tableID, rows)
+}
+
+func (r *Router) UpdateRow(ctx context.Context, connectionID, tableID, rowID string, row Row) error {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return err
+	}
+
+	return provider.UpdateRow(ctx, connectionID, tableID, rowID, row)
+}
+
+func (r *Router) DeleteRow(ctx context.Context, connectionID, tableID, rowID string) error {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return err
+	}
+
+	return provider.DeleteRow(ctx, connectionID, tableID, rowID)
+}
+
+func (r *Router) ListTables(ctx context.Context, connectionID string) ([]Table, error) {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.ListTables(ctx, connectionID)
+}
+
+func (r *Router) ListRows(ctx context.Context, connectionID, tableID string) ([]Record, error) {
+	provider, err := r.provider(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.ListRows(ctx, connectionID, tableID)
+}
+
+func (r *Router) provider(connectionID string) (Provider, error) {
+	provider, ok := r.providers[connectionID]
+	if !ok || provider == nil {
+		return nil, errors.New("sheet provider not found for ingest profile " + connectionID)
+	}
+
+	return provider, nil
+}
+
+var _ Provider = (*Router)(nil)
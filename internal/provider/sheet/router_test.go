@@ -0,0 +1,101 @@
+package sheet_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet/mocksheet"
+)
+
+func TestRouterDispatchesByConnection(t *testing.T) {
+	notion := mocksheet.NewMockSheet(t)
+	googleSheets := mocksheet.NewMockSheet(t)
+	router := sheet.NewRouter(map[string]sheet.Provider{
+		"notion-profile": notion,
+		"sheets-profile": googleSheets,
+	})
+
+	notion.EXPECT().
+		ListTables(t.Context(), "notion-profile").
+		Return([]sheet.Table{{ID: "database", Title: "Jan 2026"}}, nil).
+		Once()
+	googleSheets.EXPECT().
+		ListTables(t.Context(), "sheets-profile").
+		Return([]sheet.Table{{ID: "42", Title: "Jan 2026"}}, nil).
+		Once()
+	googleSheets.EXPECT().
+		InsertRow(t.Context(), "sheets-profile", "42", sheet.Row{"Name": sheet.TitleCell("Store")}).
+		Return(nil).
+		Once()
+	notion.EXPECT().
+		InsertRows(t.Context(), "notion-profile", "database", []sheet.Row{{"Name": sheet.TitleCell("Store")}}).
+		Return(nil).
+		Once()
+	notion.EXPECT().
+		UpdateRow(t.Context(), "notion-profile", "database", "page", sheet.Row{"Amount": sheet.NumberCell(12)}).
+		Return(nil).
+		Once()
+	notion.EXPECT().DeleteRow(t.Context(), "notion-profile", "database", "page").Return(nil).Once()
+
+	tables, err := router.ListTables(t.Context(), "notion-profile")
+	if err != nil || !reflect.DeepEqual(tables, []sheet.Table{{ID: "database", Title: "Jan 2026"}}) {
+		t.Fatalf("ListTables(notion) = %#v, %v", tables, err)
+	}
+	tables, err = router.ListTables(t.Context(), "sheets-profile")
+	if err != nil || !reflect.DeepEqual(tables, []sheet.Table{{ID: "42", Title: "Jan 2026"}}) {
+		t.Fatalf("ListTables(sheets) = %#v, %v", tables, err)
+	}
+	if err := router.InsertRow(t.Context(), "sheets-profile", "42", sheet.Row{"Name": sheet.TitleCell("Store")}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	if err := router.InsertRows(
+		t.Context(),
+		"notion-profile",
+		"database",
+		[]sheet.Row{{"Name": sheet.TitleCell("Store")}},
+	); err != nil {
+		t.Fatalf("InsertRows() error = %v", err)
+	}
+	if err := router.UpdateRow(
+		t.Context(),
+		"notion-profile",
+		"database",
+		"page",
+		sheet.Row{"Amount": sheet.NumberCell(12)},
+	); err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	if err := router.DeleteRow(t.Context(), "notion-profile", "database", "page"); err != nil {
+		t.Fatalf("DeleteRow() error = %v", err)
+	}
+}
+
+func TestRouterRejectsUnknownConnection(t *testing.T) {
+	router := sheet.NewRouter(map[string]sheet.Provider{})
+
+	if _, err := router.ListTables(t.Context(), "missing"); err == nil {
+		t.Fatal("ListTables() error = nil, want missing provider error")
+	}
+	if _, err := router.CreateTable(t.Context(), "missing", sheet.NewTable("Jan 2026")); err == nil {
+		t.Fatal("CreateTable() error = nil, want missing provider error")
+	}
+	if err := router.EnsureTableColumns(t.Context(), "missing", "table"); err == nil {
+		t.Fatal("EnsureTableColumns() error = nil, want missing provider error")
+	}
+	if err := router.InsertRow(t.Context(), "missing", "table", sheet.Row{}); err == nil {
+		t.Fatal("InsertRow() error = nil, want missing provider error")
+	}
+	if err := router.InsertRows(t.Context(), "missing", "table", []sheet.Row{{}}); err == nil {
+		t.Fatal("InsertRows() error = nil, want missing provider error")
+	}
+	if err := router.UpdateRow(t.Context(), "missing", "table", "row", sheet.Row{}); err == nil {
+		t.Fatal("UpdateRow() error = nil, want missing provider error")
+	}
+	if err := router.DeleteRow(t.Context(), "missing", "table", "row"); err == nil {
+		t.Fatal("DeleteRow() error = nil, want missing provider error")
+	}
+	if _, err := router.ListRows(t.Context(), "missing", "table"); err == nil {
+		t.Fatal("ListRows() error = nil, want missing provider error")
+	}
+}
@@ -18,8 +18,16 @@ type Provider interface {
 		columns ...Column,
 	) error
 	InsertRow(ctx context.Context, connectionID, tableID string, row Row) error
+	// InsertRows inserts the rows in as few backend calls as possible. When only some rows fail
+	// it returns an *InsertRowsError listing them.
+	InsertRows(ctx context.Context, connectionID, tableID string, rows []Row) error
+	// UpdateRow overwrites the cells the row sets on the stored row with the given record ID,
+	// leaving its other cells unchanged.
+	UpdateRow(ctx context.Context, connectionID, tableID, rowID string, row Row) error
+	// DeleteRow removes the stored row with the given record ID; Notion archives its page instead.
+	DeleteRow(ctx context.Context, connectionID, tableID, rowID string) error
 	ListTables(ctx context.Context, connectionID string) ([]Table, error)
-	ListRows(ctx context.Context, connectionID, tableID string) ([]Row, error)
+	ListRows(ctx context.Context, connectionID, tableID string) ([]Record, error)
 }
 
 type Table struct {
@@ -29,6 +37,12 @@ type Table struct {
 
 type Row map[string]Cell
 
+// Record is a stored row and the ID that addresses it in UpdateRow and DeleteRow.
+type Record struct {
+	ID  string
+	Row Row
+}
+
 type Cell interface {
 	isCell()
 }
@@ -38,9 +52,11 @@ type TextCell string
 type NumberCell float64
 type SelectCell string
 type DateCell time.Time
+type CheckboxCell bool
 
-func (TitleCell) isCell()  {}
-func (TextCell) isCell()   {}
-func (NumberCell) isCell() {}
-func (SelectCell) isCell() {}
-func (DateCell) isCell()   {}
+func (TitleCell) isCell()    {}
+func (TextCell) isCell()     {}
+func (NumberCell) isCell()   {}
+func (SelectCell) isCell()   {}
+func (DateCell) isCell()     {}
+func (CheckboxCell) isCell() {}
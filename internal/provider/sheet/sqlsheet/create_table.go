@@ -0,0 +1,61 @@
+package sqlsheet
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// CreateTable registers the table and its columns, adding any typed column sheet_rows does not
+// have yet. The icon and option colors have no place in a database and are ignored.
+func (c *Client) CreateTable(
+	ctx context.Context,
+	connectionID string,
+	definition sheet.TableDefinition,
+) (sheet.Table, error) {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return sheet.Table{}, err
+	}
+
+	if err := definition.Validate(); err != nil {
+		return sheet.Table{}, fmt.Errorf("invalid table definition: %w", err)
+	}
+
+	columns := make([]schemaColumn, 0, len(definition.Columns()))
+	for _, column := range definition.Columns() {
+		schemaColumn, err := newSchemaColumn(column)
+		if err != nil {
+			return sheet.Table{}, fmt.Errorf("invalid table definition: column %q: %w", column.Name(), err)
+		}
+		columns = append(columns, schemaColumn)
+	}
+
+	table := sheet.Table{ID: rand.Text(), Title: definition.Title()}
+
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+
+	d := database.dialect
+	err = database.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO `+tablesTable+` (id, title, connection_id) VALUES (`+d.placeholders(1, 3)+`)`,
+			table.ID,
+			table.Title,
+			connectionID,
+		); err != nil {
+			return fmt.Errorf("failed to create table %q: %w", table.Title, err)
+		}
+
+		return ensureColumns(ctx, tx, d, table.ID, columns)
+	})
+	if err != nil {
+		return sheet.Table{}, err
+	}
+
+	return table, nil
+}
@@ -0,0 +1,43 @@
+package sqlsheet
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// DeleteRow deletes the sheet_rows entry with the given id. Other rows keep their ids.
+func (c *Client) DeleteRow(ctx context.Context, connectionID, tableID, rowID string) error {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(rowID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid row id %q: %w", rowID, err)
+	}
+
+	d := database.dialect
+	if err := tableExists(ctx, database.db, d, connectionID, tableID); err != nil {
+		return err
+	}
+	result, err := database.db.ExecContext(
+		ctx,
+		`DELETE FROM `+rowsTable+` WHERE id = `+d.placeholder(1)+` AND table_id = `+d.placeholder(2),
+		id,
+		tableID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete row %q of table %q: %w", rowID, tableID, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete row %q of table %q: %w", rowID, tableID, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("row %q not found in table %q", rowID, tableID)
+	}
+
+	return nil
+}
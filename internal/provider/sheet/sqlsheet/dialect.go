@@ -0,0 +1,76 @@
+package sqlsheet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// dialect holds the statements that differ between the supported databases.
+type dialect struct {
+	driverName     string
+	rowIDColumn    string
+	numberType     string
+	dateType       string
+	listColumnsSQL string
+	positional     bool
+}
+
+var dialects = map[entity.SQLDriver]dialect{
+	entity.SQLDriverSQLite: {
+		driverName:     "sqlite",
+		rowIDColumn:    "INTEGER PRIMARY KEY AUTOINCREMENT",
+		numberType:     "REAL",
+		dateType:       "TIMESTAMP",
+		listColumnsSQL: "SELECT name FROM pragma_table_info('" + rowsTable + "')",
+	},
+	entity.SQLDriverPostgres: {
+		driverName:  "pgx",
+		rowIDColumn: "BIGSERIAL PRIMARY KEY",
+		numberType:  "DOUBLE PRECISION",
+		dateType:    "TIMESTAMPTZ",
+		listColumnsSQL: "SELECT column_name FROM information_schema.columns " +
+			"WHERE table_schema = current_schema() AND table_name = '" + rowsTable + "'",
+		positional: true,
+	},
+}
+
+// placeholder returns the bind parameter for the one-based argument index.
+func (d dialect) placeholder(index int) string {
+	if d.positional {
+		return "$" + strconv.Itoa(index)
+	}
+
+	return "?"
+}
+
+func (d dialect) placeholders(from, count int) string {
+	placeholders := make([]string, count)
+	for index := range placeholders {
+		placeholders[index] = d.placeholder(from + index)
+	}
+
+	return strings.Join(placeholders, ", ")
+}
+
+func (d dialect) columnType(columnType sheet.ColumnType) (string, error) {
+	switch columnType {
+	case sheet.ColumnTypeTitle, sheet.ColumnTypeText, sheet.ColumnTypeSelect:
+		return "TEXT", nil
+	case sheet.ColumnTypeNumber:
+		return d.numberType, nil
+	case sheet.ColumnTypeDate:
+		return d.dateType, nil
+	case sheet.ColumnTypeCheckbox:
+		return "BOOLEAN", nil
+	default:
+		return "", fmt.Errorf("unsupported column type %q", columnType)
+	}
+}
+
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
@@ -0,0 +1,52 @@
+package sqlsheet
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// EnsureTableColumns merges new select options and adds missing columns of any type. Existing rows
+// read the new columns as empty.
+func (c *Client) EnsureTableColumns(
+	ctx context.Context,
+	connectionID string,
+	tableID string,
+	columns ...sheet.Column,
+) error {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+
+	schemaColumns := make([]schemaColumn, 0, len(columns))
+	for columnIndex, column := range columns {
+		if column == nil {
+			return fmt.Errorf("invalid columns: column %d is nil", columnIndex)
+		}
+
+		definition := column.Definition()
+		if err := definition.Validate(); err != nil {
+			return fmt.Errorf("invalid columns: column %d: %w", columnIndex, err)
+		}
+
+		schemaColumn, err := newSchemaColumn(definition)
+		if err != nil {
+			return fmt.Errorf("invalid columns: column %q: %w", definition.Name(), err)
+		}
+		schemaColumns = append(schemaColumns, schemaColumn)
+	}
+
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+
+	return database.inTx(ctx, func(tx *sql.Tx) error {
+		if err := tableExists(ctx, tx, database.dialect, connectionID, tableID); err != nil {
+			return err
+		}
+
+		return ensureColumns(ctx, tx, database.dialect, tableID, schemaColumns)
+	})
+}
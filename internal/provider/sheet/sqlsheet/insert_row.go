@@ -0,0 +1,102 @@
+package sqlsheet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+func (c *Client) InsertRow(
+	ctx context.Context,
+	connectionID, tableID string,
+	row sheet.Row,
+) error {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+
+	d := database.dialect
+	if err := tableExists(ctx, database.db, d, connectionID, tableID); err != nil {
+		return err
+	}
+	columns, err := loadColumns(ctx, database.db, d, tableID)
+	if err != nil {
+		return err
+	}
+
+	query, values, err := rowInsert(d, tableID, columns, row)
+	if err != nil {
+		return fmt.Errorf("invalid row: %w", err)
+	}
+
+	if _, err := database.db.ExecContext(ctx, query, values...); err != nil {
+		return fmt.Errorf("failed to insert row into table %q: %w", tableID, err)
+	}
+
+	return nil
+}
+
+// rowInsert builds the INSERT statement of a row, binding only the columns the row sets.
+func rowInsert(d dialect, tableID string, columns []schemaColumn, row sheet.Row) (string, []any, error) {
+	columnNames := []string{"table_id"}
+	values := []any{tableID}
+	for name, cell := range row {
+		index := slices.IndexFunc(columns, func(column schemaColumn) bool {
+			return column.name == name
+		})
+		if index < 0 {
+			return "", nil, fmt.Errorf("column %q not found", name)
+		}
+
+		value, err := cellValue(columns[index].columnType, cell)
+		if err != nil {
+			return "", nil, fmt.Errorf("column %q: %w", name, err)
+		}
+		columnNames = append(columnNames, quoteIdentifier(columns[index].columnName))
+		values = append(values, value)
+	}
+
+	query := `INSERT INTO ` + rowsTable + ` (` + strings.Join(columnNames, ", ") + `) VALUES (` +
+		d.placeholders(1, len(values)) + `)`
+
+	return query, values, nil
+}
+
+// cellValue converts a cell into the value bound to its typed column. Dates are stored in UTC so
+// they read back the same on every driver.
+func cellValue(columnType sheet.ColumnType, cell sheet.Cell) (any, error) {
+	if cell == nil {
+		return nil, errors.New("cell is nil")
+	}
+
+	switch value := cell.(type) {
+	case sheet.TitleCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeTitle, string(value))
+	case sheet.TextCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeText, string(value))
+	case sheet.NumberCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeNumber, float64(value))
+	case sheet.SelectCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeSelect, string(value))
+	case sheet.DateCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeDate, time.Time(value).UTC())
+	case sheet.CheckboxCell:
+		return cellValueOfType(columnType, sheet.ColumnTypeCheckbox, bool(value))
+	default:
+		return nil, fmt.Errorf("unsupported cell type %T", cell)
+	}
+}
+
+func cellValueOfType(columnType, cellType sheet.ColumnType, value any) (any, error) {
+	if columnType != cellType {
+		return nil, fmt.Errorf("cell type %q does not match column type %q", cellType, columnType)
+	}
+
+	return value, nil
+}
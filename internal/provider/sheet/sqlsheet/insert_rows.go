@@ -0,0 +1,72 @@
+package sqlsheet
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// InsertRows inserts every valid row in one transaction, so either all of them are stored or, when
+// a statement fails, none are. Invalid rows are reported and skipped.
+func (c *Client) InsertRows(
+	ctx context.Context,
+	connectionID, tableID string,
+	rows []sheet.Row,
+) error {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	d := database.dialect
+	if err := tableExists(ctx, database.db, d, connectionID, tableID); err != nil {
+		return err
+	}
+	columns, err := loadColumns(ctx, database.db, d, tableID)
+	if err != nil {
+		return err
+	}
+
+	type statement struct {
+		query  string
+		values []any
+	}
+
+	var rowErrors []sheet.RowError
+	statements := make([]statement, 0, len(rows))
+	indexes := make([]int, 0, len(rows))
+	for index, row := range rows {
+		query, values, err := rowInsert(d, tableID, columns, row)
+		if err != nil {
+			rowErrors = append(rowErrors, sheet.RowError{Index: index, Err: fmt.Errorf("invalid row: %w", err)})
+
+			continue
+		}
+		statements = append(statements, statement{query: query, values: values})
+		indexes = append(indexes, index)
+	}
+
+	if len(statements) > 0 {
+		err := database.inTx(ctx, func(tx *sql.Tx) error {
+			for _, statement := range statements {
+				if _, err := tx.ExecContext(ctx, statement.query, statement.values...); err != nil {
+					return fmt.Errorf("failed to insert rows into table %q: %w", tableID, err)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			for _, index := range indexes {
+				rowErrors = append(rowErrors, sheet.RowError{Index: index, Err: err})
+			}
+		}
+	}
+
+	return sheet.NewInsertRowsError(len(rows), rowErrors)
+}
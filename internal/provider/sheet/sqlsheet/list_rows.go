@@ -0,0 +1,214 @@
+package sqlsheet
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// sqliteTimeFormats are the layouts SQLite drivers use when a timestamp is stored as text.
+var sqliteTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+func (c *Client) ListRows(
+	ctx context.Context,
+	connectionID, tableID string,
+) ([]sheet.Record, error) {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := database.dialect
+	if err := tableExists(ctx, database.db, d, connectionID, tableID); err != nil {
+		return nil, err
+	}
+	columns, err := loadColumns(ctx, database.db, d, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	columnNames := make([]string, 0, len(columns)+1)
+	columnNames = append(columnNames, "id")
+	for _, column := range columns {
+		columnNames = append(columnNames, quoteIdentifier(column.columnName))
+	}
+
+	rows, err := database.db.QueryContext(
+		ctx,
+		`SELECT `+strings.Join(columnNames, ", ")+` FROM `+rowsTable+
+			` WHERE table_id = `+d.placeholder(1)+` ORDER BY id`,
+		tableID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rows of table %q: %w", tableID, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []sheet.Record
+	for rows.Next() {
+		var id int64
+		values := make([]any, len(columns))
+		targets := make([]any, 0, len(columns)+1)
+		targets = append(targets, &id)
+		for index := range values {
+			targets = append(targets, &values[index])
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("failed to scan row of table %q: %w", tableID, err)
+		}
+
+		row, err := mapValuesToRow(columns, values)
+		if err != nil {
+			continue
+		}
+		result = append(result, sheet.Record{ID: strconv.FormatInt(id, 10), Row: row})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list rows of table %q: %w", tableID, err)
+	}
+
+	return result, nil
+}
+
+// mapValuesToRow fails on values that do not match the column type, so the caller can skip a row
+// edited by hand instead of failing the whole table.
+func mapValuesToRow(columns []schemaColumn, values []any) (sheet.Row, error) {
+	row := make(sheet.Row, len(columns))
+	for index, column := range columns {
+		cell, ok, err := mapValueToCell(column.columnType, values[index])
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", column.name, err)
+		}
+		if ok {
+			row[column.name] = cell
+		}
+	}
+
+	return row, nil
+}
+
+func mapValueToCell(columnType sheet.ColumnType, value any) (sheet.Cell, bool, error) {
+	if bytes, ok := value.([]byte); ok {
+		value = string(bytes)
+	}
+
+	switch columnType {
+	case sheet.ColumnTypeTitle, sheet.ColumnTypeText:
+		text, ok := value.(string)
+		if value != nil && !ok {
+			return nil, false, fmt.Errorf("unexpected text value %T", value)
+		}
+		if columnType == sheet.ColumnTypeTitle {
+			return sheet.TitleCell(text), true, nil
+		}
+
+		return sheet.TextCell(text), true, nil
+	case sheet.ColumnTypeSelect:
+		if value == nil || value == "" {
+			return nil, false, nil
+		}
+		text, ok := value.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("unexpected select value %T", value)
+		}
+
+		return sheet.SelectCell(text), true, nil
+	case sheet.ColumnTypeNumber:
+		number, ok, err := parseNumber(value)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+
+		return sheet.NumberCell(number), true, nil
+	case sheet.ColumnTypeDate:
+		date, ok, err := parseDate(value)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+
+		return sheet.DateCell(date), true, nil
+	case sheet.ColumnTypeCheckbox:
+		checked, ok, err := parseBool(value)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+
+		return sheet.CheckboxCell(checked), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+func parseNumber(value any) (float64, bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return value, true, nil
+	case int64:
+		return float64(value), true, nil
+	case string:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to parse number %s: %w", value, err)
+		}
+
+		return number, true, nil
+	default:
+		return 0, false, fmt.Errorf("unexpected number value %T", value)
+	}
+}
+
+// parseBool reads a checkbox, which SQLite stores as an integer.
+func parseBool(value any) (bool, bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return false, false, nil
+	case bool:
+		return value, true, nil
+	case int64:
+		return value != 0, true, nil
+	case string:
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to parse checkbox %s: %w", value, err)
+		}
+
+		return checked, true, nil
+	default:
+		return false, false, fmt.Errorf("unexpected checkbox value %T", value)
+	}
+}
+
+func parseDate(value any) (time.Time, bool, error) {
+	switch value := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return value.UTC(), true, nil
+	case string:
+		for _, layout := range sqliteTimeFormats {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date.UTC(), true, nil
+			}
+		}
+
+		return time.Time{}, false, fmt.Errorf("failed to parse date %s", value)
+	default:
+		return time.Time{}, false, fmt.Errorf("unexpected date value %T", value)
+	}
+}
@@ -0,0 +1,46 @@
+package sqlsheet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// ListTables returns the tables the profile created.
+func (c *Client) ListTables(
+	ctx context.Context,
+	connectionID string,
+) ([]sheet.Table, error) {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := database.db.QueryContext(
+		ctx,
+		`SELECT id, title FROM `+tablesTable+` WHERE connection_id = `+database.dialect.placeholder(1)+
+			` ORDER BY title, id`,
+		connectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var tables []sheet.Table
+	for rows.Next() {
+		var table sheet.Table
+		if err := rows.Scan(&table.ID, &table.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	return tables, nil
+}
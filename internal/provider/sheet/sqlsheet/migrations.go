@@ -0,0 +1,99 @@
+package sqlsheet
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	migrationsTable = "sheet_migrations"
+	tablesTable     = "sheet_tables"
+	columnsTable    = "sheet_columns"
+	rowsTable       = "sheet_rows"
+)
+
+// migrations create the fixed part of the schema. Typed data columns are added to sheet_rows on
+// demand by CreateTable and EnsureTableColumns, and recorded in sheet_columns. Tables belong to
+// the profile that created them, so profiles sharing a database keep apart.
+var migrations = []func(dialect) []string{
+	func(d dialect) []string {
+		return []string{
+			`CREATE TABLE ` + tablesTable + ` (
+				id TEXT PRIMARY KEY,
+				connection_id TEXT NOT NULL,
+				title TEXT NOT NULL
+			)`,
+			`CREATE INDEX sheet_tables_connection_id_idx ON ` + tablesTable + ` (connection_id)`,
+			`CREATE TABLE ` + columnsTable + ` (
+				table_id TEXT NOT NULL REFERENCES ` + tablesTable + `(id),
+				name TEXT NOT NULL,
+				column_name TEXT NOT NULL,
+				type TEXT NOT NULL,
+				currency TEXT NOT NULL DEFAULT '',
+				options TEXT NOT NULL DEFAULT '[]',
+				position INTEGER NOT NULL,
+				PRIMARY KEY (table_id, name)
+			)`,
+			`CREATE TABLE ` + rowsTable + ` (
+				id ` + d.rowIDColumn + `,
+				table_id TEXT NOT NULL REFERENCES ` + tablesTable + `(id)
+			)`,
+			`CREATE INDEX sheet_rows_table_id_idx ON ` + rowsTable + ` (table_id)`,
+		}
+	},
+}
+
+func migrate(ctx context.Context, db *sql.DB, d dialect) error {
+	if _, err := db.ExecContext(
+		ctx,
+		`CREATE TABLE IF NOT EXISTS `+migrationsTable+` (version INTEGER PRIMARY KEY)`,
+	); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	var version int
+	if err := db.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM `+migrationsTable,
+	).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for index := version; index < len(migrations); index++ {
+		if err := applyMigration(ctx, db, d, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, d dialect, index int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", index+1, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, statement := range migrations[index](d) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", index+1, err)
+		}
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO `+migrationsTable+` (version) VALUES (`+d.placeholder(1)+`)`,
+		index+1,
+	); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", index+1, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", index+1, err)
+	}
+
+	return nil
+}
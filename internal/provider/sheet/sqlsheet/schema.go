@@ -0,0 +1,324 @@
+package sqlsheet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+const maxIdentifierLength = 63
+
+type schemaColumn struct {
+	name       string
+	columnName string
+	columnType sheet.ColumnType
+	currency   sheet.Currency
+	options    []string
+}
+
+func newSchemaColumn(definition sheet.ColumnDefinition) (schemaColumn, error) {
+	switch definition.Currency() {
+	case "", sheet.Currency("BRL"):
+	default:
+		return schemaColumn{}, fmt.Errorf("unsupported currency %q", definition.Currency())
+	}
+
+	column := schemaColumn{
+		name:       definition.Name(),
+		columnName: physicalColumnName(definition.Name()),
+		columnType: definition.Type(),
+		currency:   definition.Currency(),
+	}
+	for _, option := range definition.SelectOptions() {
+		if !slices.Contains(column.options, option.Name()) {
+			column.options = append(column.options, option.Name())
+		}
+	}
+
+	return column, nil
+}
+
+// physicalColumnName turns a column name into a plain SQL identifier, e.g. "Grupo do orçamento"
+// into grupo_do_orcamento, so the data can be queried without quoting.
+func physicalColumnName(name string) string {
+	var builder strings.Builder
+	separate := false
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if separate && builder.Len() > 0 {
+				builder.WriteByte('_')
+			}
+			builder.WriteRune(r)
+			separate = false
+		default:
+			separate = true
+		}
+	}
+
+	columnName := builder.String()
+	if columnName == "" || columnName == "id" || columnName == "table_id" || unicode.IsDigit(rune(columnName[0])) {
+		columnName = "c_" + columnName
+	}
+
+	return columnName[:min(len(columnName), maxIdentifierLength)]
+}
+
+// tableExists fails unless the table belongs to the profile, which keeps every row operation
+// within the profile's own tables.
+func tableExists(ctx context.Context, q querier, d dialect, connectionID, tableID string) error {
+	var id string
+	err := q.QueryRowContext(
+		ctx,
+		`SELECT id FROM `+tablesTable+` WHERE id = `+d.placeholder(1)+` AND connection_id = `+d.placeholder(2),
+		tableID,
+		connectionID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("table %q not found", tableID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find table %q: %w", tableID, err)
+	}
+
+	return nil
+}
+
+func loadColumns(ctx context.Context, q querier, d dialect, tableID string) ([]schemaColumn, error) {
+	rows, err := q.QueryContext(
+		ctx,
+		`SELECT name, column_name, type, currency, options FROM `+columnsTable+
+			` WHERE table_id = `+d.placeholder(1)+` ORDER BY position`,
+		tableID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load columns of table %q: %w", tableID, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var columns []schemaColumn
+	for rows.Next() {
+		var column schemaColumn
+		var options string
+		if err := rows.Scan(
+			&column.name,
+			&column.columnName,
+			&column.columnType,
+			&column.currency,
+			&options,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan column of table %q: %w", tableID, err)
+		}
+		if err := json.Unmarshal([]byte(options), &column.options); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal options of column %q: %w", column.name, err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load columns of table %q: %w", tableID, err)
+	}
+
+	return columns, nil
+}
+
+// ensureColumns adds the missing columns of a table and merges new select options into the
+// existing ones. Adding a column also adds its typed column to sheet_rows when no other table
+// has created it yet.
+func ensureColumns(
+	ctx context.Context,
+	tx *sql.Tx,
+	d dialect,
+	tableID string,
+	columns []schemaColumn,
+) error {
+	existing, err := loadColumns(ctx, tx, d, tableID)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		index := slices.IndexFunc(existing, func(existing schemaColumn) bool {
+			return existing.name == column.name
+		})
+		if index < 0 {
+			if slices.ContainsFunc(existing, func(existing schemaColumn) bool {
+				return existing.columnName == column.columnName
+			}) {
+				return fmt.Errorf("column %q collides with another column stored as %q", column.name, column.columnName)
+			}
+			if err := addColumn(ctx, tx, d, tableID, column, len(existing)); err != nil {
+				return err
+			}
+			existing = append(existing, column)
+
+			continue
+		}
+
+		current := existing[index]
+		if current.columnType != column.columnType {
+			return fmt.Errorf("column %q has type %q, want %q", column.name, current.columnType, column.columnType)
+		}
+
+		options := slices.Clone(current.options)
+		for _, option := range column.options {
+			if !slices.Contains(options, option) {
+				options = append(options, option)
+			}
+		}
+		if len(options) == len(current.options) {
+			continue
+		}
+
+		if err := updateOptions(ctx, tx, d, tableID, current.name, options); err != nil {
+			return err
+		}
+		existing[index].options = options
+	}
+
+	return nil
+}
+
+func addColumn(
+	ctx context.Context,
+	tx *sql.Tx,
+	d dialect,
+	tableID string,
+	column schemaColumn,
+	position int,
+) error {
+	if err := ensureRowsColumn(ctx, tx, d, column); err != nil {
+		return err
+	}
+
+	options, err := json.Marshal(column.options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal options of column %q: %w", column.name, err)
+	}
+	if options == nil || string(options) == "null" {
+		options = []byte("[]")
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO `+columnsTable+
+			` (table_id, name, column_name, type, currency, options, position) VALUES (`+
+			d.placeholders(1, 7)+`)`,
+		tableID,
+		column.name,
+		column.columnName,
+		string(column.columnType),
+		string(column.currency),
+		string(options),
+		position,
+	); err != nil {
+		return fmt.Errorf("failed to add column %q: %w", column.name, err)
+	}
+
+	return nil
+}
+
+func ensureRowsColumn(ctx context.Context, tx *sql.Tx, d dialect, column schemaColumn) error {
+	var storedType string
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT type FROM `+columnsTable+` WHERE column_name = `+d.placeholder(1)+` LIMIT 1`,
+		column.columnName,
+	).Scan(&storedType)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("failed to check column %q: %w", column.name, err)
+	case storedType != string(column.columnType):
+		return fmt.Errorf(
+			"column %q is stored as %q by another table, want %q",
+			column.columnName,
+			storedType,
+			column.columnType,
+		)
+	default:
+		return nil
+	}
+
+	existing, err := rowsColumns(ctx, tx, d)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(existing, column.columnName) {
+		return nil
+	}
+
+	columnType, err := d.columnType(column.columnType)
+	if err != nil {
+		return fmt.Errorf("column %q: %w", column.name, err)
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`ALTER TABLE `+rowsTable+` ADD COLUMN `+quoteIdentifier(column.columnName)+` `+columnType,
+	); err != nil {
+		return fmt.Errorf("failed to add column %q to %s: %w", column.columnName, rowsTable, err)
+	}
+
+	return nil
+}
+
+func rowsColumns(ctx context.Context, q querier, d dialect) ([]string, error) {
+	rows, err := q.QueryContext(ctx, d.listColumnsSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns of %s: %w", rowsTable, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to scan column of %s: %w", rowsTable, err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list columns of %s: %w", rowsTable, err)
+	}
+
+	return columns, nil
+}
+
+func updateOptions(
+	ctx context.Context,
+	tx *sql.Tx,
+	d dialect,
+	tableID, name string,
+	options []string,
+) error {
+	value, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal options of column %q: %w", name, err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE `+columnsTable+` SET options = `+d.placeholder(1)+
+			` WHERE table_id = `+d.placeholder(2)+` AND name = `+d.placeholder(3),
+		string(value),
+		tableID,
+		name,
+	); err != nil {
+		return fmt.Errorf("failed to update options of column %q: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package sqlsheet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	// Register the database/sql drivers used by the supported dialects.
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+type database struct {
+	db      *sql.DB
+	dialect dialect
+
+	// mutex serializes migrations and column changes, which profiles sharing a database would
+	// otherwise race on.
+	mutex    sync.Mutex
+	migrated bool
+}
+
+// Client stores every table of every profile in one sheet_rows table, discriminated by
+// table_id, so rows of different months can be queried together. Each table belongs to the
+// profile that created it, so profiles sharing a database only see their own tables.
+type Client struct {
+	conns map[string]*database
+}
+
+func NewClient(env *config.Env) (*Client, error) {
+	databases := map[entity.SQLConnection]*database{}
+	conns := map[string]*database{}
+	for _, ingestProfile := range env.IngestProfiles {
+		if ingestProfile.SheetProviderOrDefault() != entity.SheetProviderSQL || ingestProfile.SQL == nil {
+			continue
+		}
+
+		connection := *ingestProfile.SQL
+		if existing, ok := databases[connection]; ok {
+			conns[ingestProfile.ID] = existing
+
+			continue
+		}
+
+		dialect, ok := dialects[connection.Driver]
+		if !ok {
+			return nil, fmt.Errorf(
+				"configure SQL ingest profile %q: unsupported driver %q",
+				ingestProfile.ID,
+				connection.Driver,
+			)
+		}
+
+		db, err := sql.Open(dialect.driverName, connection.DSN)
+		if err != nil {
+			return nil, fmt.Errorf("configure SQL ingest profile %q: %w", ingestProfile.ID, err)
+		}
+
+		databases[connection] = &database{db: db, dialect: dialect}
+		conns[ingestProfile.ID] = databases[connection]
+	}
+
+	return &Client{conns: conns}, nil
+}
+
+// connection returns the profile database, applying pending migrations on first use.
+func (c *Client) connection(ctx context.Context, connectionID string) (*database, error) {
+	database, ok := c.conns[connectionID]
+	if !ok {
+		return nil, errors.New("connection not found for ingest profile " + connectionID)
+	}
+
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+
+	if database.migrated {
+		return database, nil
+	}
+	if err := migrate(ctx, database.db, database.dialect); err != nil {
+		return nil, err
+	}
+	database.migrated = true
+
+	return database, nil
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (d *database) inTx(ctx context.Context, run func(*sql.Tx) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := run(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+var _ sheet.Provider = (*Client)(nil)
@@ -0,0 +1,518 @@
+package sqlsheet
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/config"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/domain/entity"
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+func TestTablesRoundTripThroughSQLite(t *testing.T) {
+	client := testClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+
+	tables, err := client.ListTables(t.Context(), "connection")
+	if err != nil || len(tables) != 0 {
+		t.Fatalf("ListTables() before create = %#v, %v", tables, err)
+	}
+
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("August/2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if table.ID == "" || table.Title != "August/2026" {
+		t.Fatalf("table = %#v", table)
+	}
+
+	tables, err = client.ListTables(t.Context(), "connection")
+	if err != nil || !reflect.DeepEqual(tables, []sheet.Table{table}) {
+		t.Fatalf("ListTables() = %#v, %v", tables, err)
+	}
+
+	rows := []sheet.Row{
+		{
+			"Name":     sheet.TitleCell("Store, Inc"),
+			"Category": sheet.SelectCell("Food"),
+			"Amount":   sheet.NumberCell(10.5),
+			"Notes":    sheet.TextCell("0042"),
+			"Date":     sheet.DateCell(time.Date(2026, time.August, 2, 15, 30, 0, 0, time.UTC)),
+			"Reviewed": sheet.CheckboxCell(true),
+		},
+		{
+			"Name":   sheet.TitleCell("Market"),
+			"Amount": sheet.NumberCell(-3),
+			"Date": sheet.DateCell(
+				time.Date(2026, time.August, 3, 9, 0, 0, 0, time.FixedZone("BRT", -3*60*60)),
+			),
+		},
+	}
+	for _, row := range rows {
+		if err := client.InsertRow(t.Context(), "connection", table.ID, row); err != nil {
+			t.Fatalf("InsertRow() error = %v", err)
+		}
+	}
+
+	got, err := client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+	want := []sheet.Record{{ID: got[0].ID, Row: rows[0]}, {ID: got[1].ID, Row: sheet.Row{
+		"Name":   sheet.TitleCell("Market"),
+		"Amount": sheet.NumberCell(-3),
+		"Notes":  sheet.TextCell(""),
+		"Date":   sheet.DateCell(time.Date(2026, time.August, 3, 12, 0, 0, 0, time.UTC)),
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows = %#v, want %#v", got, want)
+	}
+
+	err = client.EnsureTableColumns(
+		t.Context(),
+		"connection",
+		table.ID,
+		sheet.NewSelectColumn("Category").Options(sheet.NewSelectOption("Home")),
+		sheet.NewSelectColumn("Budget group").Options(sheet.NewSelectOption("Needs")),
+	)
+	if err != nil {
+		t.Fatalf("EnsureTableColumns() error = %v", err)
+	}
+
+	row := sheet.Row{"Name": sheet.TitleCell("Rent"), "Budget group": sheet.SelectCell("Needs")}
+	if err := client.InsertRow(t.Context(), "connection", table.ID, row); err != nil {
+		t.Fatalf("InsertRow() after ensure error = %v", err)
+	}
+	got, err = client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil {
+		t.Fatalf("ListRows() after ensure error = %v", err)
+	}
+	if len(got) != 3 || !reflect.DeepEqual(got[0].Row, rows[0]) ||
+		got[2].Row["Budget group"] != sheet.SelectCell("Needs") {
+		t.Fatalf("rows after ensure = %#v", got)
+	}
+
+	err = client.UpdateRow(t.Context(), "connection", table.ID, got[1].ID, sheet.Row{
+		"Amount":       sheet.NumberCell(-4),
+		"Budget group": sheet.SelectCell("Needs"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	updated, err := client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil {
+		t.Fatalf("ListRows() after update error = %v", err)
+	}
+	if updated[1].ID != got[1].ID || updated[1].Row["Name"] != sheet.TitleCell("Market") ||
+		updated[1].Row["Amount"] != sheet.NumberCell(-4) ||
+		updated[1].Row["Budget group"] != sheet.SelectCell("Needs") ||
+		!reflect.DeepEqual(updated[0], got[0]) {
+		t.Fatalf("rows after update = %#v", updated)
+	}
+
+	if err := client.DeleteRow(t.Context(), "connection", table.ID, got[0].ID); err != nil {
+		t.Fatalf("DeleteRow() error = %v", err)
+	}
+	remaining, err := client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil || !reflect.DeepEqual(remaining, updated[1:]) {
+		t.Fatalf("rows after delete = %#v, %v, want %#v", remaining, err, updated[1:])
+	}
+	if err := client.DeleteRow(t.Context(), "connection", table.ID, got[0].ID); err == nil {
+		t.Fatal("DeleteRow() of deleted row error = nil")
+	}
+
+	columns, err := loadColumns(t.Context(), client.conns["connection"].db, client.conns["connection"].dialect, table.ID)
+	if err != nil {
+		t.Fatalf("loadColumns() error = %v", err)
+	}
+	last := columns[len(columns)-1]
+	if !reflect.DeepEqual(columns[1].options, []string{"Food", "Home"}) ||
+		last.name != "Budget group" || last.columnName != "budget_group" {
+		t.Fatalf("columns = %#v", columns)
+	}
+}
+
+func TestTablesShareRowsTableAndSurviveReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sheets.db")
+	client := testClient(t, path)
+
+	january, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	february, err := client.CreateTable(t.Context(), "connection", testDefinition("Feb 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if err := client.InsertRow(
+		t.Context(),
+		"connection",
+		january.ID,
+		sheet.Row{"Name": sheet.TitleCell("Store")},
+	); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+
+	reopened := testClient(t, path)
+	tables, err := reopened.ListTables(t.Context(), "connection")
+	if err != nil || !reflect.DeepEqual(tables, []sheet.Table{february, january}) {
+		t.Fatalf("ListTables() after reopening = %#v, %v", tables, err)
+	}
+
+	rows, err := reopened.ListRows(t.Context(), "connection", february.ID)
+	if err != nil || len(rows) != 0 {
+		t.Fatalf("ListRows() of other table = %#v, %v", rows, err)
+	}
+	rows, err = reopened.ListRows(t.Context(), "connection", january.ID)
+	if err != nil || len(rows) != 1 || rows[0].Row["Name"] != sheet.TitleCell("Store") {
+		t.Fatalf("ListRows() = %#v, %v", rows, err)
+	}
+
+	var version int
+	if err := reopened.conns["connection"].db.QueryRowContext(
+		t.Context(),
+		`SELECT MAX(version) FROM `+migrationsTable,
+	).Scan(&version); err != nil || version != len(migrations) {
+		t.Fatalf("schema version = %d, %v, want %d", version, err, len(migrations))
+	}
+}
+
+func TestListRowsSkipsMalformedRecords(t *testing.T) {
+	client := testClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	for _, statement := range []string{
+		`INSERT INTO sheet_rows (table_id, name, amount) VALUES (?, 'Store', 10)`,
+		`INSERT INTO sheet_rows (table_id, name, amount) VALUES (?, 'Broken', 'ten')`,
+		`INSERT INTO sheet_rows (table_id, name, date) VALUES (?, 'Late', 'not a date')`,
+	} {
+		if _, err := client.conns["connection"].db.ExecContext(t.Context(), statement, table.ID); err != nil {
+			t.Fatalf("insert raw row: %v", err)
+		}
+	}
+
+	rows, err := client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Row["Name"] != sheet.TitleCell("Store") ||
+		rows[0].Row["Amount"] != sheet.NumberCell(10) {
+		t.Fatalf("rows = %#v, want only the Store row", rows)
+	}
+}
+
+func TestInsertRowRejectsInvalidCells(t *testing.T) {
+	client := testClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	tests := map[string]sheet.Row{
+		"unknown column":  {"Unknown": sheet.TextCell("value")},
+		"mismatched type": {"Amount": sheet.TextCell("10")},
+		"nil cell":        {"Name": nil},
+	}
+	for name, row := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := client.InsertRow(t.Context(), "connection", table.ID, row); err == nil {
+				t.Fatal("InsertRow() error = nil, want invalid row error")
+			}
+		})
+	}
+}
+
+func TestInsertRowsStoresValidRowsInOneTransaction(t *testing.T) {
+	client := testClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	err = client.InsertRows(t.Context(), "connection", table.ID, []sheet.Row{
+		{"Name": sheet.TitleCell("Store"), "Amount": sheet.NumberCell(10)},
+		{"Amount": sheet.TextCell("10")},
+		{"Name": sheet.TitleCell("Market")},
+	})
+	if failed := sheet.FailedRows(err, 3); len(failed) != 1 || failed[0].Index != 1 {
+		t.Fatalf("InsertRows() error = %v, want only row 1 to fail", err)
+	}
+
+	rows, err := client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil {
+		t.Fatalf("ListRows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Row["Name"] != sheet.TitleCell("Store") ||
+		rows[1].Row["Name"] != sheet.TitleCell("Market") {
+		t.Fatalf("rows = %#v", rows)
+	}
+
+	if err := client.InsertRows(t.Context(), "connection", "missing", []sheet.Row{{}}); err == nil {
+		t.Fatal("InsertRows() into missing table error = nil")
+	}
+}
+
+func TestUpdateAndDeleteRowRejectInvalidRows(t *testing.T) {
+	client := testClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	other, err := client.CreateTable(t.Context(), "connection", testDefinition("Feb 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if err := client.InsertRow(
+		t.Context(),
+		"connection",
+		table.ID,
+		sheet.Row{"Name": sheet.TitleCell("Store")},
+	); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	records, err := client.ListRows(t.Context(), "connection", table.ID)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("ListRows() = %#v, %v", records, err)
+	}
+	rowID := records[0].ID
+
+	tests := map[string]struct {
+		tableID string
+		rowID   string
+		row     sheet.Row
+	}{
+		"invalid row id":     {tableID: table.ID, rowID: "row", row: sheet.Row{"Amount": sheet.NumberCell(1)}},
+		"missing row":        {tableID: table.ID, rowID: "999", row: sheet.Row{"Amount": sheet.NumberCell(1)}},
+		"row of other table": {tableID: other.ID, rowID: rowID, row: sheet.Row{"Amount": sheet.NumberCell(1)}},
+		"missing table":      {tableID: "missing", rowID: rowID, row: sheet.Row{"Amount": sheet.NumberCell(1)}},
+		"unknown column":     {tableID: table.ID, rowID: rowID, row: sheet.Row{"Unknown": sheet.TextCell("value")}},
+		"mismatched type":    {tableID: table.ID, rowID: rowID, row: sheet.Row{"Amount": sheet.TextCell("10")}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := client.UpdateRow(t.Context(), "connection", test.tableID, test.rowID, test.row); err == nil {
+				t.Fatal("UpdateRow() error = nil, want invalid row error")
+			}
+		})
+	}
+
+	for _, test := range []struct{ tableID, rowID string }{
+		{tableID: table.ID, rowID: "row"},
+		{tableID: table.ID, rowID: "999"},
+		{tableID: other.ID, rowID: rowID},
+		{tableID: "missing", rowID: rowID},
+	} {
+		if err := client.DeleteRow(t.Context(), "connection", test.tableID, test.rowID); err == nil {
+			t.Fatalf("DeleteRow(%q, %q) error = nil, want invalid row error", test.tableID, test.rowID)
+		}
+	}
+}
+
+func TestEnsureTableColumnsRejectsIncompatibleColumns(t *testing.T) {
+	client := testClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+	table, err := client.CreateTable(t.Context(), "connection", testDefinition("Jan 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	other, err := client.CreateTable(
+		t.Context(),
+		"connection",
+		sheet.NewTable("Feb 2026").AddColumn(sheet.NewTitleColumn("Name")),
+	)
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		tableID string
+		column  sheet.Column
+	}{
+		"same table type":    {tableID: table.ID, column: sheet.NewSelectColumn("Amount")},
+		"same physical name": {tableID: table.ID, column: sheet.NewTextColumn("amount")},
+		"other table type":   {tableID: other.ID, column: sheet.NewTextColumn("Amount")},
+		"missing table":      {tableID: "missing", column: sheet.NewTextColumn("Notes")},
+		"unsupported currency": {
+			tableID: table.ID,
+			column:  sheet.NewNumberColumn("Total").Currency(sheet.Currency("USD")),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := client.EnsureTableColumns(t.Context(), "connection", test.tableID, test.column); err == nil {
+				t.Fatal("EnsureTableColumns() error = nil, want incompatible column error")
+			}
+		})
+	}
+}
+
+func TestOperationsRejectMissingConnection(t *testing.T) {
+	client := testClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+	if _, err := client.ListTables(t.Context(), "missing"); err == nil ||
+		!strings.Contains(err.Error(), "connection not found") {
+		t.Fatalf("ListTables() error = %v, want missing connection", err)
+	}
+}
+
+func TestNewClientSharesDatabasesBetweenProfiles(t *testing.T) {
+	connection := &entity.SQLConnection{
+		Driver: entity.SQLDriverSQLite,
+		DSN:    filepath.Join(t.TempDir(), "sheets.db"),
+	}
+	client, err := NewClient(&config.Env{IngestProfilesFileData: config.IngestProfilesFileData{
+		IngestProfiles: []entity.IngestProfile{
+			{ID: "notion"},
+			{ID: "first", SheetProvider: entity.SheetProviderSQL, SQL: connection},
+			{ID: "second", SheetProvider: entity.SheetProviderSQL, SQL: connection},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.conns["first"].db.Close()
+	})
+
+	if len(client.conns) != 2 || client.conns["first"] != client.conns["second"] {
+		t.Fatalf("conns = %#v, want one shared database", client.conns)
+	}
+}
+
+func TestProfilesSharingADatabaseOnlySeeTheirTables(t *testing.T) {
+	client := sharedTestClient(t, filepath.Join(t.TempDir(), "sheets.db"))
+
+	table, err := client.CreateTable(t.Context(), "first", testDefinition("Aug 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	if err := client.InsertRow(t.Context(), "first", table.ID, sheet.Row{"Name": sheet.TitleCell("Store")}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+
+	tables, err := client.ListTables(t.Context(), "second")
+	if err != nil || len(tables) != 0 {
+		t.Fatalf("ListTables() of other profile = %#v, %v", tables, err)
+	}
+	if _, err := client.ListRows(t.Context(), "second", table.ID); err == nil {
+		t.Fatal("ListRows() of other profile table error = nil, want not found")
+	}
+	rows, err := client.ListRows(t.Context(), "first", table.ID)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("ListRows() = %#v, %v", rows, err)
+	}
+	if err := client.DeleteRow(t.Context(), "second", table.ID, rows[0].ID); err == nil {
+		t.Fatal("DeleteRow() of other profile table error = nil, want not found")
+	}
+	if err := client.UpdateRow(
+		t.Context(),
+		"second",
+		table.ID,
+		rows[0].ID,
+		sheet.Row{"Name": sheet.TitleCell("Other")},
+	); err == nil {
+		t.Fatal("UpdateRow() of other profile table error = nil, want not found")
+	}
+
+	other, err := client.CreateTable(t.Context(), "second", testDefinition("Aug 2026"))
+	if err != nil {
+		t.Fatalf("CreateTable() of other profile error = %v", err)
+	}
+	tables, err = client.ListTables(t.Context(), "first")
+	if err != nil || !reflect.DeepEqual(tables, []sheet.Table{table}) {
+		t.Fatalf("ListTables() = %#v, %v", tables, err)
+	}
+	tables, err = client.ListTables(t.Context(), "second")
+	if err != nil || !reflect.DeepEqual(tables, []sheet.Table{other}) {
+		t.Fatalf("ListTables() of other profile = %#v, %v", tables, err)
+	}
+	rows, err = client.ListRows(t.Context(), "first", table.ID)
+	if err != nil || len(rows) != 1 || rows[0].Row["Name"] != sheet.TitleCell("Store") {
+		t.Fatalf("ListRows() after other profile changes = %#v, %v", rows, err)
+	}
+}
+
+func TestPhysicalColumnName(t *testing.T) {
+	tests := map[string]string{
+		"Name":               "name",
+		"Grupo do orçamento": "grupo_do_orcamento",
+		"  Amount (R$) ":     "amount_r",
+		"ID":                 "c_id",
+		"2nd category":       "c_2nd_category",
+		"💸":                  "c_",
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := physicalColumnName(name); got != want {
+				t.Fatalf("physicalColumnName(%q) = %q, want %q", name, got, want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect(t *testing.T) {
+	d := dialects[entity.SQLDriverPostgres]
+	if got := d.placeholders(2, 3); got != "$2, $3, $4" {
+		t.Fatalf("placeholders() = %q", got)
+	}
+
+	columnType, err := d.columnType(sheet.ColumnTypeDate)
+	if err != nil || columnType != "TIMESTAMPTZ" {
+		t.Fatalf("columnType(date) = %q, %v", columnType, err)
+	}
+}
+
+func testDefinition(title string) sheet.TableDefinition {
+	return sheet.NewTable(title).
+		SetIcon("💸").
+		AddColumn(sheet.NewTitleColumn("Name")).
+		AddColumn(sheet.NewSelectColumn("Category").Options(sheet.NewSelectOption("Food").Color("red"))).
+		AddColumn(sheet.NewNumberColumn("Amount").Currency(sheet.Currency("BRL"))).
+		AddColumn(sheet.NewTextColumn("Notes")).
+		AddColumn(sheet.NewDateColumn("Date")).
+		AddColumn(sheet.NewCheckboxColumn("Reviewed"))
+}
+
+func testClient(t *testing.T, path string) *Client {
+	t.Helper()
+
+	client, err := NewClient(&config.Env{IngestProfilesFileData: config.IngestProfilesFileData{
+		IngestProfiles: []entity.IngestProfile{{
+			ID:            "connection",
+			SheetProvider: entity.SheetProviderSQL,
+			SQL:           &entity.SQLConnection{Driver: entity.SQLDriverSQLite, DSN: path},
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.conns["connection"].db.Close()
+	})
+
+	return client
+}
+
+// sharedTestClient returns a client whose profiles "first" and "second" share one database.
+func sharedTestClient(t *testing.T, path string) *Client {
+	t.Helper()
+
+	connection := &entity.SQLConnection{Driver: entity.SQLDriverSQLite, DSN: path}
+	client, err := NewClient(&config.Env{IngestProfilesFileData: config.IngestProfilesFileData{
+		IngestProfiles: []entity.IngestProfile{
+			{ID: "first", SheetProvider: entity.SheetProviderSQL, SQL: connection},
+			{ID: "second", SheetProvider: entity.SheetProviderSQL, SQL: connection},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.conns["first"].db.Close()
+	})
+
+	return client
+}
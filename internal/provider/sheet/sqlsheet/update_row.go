@@ -0,0 +1,85 @@
+package sqlsheet
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielmesquitta/openfinance-to-sheets/internal/provider/sheet"
+)
+
+// UpdateRow sets the typed columns of the cells the row carries on the sheet_rows entry with the
+// given id, leaving its other columns unchanged.
+func (c *Client) UpdateRow(
+	ctx context.Context,
+	connectionID, tableID, rowID string,
+	row sheet.Row,
+) error {
+	database, err := c.connection(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(rowID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid row id %q: %w", rowID, err)
+	}
+
+	d := database.dialect
+	if err := tableExists(ctx, database.db, d, connectionID, tableID); err != nil {
+		return err
+	}
+	columns, err := loadColumns(ctx, database.db, d, tableID)
+	if err != nil {
+		return err
+	}
+
+	query, values, err := rowUpdate(d, columns, row)
+	if err != nil {
+		return fmt.Errorf("invalid row: %w", err)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	query += ` WHERE id = ` + d.placeholder(len(values)+1) + ` AND table_id = ` + d.placeholder(len(values)+2)
+	result, err := database.db.ExecContext(ctx, query, append(values, id, tableID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update row %q of table %q: %w", rowID, tableID, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update row %q of table %q: %w", rowID, tableID, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("row %q not found in table %q", rowID, tableID)
+	}
+
+	return nil
+}
+
+// rowUpdate builds the SET clause of an UPDATE binding only the columns the row sets. The caller
+// appends the WHERE clause after the returned values.
+func rowUpdate(d dialect, columns []schemaColumn, row sheet.Row) (string, []any, error) {
+	assignments := make([]string, 0, len(row))
+	values := make([]any, 0, len(row))
+	for name, cell := range row {
+		index := slices.IndexFunc(columns, func(column schemaColumn) bool {
+			return column.name == name
+		})
+		if index < 0 {
+			return "", nil, fmt.Errorf("column %q not found", name)
+		}
+
+		value, err := cellValue(columns[index].columnType, cell)
+		if err != nil {
+			return "", nil, fmt.Errorf("column %q: %w", name, err)
+		}
+		values = append(values, value)
+		assignments = append(assignments, quoteIdentifier(columns[index].columnName)+` = `+d.placeholder(len(values)))
+	}
+
+	return `UPDATE ` + rowsTable + ` SET ` + strings.Join(assignments, ", "), values, nil
+}